@@ -0,0 +1,232 @@
+package breaketernity
+
+import (
+	"iter"
+	"math"
+	"slices"
+)
+
+// Sum returns the sum of all values, or 0 if there are none.
+// Unlike adding the values one by one with Add, small terms are not lost once the total leaves layer 0,
+// and the result does not depend on the order of the values. Terms that cancel exactly, like 1e400 and -1e400,
+// drop out so the smaller terms still count. Terms that nearly cancel leave an error relative to the largest term:
+// about 1e-16 of it on layer 0, and mag * 5e-16 of it on layer 1, where e.g. 2e400 - 1e400 - 1e400 isn't exactly 0.
+func Sum[DS DecimalSource](values ...DS) *Decimal {
+	return sumDecimals(decimalsFromSources(values))
+}
+
+// SumSeq returns the sum of all values yielded by seq, with the same guarantees as Sum.
+func SumSeq(seq iter.Seq[*Decimal]) *Decimal {
+	return sumDecimals(slices.Collect(seq))
+}
+
+// Product returns the product of all values, or 1 if there are none.
+// The result does not depend on the order of the values.
+func Product[DS DecimalSource](values ...DS) *Decimal {
+	return productDecimals(decimalsFromSources(values))
+}
+
+// ProductSeq returns the product of all values yielded by seq, with the same guarantees as Product.
+func ProductSeq(seq iter.Seq[*Decimal]) *Decimal {
+	return productDecimals(slices.Collect(seq))
+}
+
+// Mean returns the arithmetic mean of all values, or NaN if there are none.
+func Mean[DS DecimalSource](values ...DS) *Decimal {
+	if len(values) == 0 {
+		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	}
+	return Sum(values...).Divide(decimalFromFloat64(float64(len(values))))
+}
+
+// GeometricMean returns the geometric mean of all values, or NaN if there are none or any of them is negative.
+func GeometricMean[DS DecimalSource](values ...DS) *Decimal {
+	decimals := decimalsFromSources(values)
+	if len(decimals) == 0 {
+		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	}
+	zero := false
+	logs := make([]*Decimal, 0, len(decimals))
+	for _, d := range decimals {
+		if d.IsNaN() || d.sign < 0 {
+			return dFC_NN(math.NaN(), math.NaN(), math.NaN())
+		}
+		if d.sign == 0 {
+			zero = true
+			continue
+		}
+		logs = append(logs, d.AbsLog10())
+	}
+	if zero {
+		return dFC_NN(0, 0, 0)
+	}
+	return sumDecimals(logs).Divide(decimalFromFloat64(float64(len(decimals)))).PowBase10()
+}
+
+func decimalsFromSources[DS DecimalSource](values []DS) []*Decimal {
+	decimals := make([]*Decimal, len(values))
+	for i, v := range values {
+		decimals[i] = D(v)
+	}
+	return decimals
+}
+
+// SUM_BAND is the smallest ratio of a term to the largest one that Sum adds in the same float64 sum.
+// It leaves room below it for the rounding error of the sum, which is kept as a compensation term.
+const SUM_BAND float64 = 1e-290
+
+// sumDecimals adds the values in layer 0 when they all fit, and otherwise scales every value by the largest one
+// (a log-sum-exp) so that the sum is taken over float64 ratios in [-1, 1], with sumScaled.
+// Both sums are compensated and taken over values sorted by magnitude, so the result is independent of the order.
+func sumDecimals(values []*Decimal) *Decimal {
+	var posInf, negInf bool
+	layer0 := true
+	for _, d := range values {
+		if d.IsNaN() {
+			return dFC_NN(math.NaN(), math.NaN(), math.NaN())
+		}
+		if d.IsInf() {
+			if d.sign > 0 {
+				posInf = true
+			} else {
+				negInf = true
+			}
+		}
+		if d.layer != 0 {
+			layer0 = false
+		}
+	}
+	if posInf && negInf {
+		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	} else if posInf {
+		return dFC_NN(1, math.Inf(1), math.Inf(1))
+	} else if negInf {
+		return dFC_NN(-1, math.Inf(1), math.Inf(1))
+	}
+
+	if layer0 {
+		terms := make([]float64, len(values))
+		for i, d := range values {
+			terms[i] = d.sign * d.mag
+		}
+		if sum := neumaierSum(terms); !math.IsInf(sum, 0) {
+			return decimalFromFloat64(sum)
+		}
+	}
+
+	sorted := slices.Clone(values)
+	slices.SortFunc(sorted, cmpAbsDescending)
+	return sumScaled(sorted)
+}
+
+// sumScaled sums values sorted by decreasing magnitude. The terms within SUM_BAND of the largest one are summed
+// as ratios to it. If they cancel out exactly, the smaller terms are summed the same way, since they are all
+// that is left; otherwise the smaller terms are below the rounding error of the result and are dropped.
+func sumScaled(values []*Decimal) *Decimal {
+	if len(values) == 0 || values[0].sign == 0 {
+		return dFC_NN(0, 0, 0)
+	}
+	scale := values[0].Abs()
+	ratios := make([]float64, 0, len(values))
+	for _, d := range values {
+		ratio := d.Divide(scale).ToFloat64()
+		if math.Abs(ratio) < SUM_BAND {
+			break
+		}
+		ratios = append(ratios, ratio)
+	}
+	if sum := neumaierSum(ratios); sum != 0 {
+		return scale.Multiply(decimalFromFloat64(sum))
+	}
+	return sumScaled(values[len(ratios):])
+}
+
+// neumaierSum returns the compensated sum of the terms, taken in order of increasing magnitude.
+// The terms are sorted in place.
+func neumaierSum(terms []float64) float64 {
+	slices.SortFunc(terms, func(a, b float64) int {
+		if c := cmpFloat64(math.Abs(a), math.Abs(b)); c != 0 {
+			return c
+		}
+		return cmpFloat64(a, b)
+	})
+	sum, compensation := 0., 0.
+	for _, t := range terms {
+		next := sum + t
+		if math.Abs(sum) >= math.Abs(t) {
+			compensation += (sum - next) + t
+		} else {
+			compensation += (t - next) + sum
+		}
+		sum = next
+	}
+	return sum + compensation
+}
+
+func cmpAbsDescending(a *Decimal, b *Decimal) int {
+	return b.CmpAbs(a)
+}
+
+func cmpFloat64(a, b float64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// productDecimals multiplies the values in layer 0 with a separate binary exponent so that the running product
+// can't overflow, and otherwise adds up their logarithms with sumDecimals.
+func productDecimals(values []*Decimal) *Decimal {
+	resultSign := 1.
+	zero, inf, layer0 := false, false, true
+	for _, d := range values {
+		if d.IsNaN() {
+			return dFC_NN(math.NaN(), math.NaN(), math.NaN())
+		}
+		if d.sign == 0 {
+			zero = true
+		} else if d.IsInf() {
+			inf = true
+		}
+		if d.sign < 0 {
+			resultSign = -resultSign
+		}
+		if d.layer != 0 {
+			layer0 = false
+		}
+	}
+	if zero && inf {
+		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	} else if zero {
+		return dFC_NN(0, 0, 0)
+	} else if inf {
+		return dFC_NN(resultSign, math.Inf(1), math.Inf(1))
+	}
+
+	if layer0 {
+		mags := make([]float64, len(values))
+		for i, d := range values {
+			mags[i] = d.mag
+		}
+		slices.Sort(mags)
+		mant, exp := 1., 0
+		for _, m := range mags {
+			frac, e := math.Frexp(mant * m)
+			mant, exp = frac, exp+e
+		}
+		if exp < 1024 && exp > -1022 {
+			return decimalFromFloat64(resultSign * math.Ldexp(mant, exp))
+		}
+		return dFC(resultSign, 1, math.Log10(mant)+float64(exp)*math.Log10(2))
+	}
+
+	logs := make([]*Decimal, len(values))
+	for i, d := range values {
+		logs[i] = d.AbsLog10()
+	}
+	result := sumDecimals(logs).PowBase10()
+	result.sign *= resultSign
+	return result
+}
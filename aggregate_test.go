@@ -0,0 +1,123 @@
+package breaketernity
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestSum(t *testing.T) {
+	cases := []struct {
+		values []string
+		want   string
+	}{
+		{nil, "0"},
+		{[]string{"1", "2", "3"}, "6"},
+		{[]string{"1e400", "-1e400", "1"}, "1"},
+		{[]string{"ee20", "1e400", "-ee20", "-1e400", "5", "-2"}, "3"},
+		{[]string{"1e400", "-1e400", "1e200", "-1e200", "0.5"}, "0.5"},
+		{[]string{"1e400", "-1e400"}, "0"},
+		{[]string{"1e400", "1e400"}, "2e400"},
+		{[]string{"1e308", "1e308", "-1e308"}, "1e308"},
+		{[]string{"ee20", "1"}, "ee20"},
+	}
+	for _, c := range cases {
+		want := D(c.want)
+		if got := Sum(c.values...); !got.EqTolerance(want, 1e-14) {
+			t.Errorf("Sum(%v) = %s, want %s", c.values, got.ToString(), want.ToString())
+		}
+	}
+	if got := Sum(math.Inf(1), 1e300); !got.Eq(D(math.Inf(1))) {
+		t.Errorf("Sum(Infinity, 1e300) = %s, want Infinity", got.ToString())
+	}
+	for _, values := range [][]float64{{math.Inf(1), math.Inf(-1)}, {1, math.NaN()}} {
+		if got := Sum(values...); !got.IsNaN() {
+			t.Errorf("Sum(%v) = %s, want NaN", values, got.ToString())
+		}
+	}
+}
+
+func TestSumNearCancellation(t *testing.T) {
+	// 2e400 is stored as 10^400.30102999566395, so it only cancels 1e400 + 1e400 to the precision of its mag
+	got := Sum("2e400", "-1e400", "-1e400", "5")
+	if limit := D("2e400").Multiply(D(400 * math.Ln10 * 0x1p-52)); got.Abs().Gt(limit) {
+		t.Errorf("Sum(2e400, -1e400, -1e400, 5) = %s, want at most %s", got.ToString(), limit.ToString())
+	}
+}
+
+func TestSumIsOrderIndependent(t *testing.T) {
+	values := []*Decimal{D("1e400"), D(1), D("-1e400"), D("3e399"), D(1e-10), D("-3e399"), D(7), D("ee20"), D("-ee20")}
+	want := Sum(values...)
+	if !want.Eq(D(8.0000000001)) {
+		t.Errorf("Sum = %s, want 8.0000000001", want.ToString())
+	}
+	for i := range values {
+		rotated := append(slices.Clone(values[i:]), values[:i]...)
+		slices.Reverse(rotated)
+		if got := Sum(rotated...); !got.Eq(want) {
+			t.Errorf("Sum of rotation %d = %s, want %s", i, got.ToString(), want.ToString())
+		}
+		if got := SumSeq(slices.Values(rotated)); !got.Eq(want) {
+			t.Errorf("SumSeq of rotation %d = %s, want %s", i, got.ToString(), want.ToString())
+		}
+	}
+}
+
+func TestProduct(t *testing.T) {
+	cases := []struct {
+		values []string
+		want   string
+	}{
+		{nil, "1"},
+		{[]string{"2", "3", "-4"}, "-24"},
+		{[]string{"1e200", "1e200", "1e-150"}, "1e250"},
+		{[]string{"1e-200", "1e-200"}, "1e-400"},
+		{[]string{"1e400", "-1e-400", "2"}, "-2"},
+		{[]string{"ee20", "ee20"}, "e2e20"},
+		{[]string{"0", "1e400"}, "0"},
+	}
+	for _, c := range cases {
+		want := D(c.want)
+		if got := Product(c.values...); !got.EqTolerance(want, 1e-14) {
+			t.Errorf("Product(%v) = %s, want %s", c.values, got.ToString(), want.ToString())
+		}
+		decimals := decimalsFromSources(c.values)
+		if got := ProductSeq(slices.Values(decimals)); !got.EqTolerance(want, 1e-14) {
+			t.Errorf("ProductSeq(%v) = %s, want %s", c.values, got.ToString(), want.ToString())
+		}
+	}
+	if got := Product(math.Inf(-1), 2); !got.Eq(D(math.Inf(-1))) {
+		t.Errorf("Product(-Infinity, 2) = %s, want -Infinity", got.ToString())
+	}
+	if got := Product(0, math.Inf(1)); !got.IsNaN() {
+		t.Errorf("Product(0, Infinity) = %s, want NaN", got.ToString())
+	}
+}
+
+func TestMeans(t *testing.T) {
+	cases := []struct {
+		values          []string
+		mean, geometric string
+	}{
+		{[]string{"1", "2", "3", "6"}, "3", "2.449489742783178"},
+		{[]string{"1e400", "1e200"}, "5e399", "1e300"},
+		{[]string{"1e-300", "1e300"}, "5e299", "1"},
+		{[]string{"0", "4"}, "2", "0"},
+	}
+	for _, c := range cases {
+		if got, want := Mean(c.values...), D(c.mean); !got.EqTolerance(want, 1e-14) {
+			t.Errorf("Mean(%v) = %s, want %s", c.values, got.ToString(), want.ToString())
+		}
+		if got, want := GeometricMean(c.values...), D(c.geometric); !got.EqTolerance(want, 1e-14) {
+			t.Errorf("GeometricMean(%v) = %s, want %s", c.values, got.ToString(), want.ToString())
+		}
+	}
+	for _, got := range []*Decimal{Mean[string](), GeometricMean[string](), GeometricMean("4", "-1")} {
+		if !got.IsNaN() {
+			t.Errorf("got %s, want NaN", got.ToString())
+		}
+	}
+	if got := Mean(math.Inf(1), 1); !got.Eq(D(math.Inf(1))) {
+		t.Errorf("Mean(Infinity, 1) = %s, want Infinity", got.ToString())
+	}
+}
@@ -0,0 +1,144 @@
+package breaketernity
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// Benchmarks of every method of math.go, plus parsing and printing, on each class of input. Run with
+//
+//	go test -run '^$' -bench BenchmarkOperations -count 5 > new.txt
+//
+// and compare two runs with go run ./cmd/benchcmp old.txt new.txt.
+
+// benchInputs are representative values of each region of the number line, each with a different value of the same
+// layer as the other operand of binary operations, so that they don't take the shortcuts for equal operands
+var benchInputs = []struct {
+	name  string
+	value *Decimal
+	other *Decimal
+}{
+	{"layer0", D(3.7), D(-1.9)},
+	{"layer1", D("1e400"), D("-3.2e399")},
+	{"layer2", D("1e1e20"), D("1e3e19")},
+	{"layer3", D("eee20"), D("eee19.5")},
+	{"negexp", D("1e-400"), D("-3e-380")},
+	{"inf", D(math.Inf(1)), D(math.Inf(-1))},
+}
+
+// Results are stored in sinks so the compiler cannot drop the calls, and so results of
+// non-pointer types are not boxed into an interface, which would show up as allocations
+var (
+	benchDecimal *Decimal
+	benchBool    bool
+	benchInt     int
+	benchFloat   float64
+	benchString  string
+)
+
+// benchOps calls each operation on x, with y as the other operand of comparisons and arithmetic
+var benchOps = map[string]func(x *Decimal, y *Decimal){
+	"D":         func(x *Decimal, y *Decimal) { benchDecimal = D(x) },
+	"ToFloat64": func(x *Decimal, y *Decimal) { benchFloat = x.ToFloat64() },
+	"ToString":  func(x *Decimal, y *Decimal) { benchString = x.ToString() },
+
+	"Cmp":          func(x *Decimal, y *Decimal) { benchInt = x.Cmp(y) },
+	"CmpAbs":       func(x *Decimal, y *Decimal) { benchInt = x.CmpAbs(y) },
+	"Eq":           func(x *Decimal, y *Decimal) { benchBool = x.Eq(y) },
+	"Neq":          func(x *Decimal, y *Decimal) { benchBool = x.Neq(y) },
+	"Lt":           func(x *Decimal, y *Decimal) { benchBool = x.Lt(y) },
+	"Lte":          func(x *Decimal, y *Decimal) { benchBool = x.Lte(y) },
+	"Gt":           func(x *Decimal, y *Decimal) { benchBool = x.Gt(y) },
+	"Gte":          func(x *Decimal, y *Decimal) { benchBool = x.Gte(y) },
+	"Max":          func(x *Decimal, y *Decimal) { benchDecimal = x.Max(y) },
+	"Min":          func(x *Decimal, y *Decimal) { benchDecimal = x.Min(y) },
+	"MaxAbs":       func(x *Decimal, y *Decimal) { benchDecimal = x.MaxAbs(y) },
+	"MinAbs":       func(x *Decimal, y *Decimal) { benchDecimal = x.MinAbs(y) },
+	"Clamp":        func(x *Decimal, y *Decimal) { benchDecimal = x.Clamp(dNegOne, dOne) },
+	"ClampMin":     func(x *Decimal, y *Decimal) { benchDecimal = x.ClampMin(dOne) },
+	"ClampMax":     func(x *Decimal, y *Decimal) { benchDecimal = x.ClampMax(dOne) },
+	"EqTolerance":  func(x *Decimal, y *Decimal) { benchBool = x.EqTolerance(y, 1e-7) },
+	"CmpTolerance": func(x *Decimal, y *Decimal) { benchInt = x.CmpTolerance(y, 1e-7) },
+	"NeqTolerance": func(x *Decimal, y *Decimal) { benchBool = x.NeqTolerance(y, 1e-7) },
+	"LtTolerance":  func(x *Decimal, y *Decimal) { benchBool = x.LtTolerance(y, 1e-7) },
+	"LteTolerance": func(x *Decimal, y *Decimal) { benchBool = x.LteTolerance(y, 1e-7) },
+	"GtTolerance":  func(x *Decimal, y *Decimal) { benchBool = x.GtTolerance(y, 1e-7) },
+	"GteTolerance": func(x *Decimal, y *Decimal) { benchBool = x.GteTolerance(y, 1e-7) },
+	"IsNaN":        func(x *Decimal, y *Decimal) { benchBool = x.IsNaN() },
+	"IsInf":        func(x *Decimal, y *Decimal) { benchBool = x.IsInf() },
+
+	"Abs":   func(x *Decimal, y *Decimal) { benchDecimal = x.Abs() },
+	"Neg":   func(x *Decimal, y *Decimal) { benchDecimal = x.Neg() },
+	"Round": func(x *Decimal, y *Decimal) { benchDecimal = x.Round() },
+	"Floor": func(x *Decimal, y *Decimal) { benchDecimal = x.Floor() },
+	"Ceil":  func(x *Decimal, y *Decimal) { benchDecimal = x.Ceil() },
+	"Trunc": func(x *Decimal, y *Decimal) { benchDecimal = x.Trunc() },
+
+	"Add":      func(x *Decimal, y *Decimal) { benchDecimal = x.Add(y) },
+	"Subtract": func(x *Decimal, y *Decimal) { benchDecimal = x.Subtract(y) },
+	"Multiply": func(x *Decimal, y *Decimal) { benchDecimal = x.Multiply(y) },
+	"Divide":   func(x *Decimal, y *Decimal) { benchDecimal = x.Divide(y) },
+	"Recip":    func(x *Decimal, y *Decimal) { benchDecimal = x.Recip() },
+	"Modulo":   func(x *Decimal, y *Decimal) { benchDecimal = x.Modulo(D(7)) },
+
+	"PLog10":    func(x *Decimal, y *Decimal) { benchDecimal = x.PLog10() },
+	"AbsLog10":  func(x *Decimal, y *Decimal) { benchDecimal = x.AbsLog10() },
+	"Log10":     func(x *Decimal, y *Decimal) { benchDecimal = x.Log10() },
+	"Log":       func(x *Decimal, y *Decimal) { benchDecimal = x.Log(D(2)) },
+	"Ln":        func(x *Decimal, y *Decimal) { benchDecimal = x.Ln() },
+	"Log2":      func(x *Decimal, y *Decimal) { benchDecimal = x.Log2() },
+	"Pow":       func(x *Decimal, y *Decimal) { benchDecimal = x.Pow(D(1.5)) },
+	"PowBase10": func(x *Decimal, y *Decimal) { benchDecimal = x.PowBase10() },
+	"PowBaseE":  func(x *Decimal, y *Decimal) { benchDecimal = x.PowBaseE() },
+	"PowBaseN":  func(x *Decimal, y *Decimal) { benchDecimal = x.PowBaseN(D(2)) },
+	"Root":      func(x *Decimal, y *Decimal) { benchDecimal = x.Root(D(3)) },
+	"Sqrt":      func(x *Decimal, y *Decimal) { benchDecimal = x.Sqrt() },
+	"Gamma":     func(x *Decimal, y *Decimal) { benchDecimal = x.Gamma() },
+	"Factorial": func(x *Decimal, y *Decimal) { benchDecimal = x.Factorial() },
+
+	"LambertW":    func(x *Decimal, y *Decimal) { benchDecimal = x.LambertW(true) },
+	"IteratedLog": func(x *Decimal, y *Decimal) { benchDecimal = x.IteratedLog(D(10), 2, false) },
+	"IteratedExp": func(x *Decimal, y *Decimal) { benchDecimal = x.IteratedExp(2, dOne, false) },
+	"LayerAdd10":  func(x *Decimal, y *Decimal) { benchDecimal = x.LayerAdd10(D(2), false) },
+	"LayerAdd":    func(x *Decimal, y *Decimal) { benchDecimal = x.LayerAdd(D(2), D(10), false) },
+	"Slog":        func(x *Decimal, y *Decimal) { benchDecimal = x.Slog(D(10), DEFAULT_SLOG_ITERATIONS, false) },
+	"Tetrate":     func(x *Decimal, y *Decimal) { benchDecimal = x.Tetrate(3, dOne, false) },
+	"Pentate":     func(x *Decimal, y *Decimal) { benchDecimal = x.Pentate(2, dOne, false) },
+}
+
+// BenchmarkOperations runs as BenchmarkOperations/<operation>/<input>, e.g. BenchmarkOperations/Tetrate/layer3
+func BenchmarkOperations(b *testing.B) {
+	names := make([]string, 0, len(benchOps))
+	for name := range benchOps {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		op := benchOps[name]
+		b.Run(name, func(b *testing.B) {
+			for _, input := range benchInputs {
+				b.Run(input.name, func(b *testing.B) {
+					b.ReportAllocs()
+					for n := 0; n < b.N; n++ {
+						op(input.value, input.other)
+					}
+				})
+			}
+		})
+	}
+}
+
+// TestBenchmarkCoverage checks that every exported method of math.go is benchmarked
+func TestBenchmarkCoverage(t *testing.T) {
+	for _, name := range mathMethods(t) {
+		if _, ok := benchOps[name]; !ok {
+			t.Errorf("%s has no entry in benchOps", name)
+		}
+	}
+	for _, input := range benchInputs {
+		if input.other.layer != input.value.layer || input.other.Eq(input.value) {
+			t.Errorf("benchInputs %s pairs %s with %s, want a different value of the same layer", input.name, input.value.ToString(), input.other.ToString())
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package breaketernity
+
+import (
+	"errors"
+	"math"
+	"math/big"
+)
+
+// MAX_BIG_INT_DIGITS is the largest number of decimal digits ToBigInt will materialize.
+const MAX_BIG_INT_DIGITS float64 = 1e6
+
+var ErrNaN = errors.New("breaketernity: cannot convert NaN")
+
+var ErrTooLarge = errors.New("breaketernity: value is too large to materialize")
+
+// FromBigInt creates a new Decimal from a big.Int.
+// Values that fit in a float64 are rounded to the nearest float64, larger values are stored in layer 1.
+// A nil x is 0, like the zero big.Int.
+func FromBigInt(x *big.Int) *Decimal {
+	if x == nil {
+		return dFC_NN(0, 0, 0)
+	}
+	return FromBigFloat(new(big.Float).SetInt(x))
+}
+
+// FromBigFloat creates a new Decimal from a big.Float.
+// Values that fit in a float64 are rounded to the nearest float64, larger and smaller values are stored in layer 1.
+// A nil x is 0, like the zero big.Float.
+func FromBigFloat(x *big.Float) *Decimal {
+	if x == nil {
+		return dFC_NN(0, 0, 0)
+	}
+	if x.IsInf() {
+		if x.Signbit() {
+			return dFC_NN(-1, math.Inf(1), math.Inf(1))
+		}
+		return dFC_NN(1, math.Inf(1), math.Inf(1))
+	}
+	if x.Sign() == 0 {
+		return dFC_NN(0, 0, 0)
+	}
+	f, _ := x.Float64()
+	if !math.IsInf(f, 0) && f != 0 {
+		return decimalFromFloat64(f)
+	}
+	// Out of float64 range: x = mant * 2^exp with 0.5 <= |mant| < 1
+	mant := new(big.Float)
+	exp := x.MantExp(mant)
+	m, _ := mant.Float64()
+	return dFC(float64(x.Sign()), 1, math.Log10(math.Abs(m))+float64(exp)*math.Log10(2))
+}
+
+// FromBigRat creates a new Decimal from a big.Rat.
+// Values that fit in a float64 are rounded to the nearest float64, larger and smaller values are stored in layer 1.
+// A nil x is 0, like the zero big.Rat.
+func FromBigRat(x *big.Rat) *Decimal {
+	if x == nil || x.Sign() == 0 {
+		return dFC_NN(0, 0, 0)
+	}
+	f, _ := x.Float64()
+	if !math.IsInf(f, 0) && f != 0 {
+		return decimalFromFloat64(f)
+	}
+	return FromBigInt(x.Num()).Divide(FromBigInt(x.Denom()))
+}
+
+// ToBigInt returns the decimal truncated to an integer as a big.Int.
+// The accuracy reports whether the result is below, equal to or above the exact value of the decimal.
+// Returns ErrNaN for NaN, and ErrTooLarge for infinities and values with more than MAX_BIG_INT_DIGITS digits (such as layer >= 2).
+func (d *Decimal) ToBigInt() (*big.Int, big.Accuracy, error) {
+	if d.IsNaN() {
+		return nil, big.Exact, ErrNaN
+	}
+	if d.IsInf() {
+		return nil, big.Exact, ErrTooLarge
+	}
+	if d.sign == 0 {
+		return new(big.Int), big.Exact, nil
+	}
+	if d.mag < 0 && d.layer > 0 {
+		// |d| < 1
+		return new(big.Int), accuracyOf(dZero, d), nil
+	}
+	if d.layer >= 2 || (d.layer == 1 && d.mag > MAX_BIG_INT_DIGITS) {
+		return nil, big.Exact, ErrTooLarge
+	}
+
+	if d.layer == 0 {
+		// The float64 converts exactly, so only the truncation is inexact
+		result, accuracy := new(big.Float).SetFloat64(d.sign * d.mag).Int(nil)
+		return result, accuracy, nil
+	}
+	f := bigFloatPow10(d.sign, d.mag, uint(d.mag*math.Log2(10))+64)
+	result, _ := f.Int(nil)
+	return result, layer1Accuracy(new(big.Float).SetInt(result), d), nil
+}
+
+// ToBigFloat returns the decimal as a big.Float with the given precision in bits (53 if prec is 0).
+// The accuracy reports whether the result is below, equal to or above the exact value of the decimal.
+// Infinities become big.Float infinities. Returns ErrNaN for NaN, and ErrTooLarge for values outside of the big.Float exponent range.
+func (d *Decimal) ToBigFloat(prec uint) (*big.Float, big.Accuracy, error) {
+	if prec == 0 {
+		prec = 53
+	}
+	if d.IsNaN() {
+		return nil, big.Exact, ErrNaN
+	}
+	if d.IsInf() {
+		return new(big.Float).SetPrec(prec).SetInf(d.sign < 0), big.Exact, nil
+	}
+	if d.sign == 0 {
+		return new(big.Float).SetPrec(prec), big.Exact, nil
+	}
+
+	var result *big.Float
+	if d.layer == 0 {
+		result = new(big.Float).SetPrec(prec).SetFloat64(d.sign * d.mag)
+		return result, result.Acc(), nil
+	} else if d.layer == 1 {
+		if d.mag*math.Log2(10) > big.MaxExp {
+			return nil, big.Exact, ErrTooLarge
+		}
+		result = bigFloatPow10(d.sign, d.mag, prec)
+		return result, layer1Accuracy(result, d), nil
+	} else if d.mag < 0 {
+		// 10^-10^... underflows every big.Float
+		result = new(big.Float).SetPrec(prec)
+	} else {
+		return nil, big.Exact, ErrTooLarge
+	}
+	return result, accuracyOf(FromBigFloat(result), d), nil
+}
+
+// bigFloatPow10 returns sign*10^exponent with the given precision.
+// The integer part of the exponent is raised exactly by repeated squaring, so integer powers of 10 stay exact.
+func bigFloatPow10(sign float64, exponent float64, prec uint) *big.Float {
+	if exponent*math.Log2(10) < big.MinExp {
+		return new(big.Float).SetPrec(prec)
+	}
+	whole := math.Floor(exponent)
+	work := prec + 64
+
+	power := new(big.Float).SetPrec(work).SetInt64(1)
+	square := new(big.Float).SetPrec(work).SetInt64(10)
+	for n := uint64(math.Abs(whole)); n > 0; n >>= 1 {
+		if n&1 == 1 {
+			power.Mul(power, square)
+		}
+		square.Mul(square, square)
+	}
+	if whole < 0 {
+		power.Quo(new(big.Float).SetPrec(work).SetInt64(1), power)
+	}
+
+	mant := new(big.Float).SetPrec(work).SetFloat64(sign * math.Pow(10, exponent-whole))
+	return new(big.Float).SetPrec(prec).Mul(power, mant)
+}
+
+func accuracyOf(result *Decimal, d *Decimal) big.Accuracy {
+	return big.Accuracy(result.Cmp(d))
+}
+
+// layer1Accuracy reports whether x is below, equal to or above d = sign*10^mag on layer 1.
+// For a whole mag of up to MAX_BIG_INT_DIGITS, x is compared with the exact power of 10. Otherwise log10|x|
+// is compared with mag in double-double, as 10^mag is then either irrational or too large to build.
+func layer1Accuracy(x *big.Float, d *Decimal) big.Accuracy {
+	if x.Sign() == 0 {
+		return accuracyOf(dZero, d)
+	}
+	if d.mag >= 0 && d.mag <= MAX_BIG_INT_DIGITS && d.mag == math.Trunc(d.mag) {
+		exact := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.mag)), nil)
+		if d.sign < 0 {
+			exact.Neg(exact)
+		}
+		return big.Accuracy(x.Cmp(new(big.Float).SetInt(exact)))
+	}
+	mant := new(big.Float)
+	exp := x.MantExp(mant)
+	logX := ddAdd(ddLog10(ddFromBigFloat(mant.Abs(mant))), ddMulFloat64(ddMul(ddLn2, ddLog10E), float64(exp)))
+	cmp := ddCmp(logX, dd{d.mag, 0})
+	if d.sign < 0 {
+		cmp = -cmp
+	}
+	return big.Accuracy(cmp)
+}
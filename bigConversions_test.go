@@ -0,0 +1,89 @@
+package breaketernity
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestToBigIntAccuracy(t *testing.T) {
+	cases := []struct {
+		d        *Decimal
+		want     string
+		accuracy big.Accuracy
+	}{
+		{D(7), "7", big.Exact},
+		{D(2.5), "2", big.Below},
+		{D(-2.5), "-2", big.Above},
+		{D("1e20"), "100000000000000000000", big.Exact},
+		{D("-1e20"), "-100000000000000000000", big.Exact},
+		// The mag of 1e30 is 29.999999999999996, a little below 30: its power of 10 is irrational
+		{D(1e30), "999999999999991828758538758847", big.Above},
+		{D("0.5"), "0", big.Below},
+		{D("-1e-400"), "0", big.Above},
+	}
+	for _, c := range cases {
+		got, accuracy, err := c.d.ToBigInt()
+		if err != nil || got.String() != c.want || accuracy != c.accuracy {
+			t.Errorf("%s.ToBigInt() = %v, %v, %v, want %s, %v", c.d.ToString(), got, accuracy, err, c.want, c.accuracy)
+		}
+	}
+	if _, _, err := D("1e1e10").ToBigInt(); err != ErrTooLarge {
+		t.Errorf("ToBigInt of 1e1e10 returned %v, want ErrTooLarge", err)
+	}
+	if _, _, err := dFC_NN(0, 0, 0).Divide(dFC_NN(0, 0, 0)).ToBigInt(); err != ErrNaN {
+		t.Errorf("ToBigInt of NaN returned %v, want ErrNaN", err)
+	}
+}
+
+func TestToBigFloatAccuracy(t *testing.T) {
+	cases := []struct {
+		d        *Decimal
+		prec     uint
+		accuracy big.Accuracy
+	}{
+		{D(2.5), 53, big.Exact},
+		{D(0.1), 53, big.Exact},
+		{D(0.1), 10, big.Below},
+		{D("1e20"), 53, big.Exact},
+		{D("1e30"), 53, big.Above},
+		{D("1e30"), 200, big.Above},
+		{D("-1e30"), 200, big.Below},
+		{D("1e-400"), 53, big.Below},
+	}
+	for _, c := range cases {
+		got, accuracy, err := c.d.ToBigFloat(c.prec)
+		if err != nil || accuracy != c.accuracy {
+			t.Errorf("%s.ToBigFloat(%d) = %v, %v, %v, want accuracy %v", c.d.ToString(), c.prec, got, accuracy, err, c.accuracy)
+		}
+	}
+}
+
+func TestBigRoundTrip(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890123456789", 10)
+	for _, x := range []*big.Int{big.NewInt(0), big.NewInt(-42), big.NewInt(1 << 53), huge} {
+		got, _, err := D(x).ToBigInt()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Only the leading 15 digits survive a float64 mag
+		diff := new(big.Float).Sub(new(big.Float).SetInt(got), new(big.Float).SetInt(x))
+		if x.Sign() != 0 && new(big.Float).Quo(diff.Abs(diff), new(big.Float).SetInt(x).Abs(new(big.Float).SetInt(x))).Cmp(big.NewFloat(1e-14)) > 0 {
+			t.Errorf("D(%v).ToBigInt() = %v", x, got)
+		}
+	}
+	if got := FromBigRat(big.NewRat(1, 3)).ToFloat64(); got != 1.0/3 {
+		t.Errorf("FromBigRat(1/3) = %g", got)
+	}
+}
+
+func TestFromNilBig(t *testing.T) {
+	if got := D((*big.Int)(nil)); got.sign != 0 {
+		t.Errorf("D(nil *big.Int) = %s, want 0", got.ToString())
+	}
+	if got := D((*big.Float)(nil)); got.sign != 0 {
+		t.Errorf("D(nil *big.Float) = %s, want 0", got.ToString())
+	}
+	if got := FromBigRat(nil); got.sign != 0 {
+		t.Errorf("FromBigRat(nil) = %s, want 0", got.ToString())
+	}
+}
@@ -0,0 +1,172 @@
+// Command benchcmp prints a comparison table between two runs of go test -bench, e.g.
+//
+//	go test -run '^$' -bench BenchmarkOperations -count 5 > old.txt
+//	(change something)
+//	go test -run '^$' -bench BenchmarkOperations -count 5 > new.txt
+//	go run ./cmd/benchcmp old.txt new.txt
+//
+// Each benchmark is summarized by the median of its runs. Benchmarks present in only one of the runs are listed with a "-".
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// procsSuffix is the GOMAXPROCS suffix that go test appends to benchmark names
+var procsSuffix = regexp.MustCompile(`-\d+$`)
+
+// run holds the measurements of one file, by benchmark name and unit, in the order the benchmarks appear
+type run struct {
+	names   []string
+	samples map[string]map[string][]float64
+}
+
+func parse(r io.Reader) (*run, error) {
+	result := &run{samples: make(map[string]map[string][]float64)}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// BenchmarkName-8  1000  123 ns/op  16 B/op  1 allocs/op
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") || len(fields)%2 != 0 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		name := procsSuffix.ReplaceAllString(fields[0], "")
+		units := result.samples[name]
+		if units == nil {
+			units = make(map[string][]float64)
+			result.samples[name] = units
+			result.names = append(result.names, name)
+		}
+		for i := 2; i < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", fields[0], err)
+			}
+			units[fields[i+1]] = append(units[fields[i+1]], value)
+		}
+	}
+	return result, scanner.Err()
+}
+
+func parseFile(path string) (*run, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parse(f)
+}
+
+// median returns the median of the samples of a benchmark in a unit, and false if there are none
+func (r *run) median(name string, unit string) (float64, bool) {
+	values := slices.Clone(r.samples[name][unit])
+	if len(values) == 0 {
+		return 0, false
+	}
+	slices.Sort(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2, true
+	}
+	return values[mid], true
+}
+
+func format(value float64, ok bool) string {
+	if !ok {
+		return "-"
+	}
+	return strconv.FormatFloat(value, 'g', 4, 64)
+}
+
+func delta(old float64, oldOk bool, new float64, newOk bool) string {
+	if !oldOk || !newOk {
+		return "-"
+	}
+	if old == new {
+		return "~"
+	}
+	if old == 0 {
+		return "+Inf%"
+	}
+	return fmt.Sprintf("%+.1f%%", (new-old)/old*100)
+}
+
+func compare(w io.Writer, old *run, new *run, filter *regexp.Regexp) error {
+	names := slices.Clone(old.names)
+	for _, name := range new.names {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "name\told ns/op\tnew ns/op\tdelta\told allocs/op\tnew allocs/op\tdelta\t")
+	// The geometric mean of the time ratios of the benchmarks present in both runs
+	logRatios, compared := 0., 0
+	for _, name := range names {
+		if filter != nil && !filter.MatchString(name) {
+			continue
+		}
+		oldTime, oldTimeOk := old.median(name, "ns/op")
+		newTime, newTimeOk := new.median(name, "ns/op")
+		oldAllocs, oldAllocsOk := old.median(name, "allocs/op")
+		newAllocs, newAllocsOk := new.median(name, "allocs/op")
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", strings.TrimPrefix(name, "Benchmark"),
+			format(oldTime, oldTimeOk), format(newTime, newTimeOk), delta(oldTime, oldTimeOk, newTime, newTimeOk),
+			format(oldAllocs, oldAllocsOk), format(newAllocs, newAllocsOk), delta(oldAllocs, oldAllocsOk, newAllocs, newAllocsOk))
+		if oldTimeOk && newTimeOk && oldTime > 0 && newTime > 0 {
+			logRatios += math.Log(newTime / oldTime)
+			compared++
+		}
+	}
+	if compared > 0 {
+		fmt.Fprintf(tw, "[geomean]\t\t\t%+.1f%%\t\t\t\t\n", (math.Exp(logRatios/float64(compared))-1)*100)
+	}
+	return tw.Flush()
+}
+
+func main() {
+	filter := flag.String("filter", "", "only compare the benchmarks whose name matches this regular expression")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: benchcmp [-filter regexp] old.txt new.txt")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var re *regexp.Regexp
+	if *filter != "" {
+		var err error
+		if re, err = regexp.Compile(*filter); err != nil {
+			fmt.Fprintln(os.Stderr, "benchcmp:", err)
+			os.Exit(2)
+		}
+	}
+	old, err := parseFile(flag.Arg(0))
+	if err == nil {
+		var new *run
+		if new, err = parseFile(flag.Arg(1)); err == nil {
+			err = compare(os.Stdout, old, new, re)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "benchcmp:", err)
+		os.Exit(1)
+	}
+}
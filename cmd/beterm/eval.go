@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	bet "github.com/aapedro/breaketernity.go"
+	"github.com/aapedro/breaketernity.go/expression"
+)
+
+// environment holds the variables of a session. ans is set to the result of every statement.
+type environment struct {
+	vars map[string]*bet.Decimal
+}
+
+func newEnvironment() *environment {
+	return &environment{vars: make(map[string]*bet.Decimal)}
+}
+
+// statement is an expression, assigned to a variable if name is set
+type statement struct {
+	name string
+	expr *expression.Expression
+}
+
+var assignment = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*=`)
+
+// parse reads a statement, "name = expression" or an expression
+func parse(line string) (*statement, error) {
+	s := &statement{}
+	src := line
+	if m := assignment.FindStringSubmatchIndex(line); m != nil {
+		s.name = line[m[2]:m[3]]
+		if !expression.IsName(s.name) || expression.IsReserved(s.name) {
+			return nil, fmt.Errorf("cannot assign to %s", s.name)
+		}
+		src = line[m[1]:]
+	}
+	var err error
+	if s.expr, err = expression.Compile(src); err != nil {
+		// Report offsets in the whole line
+		var exprErr *expression.Error
+		if errors.As(err, &exprErr) {
+			err = &expression.Error{Offset: exprErr.Offset + len(line) - len(src), Msg: exprErr.Msg}
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// run evaluates a statement, assigns it and ans, and returns its value
+func (env *environment) run(s *statement) (*bet.Decimal, error) {
+	value, err := s.expr.Eval(env.vars)
+	if err != nil {
+		return nil, err
+	}
+	if s.name != "" {
+		env.vars[s.name] = value
+	}
+	env.vars["ans"] = value
+	return value, nil
+}
+
+// render simplifies a formula and writes it as text, LaTeX or MathML, for :simplify, :latex and :mathml
+func render(format string, src string) (string, error) {
+	root, err := expression.Parse(src)
+	if err != nil {
+		return "", err
+	}
+	root = expression.Simplify(root)
+	switch format {
+	case "latex":
+		return expression.LaTeX(root), nil
+	case "mathml":
+		return expression.MathML(root), nil
+	}
+	return expression.Text(root), nil
+}
+
+// variableNames returns the names of the variables, sorted
+func (env *environment) variableNames() []string {
+	names := make([]string, 0, len(env.vars))
+	for name := range env.vars {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// describe lists the constants and functions, for :help
+func describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "constants: %s\nfunctions:\n", strings.Join(expression.Constants(), ", "))
+	for _, usage := range expression.Functions() {
+		fmt.Fprintf(&b, "  %s\n", usage)
+	}
+	return b.String()
+}
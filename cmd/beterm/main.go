@@ -0,0 +1,305 @@
+// Command beterm evaluates Decimal expressions, e.g.
+//
+//	beterm "(1e50 ^ 1.5) * 10^^3.2 / slog(ee10)"
+//
+// With no arguments it reads one statement per line, as a REPL when stdin is a terminal. A statement is an
+// expression, or an assignment "name = expression". The result of the last statement is kept in ans.
+//
+// Expressions support + - * / % ^ (power) ^^ (tetration) ^^^ (pentation) and ! (factorial), parentheses,
+// variables, the constants e, pi, inf and nan, and the functions listed by :help. Numbers are read with D,
+// so "1e1e10" and "ee10" are numbers, and any other format of D can be quoted, e.g. "(e^6)20".
+//
+// In the REPL, :help lists the functions and commands, !! repeats the last line and !n repeats line n of :history.
+// History is kept in ~/.beterm_history unless -history is empty.
+//
+// With -json, each result is written as a JSON object on its own line, with the formatted value and its
+// sign, layer and mag, or an error.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	bet "github.com/aapedro/breaketernity.go"
+)
+
+// notations format a result with the given number of places
+var notations = map[string]func(d *bet.Decimal, places int) string{
+	"default":     func(d *bet.Decimal, places int) string { return d.ToString() },
+	"exponential": (*bet.Decimal).ToExponential,
+	"fixed":       (*bet.Decimal).ToFixed,
+	"precision":   (*bet.Decimal).ToPrecision,
+	"components": func(d *bet.Decimal, places int) string {
+		return fmt.Sprintf("sign=%v layer=%v mag=%v", d.GetSign(), d.GetLayer(), d.GetMag())
+	},
+}
+
+const notationNames = "default, exponential, fixed, precision or components"
+
+// maxHistory is the number of lines kept in the history file
+const maxHistory = 1000
+
+type session struct {
+	env      *environment
+	notation string
+	places   int
+	json     bool
+	out      io.Writer
+	history  []string
+	// historyFile is appended to after each interactive line, if set
+	historyFile string
+}
+
+// jsonResult is written for each statement in -json mode
+type jsonResult struct {
+	Input string      `json:"input"`
+	Name  string      `json:"name,omitempty"`
+	Value string      `json:"value,omitempty"`
+	Sign  *jsonNumber `json:"sign,omitempty"`
+	Layer *jsonNumber `json:"layer,omitempty"`
+	Mag   *jsonNumber `json:"mag,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// jsonNumber is a float64 written as "Infinity", "-Infinity" or "NaN" when it is not finite, like JavaScript prints them
+type jsonNumber float64
+
+func (n jsonNumber) MarshalJSON() ([]byte, error) {
+	f := float64(n)
+	switch {
+	case math.IsNaN(f):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(f, 1):
+		return []byte(`"Infinity"`), nil
+	case math.IsInf(f, -1):
+		return []byte(`"-Infinity"`), nil
+	}
+	return []byte(strconv.FormatFloat(f, 'g', -1, 64)), nil
+}
+
+// format writes d in the session's notation. Infinities and NaN are always written like ToString does,
+// as the notations with places would print their layer and mag as "(e^NaN)NaN".
+func (s *session) format(d *bet.Decimal) string {
+	if d.IsNaN() || d.IsInf() {
+		return d.ToString()
+	}
+	return notations[s.notation](d, s.places)
+}
+
+// evaluate runs one statement and prints its result or error. It returns false on error.
+func (s *session) evaluate(line string) bool {
+	st, err := parse(line)
+	var value *bet.Decimal
+	if err == nil {
+		value, err = s.env.run(st)
+	}
+	if s.json {
+		result := jsonResult{Input: line}
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			sign, layer, mag := jsonNumber(value.GetSign()), jsonNumber(value.GetLayer()), jsonNumber(value.GetMag())
+			result.Name = st.name
+			result.Value = s.format(value)
+			result.Sign, result.Layer, result.Mag = &sign, &layer, &mag
+		}
+		b, _ := json.Marshal(result)
+		fmt.Fprintln(s.out, string(b))
+		return err == nil
+	}
+	if err != nil {
+		fmt.Fprintln(s.out, "error:", err)
+		return false
+	}
+	if st.name != "" {
+		fmt.Fprintf(s.out, "%s = %s\n", st.name, s.format(value))
+	} else {
+		fmt.Fprintln(s.out, s.format(value))
+	}
+	return true
+}
+
+// command runs a REPL command starting with ":"
+func (s *session) command(line string) {
+	fields := strings.Fields(strings.TrimPrefix(line, ":"))
+	if len(fields) == 0 {
+		fields = []string{"help"}
+	}
+	switch fields[0] {
+	case "help":
+		fmt.Fprint(s.out, describe())
+		fmt.Fprintln(s.out, "commands:\n  :vars\n  :history\n  :notation ["+notationNames+"]\n  :places [n]\n  :simplify, :latex, :mathml formula\n  :quit\n  !!, !n")
+	case "vars":
+		for _, name := range s.env.variableNames() {
+			fmt.Fprintf(s.out, "%s = %s\n", name, s.format(s.env.vars[name]))
+		}
+	case "history":
+		for i, entry := range s.history {
+			fmt.Fprintf(s.out, "%5d  %s\n", i+1, entry)
+		}
+	case "notation":
+		if len(fields) == 1 {
+			fmt.Fprintln(s.out, s.notation)
+		} else if _, ok := notations[fields[1]]; ok {
+			s.notation = fields[1]
+		} else {
+			fmt.Fprintf(s.out, "error: unknown notation %s, expected %s\n", fields[1], notationNames)
+		}
+	case "places":
+		if len(fields) == 1 {
+			fmt.Fprintln(s.out, s.places)
+		} else if n, err := strconv.Atoi(fields[1]); err == nil && n >= 0 {
+			s.places = n
+		} else {
+			fmt.Fprintf(s.out, "error: invalid number of places %s\n", fields[1])
+		}
+	case "simplify", "latex", "mathml":
+		src := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(line, ":"), fields[0]))
+		if out, err := render(fields[0], src); err != nil {
+			fmt.Fprintln(s.out, "error:", err)
+		} else {
+			fmt.Fprintln(s.out, out)
+		}
+	default:
+		fmt.Fprintf(s.out, "error: unknown command :%s, see :help\n", fields[0])
+	}
+}
+
+// recall expands !! and !n from the history
+func (s *session) recall(line string) (string, error) {
+	if line == "!!" {
+		if len(s.history) == 0 {
+			return "", fmt.Errorf("no history")
+		}
+		return s.history[len(s.history)-1], nil
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 1 || n > len(s.history) {
+		return "", fmt.Errorf("no history entry %s", line[1:])
+	}
+	return s.history[n-1], nil
+}
+
+func (s *session) remember(line string, persist bool) {
+	s.history = append(s.history, line)
+	if !persist || s.historyFile == "" {
+		return
+	}
+	f, err := os.OpenFile(s.historyFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
+
+func (s *session) loadHistory() {
+	if s.historyFile == "" {
+		return
+	}
+	b, err := os.ReadFile(s.historyFile)
+	if err != nil {
+		return
+	}
+	lines := strings.Split(strings.TrimRight(string(b), "\n"), "\n")
+	if len(lines) > maxHistory {
+		lines = lines[len(lines)-maxHistory:]
+		// Keep the file from growing forever
+		os.WriteFile(s.historyFile, []byte(strings.Join(lines, "\n")+"\n"), 0o600)
+	}
+	for _, line := range lines {
+		if line != "" {
+			s.history = append(s.history, line)
+		}
+	}
+}
+
+// repl reads statements and commands line by line until the end of the input or :quit.
+// It returns false if any statement failed.
+func (s *session) repl(in io.Reader, interactive bool) bool {
+	ok := true
+	scanner := bufio.NewScanner(in)
+	for {
+		if interactive {
+			fmt.Fprint(s.out, "> ")
+		}
+		if !scanner.Scan() {
+			if interactive {
+				fmt.Fprintln(s.out)
+			}
+			return ok
+		}
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case line == ":quit" || line == ":q":
+			return ok
+		case strings.HasPrefix(line, ":"):
+			s.command(line)
+			continue
+		case strings.HasPrefix(line, "!"):
+			recalled, err := s.recall(line)
+			if err != nil {
+				fmt.Fprintln(s.out, "error:", err)
+				continue
+			}
+			line = recalled
+			if interactive {
+				fmt.Fprintln(s.out, line)
+			}
+		}
+		s.remember(line, interactive)
+		ok = s.evaluate(line) && ok
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func main() {
+	s := &session{env: newEnvironment(), out: os.Stdout}
+	flag.StringVar(&s.notation, "notation", "default", "output notation: "+notationNames)
+	flag.IntVar(&s.places, "places", 6, "decimal places of the exponential, fixed and precision notations")
+	flag.BoolVar(&s.json, "json", false, "write each result as a JSON object on its own line")
+	defaultHistory := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		defaultHistory = filepath.Join(home, ".beterm_history")
+	}
+	flag.StringVar(&s.historyFile, "history", defaultHistory, "file to keep the REPL history in, empty to disable")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: beterm [flags] [statement ...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if _, ok := notations[s.notation]; !ok {
+		fmt.Fprintf(os.Stderr, "beterm: unknown notation %s, expected %s\n", s.notation, notationNames)
+		os.Exit(2)
+	}
+
+	ok := true
+	if flag.NArg() > 0 {
+		for _, arg := range flag.Args() {
+			ok = s.evaluate(arg) && ok
+		}
+	} else {
+		interactive := isTerminal(os.Stdin) && !s.json
+		if interactive {
+			s.loadHistory()
+		}
+		ok = s.repl(os.Stdin, interactive)
+	}
+	if !ok {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func newTestSession() (*session, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &session{env: newEnvironment(), notation: "default", places: 6, out: out}, out
+}
+
+// run feeds the lines to a non-interactive REPL and returns what it printed, one line per entry
+func run(t *testing.T, s *session, out *bytes.Buffer, lines ...string) []string {
+	t.Helper()
+	out.Reset()
+	s.repl(strings.NewReader(strings.Join(lines, "\n")), false)
+	return strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+}
+
+func checkOutput(t *testing.T, got []string, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("printed %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	s, out := newTestSession()
+	checkOutput(t, run(t, s, out, "1e400 * 10", "# comment", "", "ee20 > 0", "slog(10^^3)", "1 +", "y"), []string{
+		"1e401",
+		`error: 6: unexpected ">"`,
+		"3",
+		`error: 4: unexpected end of input, expected a number, a name or "("`,
+		"error: expression: variable y is not set",
+	})
+	if ok := s.evaluate("1 + 2"); !ok {
+		t.Errorf("evaluate(\"1 + 2\") reported an error")
+	}
+	if ok := s.evaluate("foo(1)"); ok {
+		t.Errorf("evaluate(\"foo(1)\") reported no error")
+	}
+}
+
+func TestAssignmentAndAns(t *testing.T) {
+	s, out := newTestSession()
+	checkOutput(t, run(t, s, out, "x = 1e100", "x * 10", "ans / x", "y = ans + 1", "pi = 3", ":vars"), []string{
+		"x = 1e100",
+		"1e101",
+		"10",
+		"y = 11",
+		"error: cannot assign to pi",
+		"ans = 11",
+		"x = 1e100",
+		"y = 11",
+	})
+}
+
+func TestRecall(t *testing.T) {
+	s, out := newTestSession()
+	checkOutput(t, run(t, s, out, "!!", "x = 5", "x * 2", "!!", "!1", "x + 1", "!7", "!0", "!a", ":history"), []string{
+		"error: no history",
+		"x = 5",
+		"10",
+		"10",
+		"x = 5",
+		"6",
+		"error: no history entry 7",
+		"error: no history entry 0",
+		"error: no history entry a",
+		"    1  x = 5",
+		"    2  x * 2",
+		"    3  x * 2",
+		"    4  x = 5",
+		"    5  x + 1",
+	})
+}
+
+func TestNotationAndPlaces(t *testing.T) {
+	s, out := newTestSession()
+	checkOutput(t, run(t, s, out,
+		":notation", ":places", ":notation fixed", ":places 2", "1/3", "inf", ":notation components", "-1e400",
+		":notation exponential", "12345", ":notation bogus", ":places -1", ":places x", ":notation", ":places", ":bogus",
+	), []string{
+		"default",
+		"6",
+		"0.33",
+		"Infinity",
+		"sign=-1 layer=1 mag=400",
+		"1.23e+04",
+		"error: unknown notation bogus, expected " + notationNames,
+		"error: invalid number of places -1",
+		"error: invalid number of places x",
+		"exponential",
+		"2",
+		"error: unknown command :bogus, see :help",
+	})
+}
+
+func TestSimplifyCommands(t *testing.T) {
+	s, out := newTestSession()
+	checkOutput(t, run(t, s, out, ":simplify x * 1 + 0", ":latex sqrt(x) / 2", ":mathml x^2", ":simplify 1 +"), []string{
+		"x",
+		`\frac{\sqrt{x}}{2}`,
+		`<math xmlns="http://www.w3.org/1998/Math/MathML"><msup><mrow><mi>x</mi></mrow><mrow><mn>2</mn></mrow></msup></math>`,
+		`error: 4: unexpected end of input, expected a number, a name or "("`,
+	})
+}
+
+func TestJSON(t *testing.T) {
+	s, out := newTestSession()
+	s.json = true
+	lines := run(t, s, out, "z = 1e400", "-inf", "foo(")
+	want := []map[string]any{
+		{"input": "z = 1e400", "name": "z", "value": "1e400", "sign": 1., "layer": 1., "mag": 400.},
+		{"input": "-inf", "value": "-Infinity", "sign": -1., "layer": "Infinity", "mag": "Infinity"},
+		{"input": "foo(", "error": `5: unexpected end of input, expected a number, a name or "("`},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("printed %q, want %d lines", lines, len(want))
+	}
+	for i, line := range lines {
+		var got map[string]any
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d = %q: %v", i+1, line, err)
+		}
+		if len(got) != len(want[i]) {
+			t.Errorf("line %d = %v, want %v", i+1, got, want[i])
+			continue
+		}
+		for key, value := range want[i] {
+			if got[key] != value {
+				t.Errorf("line %d: %s = %v, want %v", i+1, key, got[key], value)
+			}
+		}
+	}
+}
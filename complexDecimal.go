@@ -0,0 +1,278 @@
+package breaketernity
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// ComplexDecimal is a complex number with Decimal real and imaginary parts.
+// It gives a value to the cases that are NaN for a Decimal, such as Pow with a negative base and a fractional exponent,
+// the logarithm of a negative number or LambertW below -1/e. Multi-valued functions return their principal value,
+// except LambertW, which takes the branch to use.
+// Whenever the inputs are real and so is the result, the Decimal functions are used, so the imaginary part is exactly 0.
+type ComplexDecimal struct {
+	re Decimal
+	im Decimal
+}
+
+// C creates a new ComplexDecimal from its real and imaginary parts.
+func C[DS DecimalSource](re DS, im DS) *ComplexDecimal {
+	return &ComplexDecimal{re: *D(re), im: *D(im)}
+}
+
+// CFromPolar creates a new ComplexDecimal from its modulus and its argument in radians.
+func CFromPolar(modulus *Decimal, arg float64) *ComplexDecimal {
+	if arg == 0 {
+		return &ComplexDecimal{re: *decimalFromDecimal(modulus), im: *dZero}
+	}
+	return &ComplexDecimal{
+		re: *modulus.Multiply(decimalFromFloat64(math.Cos(arg))),
+		im: *modulus.Multiply(decimalFromFloat64(math.Sin(arg))),
+	}
+}
+
+func complexFromDecimal(re *Decimal) *ComplexDecimal {
+	return &ComplexDecimal{re: *decimalFromDecimal(re), im: *dZero}
+}
+
+func complexFromComplex128(z complex128) *ComplexDecimal {
+	return &ComplexDecimal{re: *decimalFromFloat64(real(z)), im: *decimalFromFloat64(imag(z))}
+}
+
+func complexNaN() *ComplexDecimal {
+	nan := dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	return &ComplexDecimal{re: *nan, im: *nan}
+}
+
+// Re returns the real part of the complex number
+func (z *ComplexDecimal) Re() *Decimal {
+	return decimalFromDecimal(&z.re)
+}
+
+// Im returns the imaginary part of the complex number
+func (z *ComplexDecimal) Im() *Decimal {
+	return decimalFromDecimal(&z.im)
+}
+
+// IsReal returns true if the imaginary part is exactly 0
+func (z *ComplexDecimal) IsReal() bool {
+	return z.im.sign == 0 && !z.im.IsNaN()
+}
+
+// IsNaN returns true if either part is NaN
+func (z *ComplexDecimal) IsNaN() bool {
+	return z.re.IsNaN() || z.im.IsNaN()
+}
+
+// ToDecimal returns the real part of the complex number. ok is false if the imaginary part is not 0.
+func (z *ComplexDecimal) ToDecimal() (*Decimal, bool) {
+	return decimalFromDecimal(&z.re), z.IsReal()
+}
+
+// ToString returns the complex number as a string in the form "a + bi", or "a" if it is real
+func (z *ComplexDecimal) ToString() string {
+	if z.IsReal() {
+		return z.re.ToString()
+	}
+	if z.im.sign < 0 {
+		return z.re.ToString() + " - " + z.im.Neg().ToString() + "i"
+	}
+	return z.re.ToString() + " + " + z.im.ToString() + "i"
+}
+
+// Eq returns true if both parts are equal
+func (z *ComplexDecimal) Eq(other *ComplexDecimal) bool {
+	return z.re.Eq(&other.re) && z.im.Eq(&other.im)
+}
+
+// Neg returns the negative of the complex number
+func (z *ComplexDecimal) Neg() *ComplexDecimal {
+	return &ComplexDecimal{re: *z.re.Neg(), im: *z.im.Neg()}
+}
+
+// Conj returns the complex conjugate of the complex number
+func (z *ComplexDecimal) Conj() *ComplexDecimal {
+	return &ComplexDecimal{re: z.re, im: *z.im.Neg()}
+}
+
+// Add returns the sum of the complex number and other
+func (z *ComplexDecimal) Add(other *ComplexDecimal) *ComplexDecimal {
+	return &ComplexDecimal{re: *z.re.Add(&other.re), im: *z.im.Add(&other.im)}
+}
+
+// Subtract returns the difference between the complex number and other
+func (z *ComplexDecimal) Subtract(other *ComplexDecimal) *ComplexDecimal {
+	return z.Add(other.Neg())
+}
+
+// Multiply returns the product of the complex number and other
+func (z *ComplexDecimal) Multiply(other *ComplexDecimal) *ComplexDecimal {
+	if z.IsReal() && other.IsReal() {
+		return complexFromDecimal(z.re.Multiply(&other.re))
+	}
+	return &ComplexDecimal{
+		re: *z.re.Multiply(&other.re).Subtract(z.im.Multiply(&other.im)),
+		im: *z.re.Multiply(&other.im).Add(z.im.Multiply(&other.re)),
+	}
+}
+
+// Divide returns the quotient of the complex number and other
+func (z *ComplexDecimal) Divide(other *ComplexDecimal) *ComplexDecimal {
+	if z.IsReal() && other.IsReal() {
+		return complexFromDecimal(z.re.Divide(&other.re))
+	}
+	denominator := other.re.Multiply(&other.re).Add(other.im.Multiply(&other.im))
+	numerator := z.Multiply(other.Conj())
+	return &ComplexDecimal{re: *numerator.re.Divide(denominator), im: *numerator.im.Divide(denominator)}
+}
+
+// Abs returns the modulus of the complex number
+func (z *ComplexDecimal) Abs() *Decimal {
+	if z.IsReal() {
+		return z.re.Abs()
+	}
+	if z.re.sign == 0 {
+		return z.im.Abs()
+	}
+	return z.re.Multiply(&z.re).Add(z.im.Multiply(&z.im)).Sqrt()
+}
+
+// Arg returns the argument of the complex number in radians, in the range [-Pi, Pi]
+func (z *ComplexDecimal) Arg() float64 {
+	if z.IsNaN() {
+		return math.NaN()
+	}
+	re, im := z.re.ToFloat64(), z.im.ToFloat64()
+	if !math.IsInf(re, 0) && !math.IsInf(im, 0) && (re != 0 || z.re.sign == 0) && (im != 0 || z.im.sign == 0) {
+		return math.Atan2(im, re)
+	}
+	// At least one part is outside of float64 range, so only their ratio is meaningful
+	if z.re.CmpAbs(&z.im) >= 0 {
+		ratio := z.im.Divide(z.re.Abs()).ToFloat64()
+		if z.re.sign < 0 {
+			return math.Atan2(ratio, -1)
+		}
+		return math.Atan2(ratio, 1)
+	}
+	ratio := z.re.Divide(z.im.Abs()).ToFloat64()
+	return math.Atan2(z.im.sign, ratio)
+}
+
+// Exp returns e raised to the power of the complex number.
+// The imaginary part must fit in a float64, otherwise the result is NaN.
+func (z *ComplexDecimal) Exp() *ComplexDecimal {
+	if z.IsReal() {
+		return complexFromDecimal(z.re.PowBaseE())
+	}
+	angle := z.im.ToFloat64()
+	if math.IsInf(angle, 0) || math.IsNaN(angle) {
+		return complexNaN()
+	}
+	return CFromPolar(z.re.PowBaseE(), angle)
+}
+
+// Log returns the principal value of the natural logarithm of the complex number
+func (z *ComplexDecimal) Log() *ComplexDecimal {
+	if z.IsReal() && z.re.sign > 0 {
+		return complexFromDecimal(z.re.Ln())
+	}
+	if z.re.sign == 0 && z.im.sign == 0 {
+		return complexNaN()
+	}
+	return &ComplexDecimal{re: *z.Abs().Ln(), im: *decimalFromFloat64(z.Arg())}
+}
+
+// Pow returns the principal value of the complex number raised to the power of other
+func (z *ComplexDecimal) Pow(other *ComplexDecimal) *ComplexDecimal {
+	if z.IsReal() && other.IsReal() {
+		if z.re.sign > 0 || z.re.sign == 0 && other.re.sign >= 0 || other.re.Eq(other.re.Floor()) {
+			return complexFromDecimal(z.re.Pow(&other.re))
+		}
+	}
+	if z.re.sign == 0 && z.im.sign == 0 {
+		if other.re.sign > 0 {
+			return complexFromDecimal(dZero)
+		}
+		return complexNaN()
+	}
+	return other.Multiply(z.Log()).Exp()
+}
+
+// Sqrt returns the principal square root of the complex number
+func (z *ComplexDecimal) Sqrt() *ComplexDecimal {
+	if z.IsReal() {
+		if z.re.sign >= 0 {
+			return complexFromDecimal(z.re.Sqrt())
+		}
+		return &ComplexDecimal{re: *dZero, im: *z.re.Abs().Sqrt()}
+	}
+	return CFromPolar(z.Abs().Sqrt(), z.Arg()/2)
+}
+
+// LambertW returns the kth branch of the Lambert W function, the solution w of w*e^w = z.
+// Branch 0 is the principal branch, and branch -1 is the other one that is real on [-1/e, 0).
+// W_k(0) is NaN for every k != 0.
+func (z *ComplexDecimal) LambertW(k int) *ComplexDecimal {
+	if z.IsNaN() {
+		return complexNaN()
+	}
+	if z.IsReal() {
+		if k == 0 && z.re.Gte(D(-0.3678794411710499)) {
+			return complexFromDecimal(z.re.LambertW(true))
+		}
+		if k == -1 && z.re.Gte(D(-0.3678794411710499)) && z.re.sign < 0 {
+			return complexFromDecimal(z.re.LambertW(false))
+		}
+	}
+	if z.re.sign == 0 && z.im.sign == 0 {
+		return complexNaN()
+	}
+
+	re, im := z.re.ToFloat64(), z.im.ToFloat64()
+	if z.Abs().Lt(D(1e300)) && z.Abs().Gt(D(1e-300)) {
+		return complexFromComplex128(cLambertW(complex(re, im), k))
+	}
+
+	// Asymptotic expansion: W = L1 - L2 + L2/L1 + L2(L2 - 2)/(2 L1^2), L1 = log(z) + 2*Pi*i*k, L2 = log(L1)
+	l1 := z.Log().Add(C(0, 2*math.Pi*float64(k)))
+	l2 := l1.Log()
+	result := l1.Subtract(l2).Add(l2.Divide(l1))
+	correction := l2.Multiply(l2.Subtract(C(2, 0))).Divide(l1.Multiply(l1).Multiply(C(2, 0)))
+	return result.Add(correction)
+}
+
+// cLambertW computes the kth branch of the Lambert W function with Halley's method
+func cLambertW(z complex128, k int) complex128 {
+	nearBranchPoint := cmplx.Abs(z+1/math.E) < 0.3
+	var w complex128
+	if k == 0 && nearBranchPoint {
+		p := cmplx.Sqrt(2 * (math.E*z + 1))
+		w = -1 + p - p*p/3
+	} else if nearBranchPoint && (k == -1 && imag(z) >= 0 || k == 1 && imag(z) < 0) {
+		p := cmplx.Sqrt(2 * (math.E*z + 1))
+		w = -1 - p - p*p/3
+	} else if k == 0 && cmplx.Abs(z) < 3 {
+		w = cmplx.Log(1 + z)
+		if cmplx.IsInf(w) {
+			// z = -1, W_0(-1) is about -0.318 + 1.337i
+			w = complex(-0.3, 1.3)
+		}
+	} else {
+		l1 := cmplx.Log(z) + complex(0, 2*math.Pi*float64(k))
+		w = l1 - cmplx.Log(l1)
+	}
+
+	for i := 0; i < 100; i++ {
+		ew := cmplx.Exp(w)
+		f := w*ew - z
+		if w == -1 || f == 0 {
+			break
+		}
+		wn := w - f/(ew*(w+1)-(w+2)*f/(2*w+2))
+		if cmplx.Abs(wn-w) <= 1e-15*cmplx.Abs(wn) {
+			return wn
+		}
+		w = wn
+	}
+	return w
+}
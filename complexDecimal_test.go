@@ -0,0 +1,85 @@
+package breaketernity
+
+import (
+	"math"
+	"testing"
+)
+
+// complexNear returns true if z is within tolerance of re + im*i, relative to the larger of its parts and 1
+func complexNear(z *ComplexDecimal, re float64, im float64, tolerance float64) bool {
+	scale := math.Max(1, math.Max(math.Abs(re), math.Abs(im)))
+	return math.Abs(z.Re().ToFloat64()-re) <= tolerance*scale && math.Abs(z.Im().ToFloat64()-im) <= tolerance*scale
+}
+
+func TestComplexLambertW(t *testing.T) {
+	// The references solve w * e^w = z, or w + log(w) = log(z) + 2*Pi*i*k for large z, by Newton's method
+	cases := []struct {
+		z         *ComplexDecimal
+		k         int
+		re, im    float64
+		tolerance float64
+	}{
+		{C(-1, 0), 0, -0.31813150520476424, 1.3372357014306893, 1e-14},
+		{C(-1, 0), -1, -0.31813150520476424, -1.3372357014306893, 1e-14},
+		{C(1, 0), 0, 0.5671432904097838, 0, 1e-15},
+		{C(-0.25, 0), -1, -2.153292364110349, 0, 1e-14},
+		// The asymptotic expansion stops at the L2^2 / L1^2 term, which leaves an error of about L2^3 / L1^3
+		{C("1e400", "0"), 1, 914.2159468231328, 6.276320165977286, 1e-9},
+		{C("1e400", "0"), -1, 914.2159468231328, -6.276320165977286, 1e-9},
+	}
+	for _, c := range cases {
+		if got := c.z.LambertW(c.k); !complexNear(got, c.re, c.im, c.tolerance) {
+			t.Errorf("W_%d(%s) = %s, want %v + %vi", c.k, c.z.ToString(), got.ToString(), c.re, c.im)
+		}
+	}
+	if got := C(0, 0).LambertW(1); !got.IsNaN() {
+		t.Errorf("W_1(0) = %s, want NaN", got.ToString())
+	}
+}
+
+func TestComplexLambertWSolvesItsEquation(t *testing.T) {
+	for _, z := range []*ComplexDecimal{C(-1, 0), C(2, 3), C(-0.3, 0.01), C(1e10, -1e10)} {
+		for _, k := range []int{-2, -1, 0, 1, 2} {
+			w := z.LambertW(k)
+			residual := w.Multiply(w.Exp()).Subtract(z).Abs()
+			if !residual.Lte(z.Abs().Multiply(D(1e-13))) {
+				t.Errorf("W_%d(%s) = %s, but w * e^w is off by %s", k, z.ToString(), w.ToString(), residual.ToString())
+			}
+		}
+	}
+}
+
+func TestComplexPow(t *testing.T) {
+	cases := []struct {
+		z, power *ComplexDecimal
+		re, im   float64
+	}{
+		// The principal cube root of -8 is 2 * e^(i*Pi/3), not the real -2
+		{C(-8, 0), C(1.0/3, 0), 1, math.Sqrt(3)},
+		{C(-4, 0), C(0.5, 0), 0, 2},
+		{C(-2, 0), C(3, 0), -8, 0},
+		{C(0, 1), C(2, 0), -1, 0},
+		{C(0, 0), C(2, 0), 0, 0},
+	}
+	for _, c := range cases {
+		if got := c.z.Pow(c.power); !complexNear(got, c.re, c.im, 1e-15) {
+			t.Errorf("(%s)^(%s) = %s, want %v + %vi", c.z.ToString(), c.power.ToString(), got.ToString(), c.re, c.im)
+		}
+	}
+	// A negative base with an integer exponent stays real, as with Decimal.Pow
+	if got := C(-2, 0).Pow(C(3, 0)); !got.IsReal() {
+		t.Errorf("(-2)^3 = %s, want a real result", got.ToString())
+	}
+}
+
+func TestComplexLogAndSqrt(t *testing.T) {
+	if got := C(-1, 0).Log(); !complexNear(got, 0, math.Pi, 1e-15) {
+		t.Errorf("log(-1) = %s, want Pi*i", got.ToString())
+	}
+	if got := C("-1e400", "0").Log(); !complexNear(got, 400*math.Ln10, math.Pi, 1e-15) {
+		t.Errorf("log(-1e400) = %s, want 400 ln(10) + Pi*i", got.ToString())
+	}
+	if got := C(-9, 0).Sqrt(); !complexNear(got, 0, 3, 1e-15) || got.Re().sign != 0 {
+		t.Errorf("sqrt(-9) = %s, want exactly 3i", got.ToString())
+	}
+}
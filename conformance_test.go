@@ -0,0 +1,452 @@
+package breaketernity
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// The conformance suite checks the port against golden vectors stored in testdata/conformance. They were computed
+// from break_eternity.js's definitions and not yet regenerated from the library, see the README there.
+
+var conformanceReport = flag.String("conformance-report", "", "write a Markdown report of the conformance results to this file")
+
+// Tolerance policy: the relative error allowed on the mag of the result, unless a case sets its own.
+const (
+	// Comparisons, rounding and sign changes must match exactly
+	conformanceExact = 0
+	// Arithmetic rounds once or twice in float64
+	conformanceArithmetic = 1e-14
+	// Logarithms, powers and gamma go through a few library calls
+	conformanceTranscendental = 1e-12
+	// Iterative algorithms stop at their own tolerance: LambertW, tetration, slog...
+	conformanceIterative = 1e-9
+)
+
+type conformanceOp struct {
+	tolerance float64
+	call      func(a *conformanceArgs) any
+}
+
+// conformanceOps maps each operation of the fixtures to the Go function under test.
+// Arguments are in break_eternity.js order, with the receiver first.
+var conformanceOps = map[string]conformanceOp{
+	"D": {conformanceArithmetic, func(a *conformanceArgs) any { return a.decimal(0) }},
+
+	"Cmp":          {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Cmp(a.decimal(1)) }},
+	"CmpAbs":       {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).CmpAbs(a.decimal(1)) }},
+	"Eq":           {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Eq(a.decimal(1)) }},
+	"Neq":          {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Neq(a.decimal(1)) }},
+	"Lt":           {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Lt(a.decimal(1)) }},
+	"Lte":          {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Lte(a.decimal(1)) }},
+	"Gt":           {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Gt(a.decimal(1)) }},
+	"Gte":          {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Gte(a.decimal(1)) }},
+	"Max":          {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Max(a.decimal(1)) }},
+	"Min":          {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Min(a.decimal(1)) }},
+	"MaxAbs":       {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).MaxAbs(a.decimal(1)) }},
+	"MinAbs":       {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).MinAbs(a.decimal(1)) }},
+	"Clamp":        {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Clamp(a.decimal(1), a.decimal(2)) }},
+	"ClampMin":     {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).ClampMin(a.decimal(1)) }},
+	"ClampMax":     {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).ClampMax(a.decimal(1)) }},
+	"EqTolerance":  {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).EqTolerance(a.decimal(1), a.float(2)) }},
+	"CmpTolerance": {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).CmpTolerance(a.decimal(1), a.float(2)) }},
+	"NeqTolerance": {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).NeqTolerance(a.decimal(1), a.float(2)) }},
+	"LtTolerance":  {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).LtTolerance(a.decimal(1), a.float(2)) }},
+	"LteTolerance": {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).LteTolerance(a.decimal(1), a.float(2)) }},
+	"GtTolerance":  {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).GtTolerance(a.decimal(1), a.float(2)) }},
+	"GteTolerance": {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).GteTolerance(a.decimal(1), a.float(2)) }},
+	"IsNaN":        {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).IsNaN() }},
+	"IsInf":        {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).IsInf() }},
+
+	"ToFloat64": {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).ToFloat64() }},
+	"Abs":       {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Abs() }},
+	"Neg":       {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Neg() }},
+	"Round":     {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Round() }},
+	"Floor":     {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Floor() }},
+	"Ceil":      {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Ceil() }},
+	"Trunc":     {conformanceExact, func(a *conformanceArgs) any { return a.decimal(0).Trunc() }},
+
+	"Add":      {conformanceArithmetic, func(a *conformanceArgs) any { return a.decimal(0).Add(a.decimal(1)) }},
+	"Subtract": {conformanceArithmetic, func(a *conformanceArgs) any { return a.decimal(0).Subtract(a.decimal(1)) }},
+	"Multiply": {conformanceArithmetic, func(a *conformanceArgs) any { return a.decimal(0).Multiply(a.decimal(1)) }},
+	"Divide":   {conformanceArithmetic, func(a *conformanceArgs) any { return a.decimal(0).Divide(a.decimal(1)) }},
+	"Recip":    {conformanceArithmetic, func(a *conformanceArgs) any { return a.decimal(0).Recip() }},
+	"Modulo":   {conformanceArithmetic, func(a *conformanceArgs) any { return a.decimal(0).Modulo(a.decimal(1)) }},
+
+	"PLog10":    {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).PLog10() }},
+	"AbsLog10":  {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).AbsLog10() }},
+	"Log10":     {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).Log10() }},
+	"Log":       {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).Log(a.decimal(1)) }},
+	"Ln":        {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).Ln() }},
+	"Log2":      {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).Log2() }},
+	"Pow":       {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).Pow(a.decimal(1)) }},
+	"PowBase10": {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).PowBase10() }},
+	"PowBaseE":  {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).PowBaseE() }},
+	"PowBaseN":  {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).PowBaseN(a.decimal(1)) }},
+	"Root":      {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).Root(a.decimal(1)) }},
+	"Sqrt":      {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).Sqrt() }},
+	"Gamma":     {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).Gamma() }},
+	"Factorial": {conformanceTranscendental, func(a *conformanceArgs) any { return a.decimal(0).Factorial() }},
+
+	"LambertW": {conformanceIterative, func(a *conformanceArgs) any { return a.decimal(0).LambertW(a.bool(1)) }},
+	"IteratedLog": {conformanceIterative, func(a *conformanceArgs) any {
+		return a.decimal(0).IteratedLog(a.decimal(1), a.float(2), a.bool(3))
+	}},
+	"IteratedExp": {conformanceIterative, func(a *conformanceArgs) any {
+		return a.decimal(0).IteratedExp(a.float(1), a.decimal(2), a.bool(3))
+	}},
+	"LayerAdd10": {conformanceIterative, func(a *conformanceArgs) any {
+		return a.decimal(0).LayerAdd10(a.decimal(1), a.bool(2))
+	}},
+	"LayerAdd": {conformanceIterative, func(a *conformanceArgs) any {
+		return a.decimal(0).LayerAdd(a.decimal(1), a.decimal(2), a.bool(3))
+	}},
+	"Slog": {conformanceIterative, func(a *conformanceArgs) any {
+		return a.decimal(0).Slog(a.decimal(1), a.float(2), a.bool(3))
+	}},
+	"Tetrate": {conformanceIterative, func(a *conformanceArgs) any {
+		return a.decimal(0).Tetrate(a.float(1), a.decimal(2), a.bool(3))
+	}},
+	"Pentate": {conformanceIterative, func(a *conformanceArgs) any {
+		return a.decimal(0).Pentate(a.float(1), a.decimal(2), a.bool(3))
+	}},
+}
+
+type conformanceFile struct {
+	Cases []conformanceCase `json:"cases"`
+}
+
+// conformanceCase is one golden vector. Expected holds either a Decimal (sign, layer and mag),
+// a bool, an int or a number, depending on what the operation returns.
+// Divergence explains why the Go result is known to differ from the expected value.
+type conformanceCase struct {
+	Op       string            `json:"op"`
+	Args     []json.RawMessage `json:"args"`
+	Expected struct {
+		Sign   *jsonFloat `json:"sign"`
+		Layer  *jsonFloat `json:"layer"`
+		Mag    *jsonFloat `json:"mag"`
+		Bool   *bool      `json:"bool"`
+		Int    *int       `json:"int"`
+		Number *jsonFloat `json:"number"`
+	} `json:"expected"`
+	Tolerance  *float64 `json:"tolerance"`
+	Divergence string   `json:"divergence"`
+}
+
+// jsonFloat is a float64 that can also be written "Infinity", "-Infinity" or "NaN", like JavaScript prints them
+type jsonFloat float64
+
+func (f *jsonFloat) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		switch s {
+		case "Infinity":
+			*f = jsonFloat(math.Inf(1))
+		case "-Infinity":
+			*f = jsonFloat(math.Inf(-1))
+		case "NaN":
+			*f = jsonFloat(math.NaN())
+		default:
+			return fmt.Errorf("invalid number %q", s)
+		}
+		return nil
+	}
+	var v float64
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*f = jsonFloat(v)
+	return nil
+}
+
+// conformanceArgs decodes the arguments of a case. A Decimal argument is a number, a string parsed by D,
+// or an exact {sign, layer, mag} object.
+type conformanceArgs struct {
+	raw []json.RawMessage
+	err error
+}
+
+func (a *conformanceArgs) get(i int) json.RawMessage {
+	if i >= len(a.raw) {
+		if a.err == nil {
+			a.err = fmt.Errorf("missing argument %d", i)
+		}
+		return json.RawMessage("null")
+	}
+	return a.raw[i]
+}
+
+func (a *conformanceArgs) decimal(i int) *Decimal {
+	raw := a.get(i)
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return D(s)
+	}
+	var f float64
+	if json.Unmarshal(raw, &f) == nil {
+		return D(f)
+	}
+	var t struct{ Sign, Layer, Mag jsonFloat }
+	if err := json.Unmarshal(raw, &t); err != nil && a.err == nil {
+		a.err = fmt.Errorf("argument %d: %w", i, err)
+	}
+	return dFC_NN(float64(t.Sign), float64(t.Layer), float64(t.Mag))
+}
+
+func (a *conformanceArgs) float(i int) float64 {
+	var f jsonFloat
+	if err := json.Unmarshal(a.get(i), &f); err != nil && a.err == nil {
+		a.err = fmt.Errorf("argument %d: %w", i, err)
+	}
+	return float64(f)
+}
+
+func (a *conformanceArgs) bool(i int) bool {
+	var b bool
+	if err := json.Unmarshal(a.get(i), &b); err != nil && a.err == nil {
+		a.err = fmt.Errorf("argument %d: %w", i, err)
+	}
+	return b
+}
+
+// closeTo reports whether got is within a relative tolerance of want, NaNs and infinities matching themselves
+func closeTo(got float64, want float64, tolerance float64) bool {
+	if math.IsNaN(want) || math.IsInf(want, 0) || tolerance == 0 {
+		return got == want || (math.IsNaN(got) && math.IsNaN(want))
+	}
+	if want == 0 {
+		return math.Abs(got) <= tolerance
+	}
+	return math.Abs(got-want) <= tolerance*math.Max(math.Abs(got), math.Abs(want))
+}
+
+// check compares a result to the expected value, returning a description of the mismatch if any
+func (c *conformanceCase) check(result any, tolerance float64) string {
+	e := c.Expected
+	switch r := result.(type) {
+	case *Decimal:
+		if e.Sign == nil || e.Layer == nil || e.Mag == nil {
+			return fmt.Sprintf("got a Decimal %v, the fixture expects another type", *r)
+		}
+		want := dFC_NN(float64(*e.Sign), float64(*e.Layer), float64(*e.Mag))
+		switch {
+		case want.IsNaN():
+			if !r.IsNaN() {
+				return fmt.Sprintf("got %v, want NaN", *r)
+			}
+		case want.IsInf():
+			if !r.IsInf() || r.sign != want.sign {
+				return fmt.Sprintf("got %v, want %v", *r, *want)
+			}
+		case want.sign == 0 && tolerance > 0:
+			// Iterative algorithms converge to 0 from either side
+			if !(math.Abs(r.ToFloat64()) <= tolerance) {
+				return fmt.Sprintf("got %v, want 0", *r)
+			}
+		case r.sign != want.sign || r.layer != want.layer || !closeTo(r.mag, want.mag, tolerance):
+			return fmt.Sprintf("got %v, want %v", *r, *want)
+		}
+	case bool:
+		if e.Bool == nil || r != *e.Bool {
+			return fmt.Sprintf("got %v, want %v", r, fixtureValue(e.Bool))
+		}
+	case int:
+		if e.Int == nil || r != *e.Int {
+			return fmt.Sprintf("got %v, want %v", r, fixtureValue(e.Int))
+		}
+	case float64:
+		if e.Number == nil || !closeTo(r, float64(*e.Number), tolerance) {
+			return fmt.Sprintf("got %v, want %v", r, fixtureValue(e.Number))
+		}
+	default:
+		return fmt.Sprintf("unsupported result type %T", result)
+	}
+	return ""
+}
+
+func fixtureValue[T any](v *T) any {
+	if v == nil {
+		return "<missing>"
+	}
+	return *v
+}
+
+// run calls the operation, turning a panic into a mismatch
+func (c *conformanceCase) run(op conformanceOp) (mismatch string) {
+	defer func() {
+		if r := recover(); r != nil {
+			mismatch = fmt.Sprintf("panic: %v", r)
+		}
+	}()
+	args := &conformanceArgs{raw: c.Args}
+	result := op.call(args)
+	if args.err != nil {
+		return args.err.Error()
+	}
+	tolerance := op.tolerance
+	if c.Tolerance != nil {
+		tolerance = *c.Tolerance
+	}
+	return c.check(result, tolerance)
+}
+
+func loadConformanceFixtures(t *testing.T) map[string][]conformanceCase {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join("testdata", "conformance", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no fixtures in testdata/conformance")
+	}
+	fixtures := make(map[string][]conformanceCase)
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var f conformanceFile
+		if err := json.Unmarshal(b, &f); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		fixtures[strings.TrimSuffix(filepath.Base(path), ".json")] = f.Cases
+	}
+	return fixtures
+}
+
+type conformanceTally struct {
+	cases, passed, diverging int
+	failed                   []string
+	divergences              []string
+}
+
+func TestConformance(t *testing.T) {
+	tallies := make(map[string]*conformanceTally)
+	for name, cases := range loadConformanceFixtures(t) {
+		t.Run(name, func(t *testing.T) {
+			for i, c := range cases {
+				op, ok := conformanceOps[c.Op]
+				if !ok {
+					t.Errorf("case %d: unknown operation %q", i, c.Op)
+					continue
+				}
+				tally := tallies[c.Op]
+				if tally == nil {
+					tally = &conformanceTally{}
+					tallies[c.Op] = tally
+				}
+				tally.cases++
+				args := strings.Trim(string(mustMarshal(c.Args)), "[]")
+				mismatch := c.run(op)
+				switch {
+				case mismatch == "" && c.Divergence != "":
+					t.Errorf("%s(%s) is marked as diverging but now matches the expected value, remove the divergence note", c.Op, args)
+				case mismatch == "":
+					tally.passed++
+				case c.Divergence != "":
+					tally.diverging++
+					tally.divergences = append(tally.divergences, fmt.Sprintf("%s(%s): %s", c.Op, args, c.Divergence))
+					t.Logf("known divergence: %s(%s): %s (%s)", c.Op, args, mismatch, c.Divergence)
+				default:
+					tally.failed = append(tally.failed, fmt.Sprintf("%s(%s): %s", c.Op, args, mismatch))
+					t.Errorf("%s(%s): %s", c.Op, args, mismatch)
+				}
+			}
+		})
+	}
+
+	report := conformanceReportText(tallies)
+	t.Log("\n" + report)
+	if *conformanceReport != "" {
+		if err := os.WriteFile(*conformanceReport, []byte(report), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func mustMarshal(v any) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// conformanceReportText lists every operation with its results, then the cases marked as diverging
+func conformanceReportText(tallies map[string]*conformanceTally) string {
+	ops := make([]string, 0, len(tallies))
+	for op := range tallies {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	var sb strings.Builder
+	sb.WriteString("| Operation | Cases | Match | Known divergences | Failures |\n")
+	sb.WriteString("|---|---|---|---|---|\n")
+	var diverging []string
+	for _, op := range ops {
+		tally := tallies[op]
+		fmt.Fprintf(&sb, "| %s | %d | %d | %d | %d |\n", op, tally.cases, tally.passed, tally.diverging, len(tally.failed))
+		diverging = append(diverging, tally.divergences...)
+		diverging = append(diverging, tally.failed...)
+	}
+	if len(diverging) > 0 {
+		sb.WriteString("\nDiverging cases:\n\n")
+		for _, d := range diverging {
+			fmt.Fprintf(&sb, "- %s\n", d)
+		}
+	}
+	return sb.String()
+}
+
+// TestConformanceCoverage checks that every exported method of math.go has golden vectors
+func TestConformanceCoverage(t *testing.T) {
+	counts := make(map[string]int)
+	for _, cases := range loadConformanceFixtures(t) {
+		for _, c := range cases {
+			counts[c.Op]++
+		}
+	}
+
+	for _, name := range mathMethods(t) {
+		if _, ok := conformanceOps[name]; !ok {
+			t.Errorf("%s has no entry in conformanceOps", name)
+		} else if counts[name] == 0 {
+			t.Errorf("%s has no golden vectors in testdata/conformance", name)
+		}
+	}
+}
+
+// mathMethods returns the names of the exported methods of *Decimal declared in math.go
+func mathMethods(t *testing.T) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "math.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || !fn.Name.IsExported() {
+			continue
+		}
+		star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		if ident, ok := star.X.(*ast.Ident); !ok || ident.Name != "Decimal" {
+			continue
+		}
+		names = append(names, fn.Name.Name)
+	}
+	return names
+}
@@ -10,7 +10,7 @@ const EXP_LIMIT float64 = 9e15
 
 const LAYER_DOWN float64 = 15.9542425094393248746 //math.Log10(9e15)
 
-const FIRST_NEG_LAYER float64 = 1 / 1e18
+const FIRST_NEG_LAYER float64 = 1 / 9e15
 
 const NUMBER_EXP_MAX int = 308
 
@@ -26,7 +26,7 @@ const COMMAS_ARE_DECIMAL_POINTS = false
 
 var dOne *Decimal = dFC_NN(1, 0, 1)
 
-var dNegOne *Decimal = dFC_NN(0, 0, 1)
+var dNegOne *Decimal = dFC_NN(-1, 0, 1)
 
 var dInf *Decimal = dFC(1, math.Inf(1), math.Inf(1))
 
@@ -0,0 +1,149 @@
+package breaketernity
+
+// ErrorMode determines what a Context does when an operation produces NaN.
+type ErrorMode int
+
+const (
+	// ERROR_MODE_NAN returns NaN, like the package-level functions do.
+	ERROR_MODE_NAN ErrorMode = iota
+	// ERROR_MODE_PANIC panics with the name of the operation that produced NaN.
+	ERROR_MODE_PANIC
+)
+
+const DEFAULT_SLOG_ITERATIONS float64 = 100
+
+const DEFAULT_LAMBERTW_TOLERANCE float64 = 1e-10
+
+const DEFAULT_CMP_TOLERANCE float64 = 1e-7
+
+// Context bundles the precision policy used by the hyper 4 operations, LambertW and the tolerance comparisons,
+// so it can be picked once and applied consistently instead of being passed to every call.
+// The zero value of each field falls back to the package default.
+type Context struct {
+	// Linear uses the linear approximation for non-integer heights for all bases, instead of only bases > 10.
+	Linear bool
+	// SlogIterations is the number of binary search iterations used by Slog.
+	SlogIterations float64
+	// LambertWTolerance is the relative tolerance at which LambertW stops iterating.
+	LambertWTolerance float64
+	// Tolerance is the relative tolerance used by the comparison methods.
+	Tolerance float64
+	// ErrorMode determines what happens when an operation produces NaN.
+	ErrorMode ErrorMode
+}
+
+// DefaultContext returns a Context with the package defaults. The package-level functions follow the same policy,
+// except LambertW, which iterates the principal branch on layer 0 to full float64 precision instead of
+// stopping at DEFAULT_LAMBERTW_TOLERANCE.
+func DefaultContext() *Context {
+	return &Context{
+		Linear:            false,
+		SlogIterations:    DEFAULT_SLOG_ITERATIONS,
+		LambertWTolerance: DEFAULT_LAMBERTW_TOLERANCE,
+		Tolerance:         DEFAULT_CMP_TOLERANCE,
+		ErrorMode:         ERROR_MODE_NAN,
+	}
+}
+
+func (c *Context) slogIterations() float64 {
+	if c.SlogIterations <= 0 {
+		return DEFAULT_SLOG_ITERATIONS
+	}
+	return c.SlogIterations
+}
+
+func (c *Context) lambertWTolerance() float64 {
+	if c.LambertWTolerance <= 0 {
+		return DEFAULT_LAMBERTW_TOLERANCE
+	}
+	return c.LambertWTolerance
+}
+
+func (c *Context) tolerance() float64 {
+	if c.Tolerance <= 0 {
+		return DEFAULT_CMP_TOLERANCE
+	}
+	return c.Tolerance
+}
+
+func (c *Context) check(op string, result *Decimal) *Decimal {
+	if c.ErrorMode == ERROR_MODE_PANIC && result.IsNaN() {
+		panic(op + " produced NaN")
+	}
+	return result
+}
+
+// Tetrate is the result of exponentiating 'd' to 'payload' 'height' times in a row, using the context's approximation
+func (c *Context) Tetrate(d *Decimal, height float64, payload *Decimal) *Decimal {
+	return c.check("Tetrate", d.Tetrate(height, payload, c.Linear))
+}
+
+// IteratedExp returns the result of applying exp(base) 'height' times, using the context's approximation
+func (c *Context) IteratedExp(d *Decimal, height float64, payload *Decimal) *Decimal {
+	return c.check("IteratedExp", d.IteratedExp(height, payload, c.Linear))
+}
+
+// IteratedLog returns the result of applying log(base) 'times' times, using the context's approximation
+func (c *Context) IteratedLog(d *Decimal, base *Decimal, times float64) *Decimal {
+	return c.check("IteratedLog", d.IteratedLog(base, times, c.Linear))
+}
+
+// LayerAdd10 adds/removes layers from a Decimal, even fractional layers, using the context's approximation
+func (c *Context) LayerAdd10(d *Decimal, diff *Decimal) *Decimal {
+	return c.check("LayerAdd10", d.LayerAdd10(diff, c.Linear))
+}
+
+// LayerAdd is like adding "diff" to the number's slog(base) representation, using the context's approximation
+func (c *Context) LayerAdd(d *Decimal, diff *Decimal, base *Decimal) *Decimal {
+	return c.check("LayerAdd", d.LayerAdd(diff, base, c.Linear))
+}
+
+// Slog is the super-logarithm of 'd' to the given base, using the context's approximation and iteration limit
+func (c *Context) Slog(d *Decimal, base *Decimal) *Decimal {
+	return c.check("Slog", d.Slog(base, c.slogIterations(), c.Linear))
+}
+
+// Pentate is the result of tetrating 'height' times in a row, using the context's approximation
+func (c *Context) Pentate(d *Decimal, height float64, payload *Decimal) *Decimal {
+	return c.check("Pentate", d.Pentate(height, payload, c.Linear))
+}
+
+// LambertW is the Lambert W function on the given branch, iterated until the context's tolerance is reached
+func (c *Context) LambertW(d *Decimal, principal bool) *Decimal {
+	return c.check("LambertW", d.lambertW(principal, c.lambertWTolerance()))
+}
+
+// Eq returns true if d and other are equal within the context's tolerance.
+func (c *Context) Eq(d *Decimal, other *Decimal) bool {
+	return d.EqTolerance(other, c.tolerance())
+}
+
+// Neq returns true if d and other are not equal within the context's tolerance.
+func (c *Context) Neq(d *Decimal, other *Decimal) bool {
+	return d.NeqTolerance(other, c.tolerance())
+}
+
+// Cmp returns -1 if d < other, 0 if d = other, 1 if d > other, considering values within the context's tolerance equal.
+func (c *Context) Cmp(d *Decimal, other *Decimal) int {
+	return d.CmpTolerance(other, c.tolerance())
+}
+
+// Lt returns true if d < other and they are not equal within the context's tolerance.
+func (c *Context) Lt(d *Decimal, other *Decimal) bool {
+	return d.LtTolerance(other, c.tolerance())
+}
+
+// Lte returns true if d < other or they are equal within the context's tolerance.
+func (c *Context) Lte(d *Decimal, other *Decimal) bool {
+	return d.LteTolerance(other, c.tolerance())
+}
+
+// Gt returns true if d > other and they are not equal within the context's tolerance.
+func (c *Context) Gt(d *Decimal, other *Decimal) bool {
+	return d.GtTolerance(other, c.tolerance())
+}
+
+// Gte returns true if d > other or they are equal within the context's tolerance.
+func (c *Context) Gte(d *Decimal, other *Decimal) bool {
+	return d.GteTolerance(other, c.tolerance())
+}
@@ -18,16 +18,16 @@ func (d *Decimal) ToFloat64() float64 {
 	if d.mag == math.Inf(1) && d.layer == math.Inf(1) && d.sign == 1 {
 		return math.Inf(1)
 	}
-	if d.mag == math.Inf(1) && d.layer == math.Inf(1) && d.sign == 0 {
+	if d.mag == math.Inf(1) && d.layer == math.Inf(1) && d.sign == -1 {
 		return math.Inf(-1)
 	}
-	if math.IsInf(d.layer, 0) {
+	if math.IsInf(d.layer, 0) || math.IsNaN(d.layer) {
 		return math.NaN()
 	}
 	if d.layer == 0 {
 		return d.sign * d.mag
 	} else if d.layer == 1 {
-		return d.sign * math.Pow(10, d.layer)
+		return d.sign * math.Pow(10, d.mag)
 	} else {
 		if d.mag > 0 {
 			if d.sign > 0 {
@@ -41,8 +41,106 @@ func (d *Decimal) ToFloat64() float64 {
 	}
 }
 
+// ToFloat32 returns the decimal as a float32.
+// ok is false if the decimal is NaN, or finite but outside of the float32 range (including nonzero values that round to 0).
+func (d *Decimal) ToFloat32() (float32, bool) {
+	f := d.ToFloat64()
+	if math.IsNaN(f) {
+		return float32(f), false
+	}
+	result := float32(f)
+	if math.IsInf(float64(result), 0) && !d.IsInf() {
+		return result, false
+	}
+	if result == 0 && d.sign != 0 {
+		return result, false
+	}
+	return result, true
+}
+
+// ToFloat32Saturating returns the decimal as a float32, clamping finite values outside of the float32 range to ±math.MaxFloat32.
+// NaN returns NaN and infinities stay infinite.
+func (d *Decimal) ToFloat32Saturating() float32 {
+	result, ok := d.ToFloat32()
+	if !ok && math.IsInf(float64(result), 0) {
+		return float32(math.Copysign(math.MaxFloat32, float64(result)))
+	}
+	return result
+}
+
+// ToInt64 returns the decimal truncated towards zero as an int64.
+// ok is false if the decimal is NaN or outside of the int64 range.
+func (d *Decimal) ToInt64() (int64, bool) {
+	f := math.Trunc(d.ToFloat64())
+	if math.IsNaN(f) || f < math.MinInt64 || f >= -math.MinInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// ToInt64Saturating returns the decimal truncated towards zero as an int64,
+// clamping values outside of the int64 range to math.MinInt64 or math.MaxInt64. NaN returns 0.
+func (d *Decimal) ToInt64Saturating() int64 {
+	f := math.Trunc(d.ToFloat64())
+	if math.IsNaN(f) {
+		return 0
+	} else if f < math.MinInt64 {
+		return math.MinInt64
+	} else if f >= -math.MinInt64 {
+		return math.MaxInt64
+	}
+	return int64(f)
+}
+
+// ToUint64 returns the decimal truncated towards zero as a uint64.
+// ok is false if the decimal is NaN or outside of the uint64 range.
+func (d *Decimal) ToUint64() (uint64, bool) {
+	f := math.Trunc(d.ToFloat64())
+	if math.IsNaN(f) || f < 0 || f >= math.MaxUint64 {
+		return 0, false
+	}
+	return uint64(f), true
+}
+
+// ToUint64Saturating returns the decimal truncated towards zero as a uint64,
+// clamping negative values to 0 and values above the uint64 range to math.MaxUint64. NaN returns 0.
+func (d *Decimal) ToUint64Saturating() uint64 {
+	f := math.Trunc(d.ToFloat64())
+	if math.IsNaN(f) || f < 0 {
+		return 0
+	} else if f >= math.MaxUint64 {
+		return math.MaxUint64
+	}
+	return uint64(f)
+}
+
+// ToInt returns the decimal truncated towards zero as an int.
+// ok is false if the decimal is NaN or outside of the int range.
+func (d *Decimal) ToInt() (int, bool) {
+	i, ok := d.ToInt64()
+	if !ok || i < math.MinInt || i > math.MaxInt {
+		return 0, false
+	}
+	return int(i), true
+}
+
+// ToIntSaturating returns the decimal truncated towards zero as an int,
+// clamping values outside of the int range to math.MinInt or math.MaxInt. NaN returns 0.
+func (d *Decimal) ToIntSaturating() int {
+	i := d.ToInt64Saturating()
+	if i < math.MinInt {
+		return math.MinInt
+	} else if i > math.MaxInt {
+		return math.MaxInt
+	}
+	return int(i)
+}
+
 func (d *Decimal) ToString() string {
+	return d.format(MAX_ES_IN_A_ROW)
+}
 
+func (d *Decimal) format(maxEsInARow float64) string {
 	if math.IsNaN(d.layer) || math.IsNaN(d.sign) || math.IsNaN(d.mag) {
 		return "NaN"
 	}
@@ -57,19 +155,25 @@ func (d *Decimal) ToString() string {
 		if (d.mag < 1e21 && d.mag > 1e-7) || d.mag == 0 {
 			return fmt.Sprintf("%g", d.sign*d.mag)
 		}
-		return fmt.Sprintf("%fe%d", d.GetMantissa(), int(d.GetExponent()))
+		return fmt.Sprintf("%se%d", formatShortest(d.GetMantissa()), int(d.GetExponent()))
 	} else if d.layer == 1 {
-		return fmt.Sprintf("%fe%d", d.GetMantissa(), int(d.GetExponent()))
+		return fmt.Sprintf("%se%d", formatShortest(d.GetMantissa()), int(d.GetExponent()))
 	} else {
 		// layer 2+
-		if d.layer <= MAX_ES_IN_A_ROW {
-			return fmt.Sprintf("%s%s%d", signPrefix(d.sign), strings.Repeat("e", int(d.layer)), int(d.mag))
+		if d.layer <= maxEsInARow {
+			return fmt.Sprintf("%s%s%s", signPrefix(d.sign), strings.Repeat("e", int(d.layer)), formatShortest(d.mag))
 		} else {
-			return fmt.Sprintf("%s(e^%d)%d", signPrefix(d.sign), int(d.layer), int(d.mag))
+			return fmt.Sprintf("%s(e^%d)%s", signPrefix(d.sign), int(d.layer), formatShortest(d.mag))
 		}
 	}
 }
 
+// formatShortest prints the fewest digits that parse back to x, without an exponent so that it can be
+// combined with the "e"s of the Decimal notation
+func formatShortest(x float64) string {
+	return strconv.FormatFloat(x, 'f', -1, 64)
+}
+
 func (d *Decimal) ToExponential(places int) string {
 	if d.layer == 0 {
 		return numberToExponentialString(d.sign*d.mag, places)
@@ -98,6 +202,10 @@ func (d *Decimal) ToPrecision(places int) string {
 }
 
 func (d *Decimal) ToStringWithNDecimalPlaces(places int) string {
+	return d.formatWithNDecimalPlaces(places, MAX_ES_IN_A_ROW)
+}
+
+func (d *Decimal) formatWithNDecimalPlaces(places int, maxEsInARow float64) string {
 	m := d.GetMantissa()
 	e := d.GetExponent()
 	mString := strconv.FormatFloat(decimalPlaces(m, places), 'g', -1, 64)
@@ -112,7 +220,7 @@ func (d *Decimal) ToStringWithNDecimalPlaces(places int) string {
 	} else if d.layer == 1 {
 		return mString + "e" + eString
 	} else {
-		if d.layer <= MAX_ES_IN_A_ROW {
+		if d.layer <= maxEsInARow {
 			return signPrefix(d.sign) + strings.Repeat("e", int(d.layer)) + magString
 		} else {
 			return signPrefix(d.sign) + "(e^" + layerString + ")" + magString
@@ -145,7 +253,7 @@ func (d *Decimal) Normalize() *Decimal {
 	// Handle shifting from layer 0 to negative layers
 	if d.layer == 0 && d.mag < FIRST_NEG_LAYER {
 		d.layer += 1
-		d.mag = math.Log10(d.mag)
+		d.mag = fLog10(d.mag)
 		return d
 	}
 
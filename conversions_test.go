@@ -0,0 +1,180 @@
+package breaketernity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestToFloat64NaN(t *testing.T) {
+	nan := dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	if got := nan.ToFloat64(); !math.IsNaN(got) {
+		t.Errorf("NaN.ToFloat64() = %g, want NaN", got)
+	}
+}
+
+func TestNormalizeTinyValuesToLayer1(t *testing.T) {
+	// Below 1/EXP_LIMIT a layer 0 value moves to layer 1, mirroring the layer 1 to 0 threshold
+	for _, x := range []float64{1e-17, 1e-16, 5e-300} {
+		if got := D(x); got.layer != 1 || math.Abs(got.mag-math.Log10(x)) > 1e-12 {
+			t.Errorf("D(%g) = {%g, %g, %g}, want layer 1", x, got.sign, got.layer, got.mag)
+		}
+	}
+	if got := D(1e-15); got.layer != 0 {
+		t.Errorf("D(1e-15) is on layer %g, want 0", got.layer)
+	}
+}
+
+func TestToStringShortest(t *testing.T) {
+	cases := []struct {
+		d    *Decimal
+		want string
+	}{
+		{dFC_NN(1, 2, 123.5), "ee123.5"},
+		{dFC_NN(-1, 2, 1e20), "-ee100000000000000000000"},
+		{dFC_NN(1, 6, 20.25), "(e^6)20.25"},
+	}
+	for _, c := range cases {
+		if got := c.d.ToString(); got != c.want {
+			t.Errorf("ToString() = %q, want %q", got, c.want)
+		}
+	}
+}
+
+func TestToStringRoundTripsLayer1(t *testing.T) {
+	// Six fixed decimals of the mantissa used to lose everything past 1e-6 relative
+	for _, mag := range []float64{400.5, 1234.123456789, -500.25} {
+		d := dFC_NN(1, 1, mag)
+		if got := D(d.ToString()); math.Abs(got.mag-d.mag) > 1e-12*math.Abs(d.mag) {
+			t.Errorf("D(%q) has mag %.17g, want %.17g", d.ToString(), got.mag, d.mag)
+		}
+	}
+}
+
+func TestToFloat64(t *testing.T) {
+	cases := []struct {
+		d    *Decimal
+		want float64
+	}{
+		{D(2.5), 2.5},
+		{D(-3), -3},
+		{D(1e20), 1e20},
+		{D(-4.5e300), -4.5e300},
+		{D(1e-300), 1e-300},
+		{D("1e400"), math.Inf(1)},
+		{D("-1e400"), math.Inf(-1)},
+		{D("1e-400"), 0},
+		{D("ee20"), math.Inf(1)},
+		{D("-ee20"), math.Inf(-1)},
+		{dFC(1, 2, -20), 0},
+		{D(math.Inf(1)), math.Inf(1)},
+		{D(math.Inf(-1)), math.Inf(-1)},
+	}
+	for _, c := range cases {
+		got := c.d.ToFloat64()
+		// On layer 1 the value is only as precise as 10^mag, about mag * 5e-16 relative
+		if got != c.want && math.Abs(got-c.want) > 2e-13*math.Abs(c.want) {
+			t.Errorf("%s.ToFloat64() = %g, want %g", c.d.ToString(), got, c.want)
+		}
+	}
+}
+
+func TestToInt64(t *testing.T) {
+	cases := []struct {
+		d          *Decimal
+		want       int64
+		ok         bool
+		saturating int64
+	}{
+		{D(0), 0, true, 0},
+		{D(1.9), 1, true, 1},
+		{D(-1.9), -1, true, -1},
+		{D(123456789012345), 123456789012345, true, 123456789012345},
+		{D(-1e18), -1e18, true, -1e18},
+		{D(1e19), 0, false, math.MaxInt64},
+		{D(-1e19), 0, false, math.MinInt64},
+		{D("1e400"), 0, false, math.MaxInt64},
+		{D(math.Inf(-1)), 0, false, math.MinInt64},
+		{D(math.NaN()), 0, false, 0},
+	}
+	for _, c := range cases {
+		// Beyond 2^53, a Decimal only holds the value to within a relative 1e-15
+		near := func(got int64) bool {
+			return math.Abs(float64(got)-float64(c.want)) <= 1e-15*math.Abs(float64(c.want))
+		}
+		if got, ok := c.d.ToInt64(); ok != c.ok || !near(got) {
+			t.Errorf("%s.ToInt64() = %d, %v, want %d, %v", c.d.ToString(), got, ok, c.want, c.ok)
+		}
+		if got := c.d.ToInt64Saturating(); got != c.saturating && !(c.ok && near(got)) {
+			t.Errorf("%s.ToInt64Saturating() = %d, want %d", c.d.ToString(), got, c.saturating)
+		}
+		want, saturating := int(c.want), int(c.saturating)
+		if got, ok := c.d.ToInt(); ok != c.ok || !near(int64(got)) {
+			t.Errorf("%s.ToInt() = %d, %v, want %d, %v", c.d.ToString(), got, ok, want, c.ok)
+		}
+		if got := c.d.ToIntSaturating(); got != saturating && !(c.ok && near(int64(got))) {
+			t.Errorf("%s.ToIntSaturating() = %d, want %d", c.d.ToString(), got, saturating)
+		}
+	}
+}
+
+func TestToUint64(t *testing.T) {
+	cases := []struct {
+		d          *Decimal
+		want       uint64
+		ok         bool
+		saturating uint64
+	}{
+		{D(0), 0, true, 0},
+		{D(7.9), 7, true, 7},
+		{D(-0.5), 0, true, 0},
+		{D(-1), 0, false, 0},
+		{D(1.5e19), 1.5e19, true, 1.5e19},
+		{D(2e19), 0, false, math.MaxUint64},
+		{D("1e400"), 0, false, math.MaxUint64},
+		{D(math.Inf(-1)), 0, false, 0},
+		{D(math.NaN()), 0, false, 0},
+	}
+	for _, c := range cases {
+		near := func(got uint64) bool {
+			return math.Abs(float64(got)-float64(c.want)) <= 1e-15*float64(c.want)
+		}
+		if got, ok := c.d.ToUint64(); ok != c.ok || !near(got) {
+			t.Errorf("%s.ToUint64() = %d, %v, want %d, %v", c.d.ToString(), got, ok, c.want, c.ok)
+		}
+		if got := c.d.ToUint64Saturating(); got != c.saturating && !(c.ok && near(got)) {
+			t.Errorf("%s.ToUint64Saturating() = %d, want %d", c.d.ToString(), got, c.saturating)
+		}
+	}
+}
+
+func TestToFloat32(t *testing.T) {
+	cases := []struct {
+		d          *Decimal
+		want       float32
+		ok         bool
+		saturating float32
+	}{
+		{D(1.5), 1.5, true, 1.5},
+		{D(-2e30), -2e30, true, -2e30},
+		{D(1e39), float32(math.Inf(1)), false, math.MaxFloat32},
+		{D("-1e400"), float32(math.Inf(-1)), false, -math.MaxFloat32},
+		{D(1e-50), 0, false, 0},
+		{D(math.Inf(1)), float32(math.Inf(1)), true, float32(math.Inf(1))},
+		{D(0), 0, true, 0},
+	}
+	for _, c := range cases {
+		if got, ok := c.d.ToFloat32(); got != c.want || ok != c.ok {
+			t.Errorf("%s.ToFloat32() = %g, %v, want %g, %v", c.d.ToString(), got, ok, c.want, c.ok)
+		}
+		if got := c.d.ToFloat32Saturating(); got != c.saturating {
+			t.Errorf("%s.ToFloat32Saturating() = %g, want %g", c.d.ToString(), got, c.saturating)
+		}
+	}
+	nan := D(math.NaN())
+	if got, ok := nan.ToFloat32(); !math.IsNaN(float64(got)) || ok {
+		t.Errorf("NaN.ToFloat32() = %g, %v, want NaN, false", got, ok)
+	}
+	if got := nan.ToFloat32Saturating(); !math.IsNaN(float64(got)) {
+		t.Errorf("NaN.ToFloat32Saturating() = %g, want NaN", got)
+	}
+}
@@ -0,0 +1,654 @@
+package breaketernity
+
+import (
+	"math"
+)
+
+// CostCurve is the price of an upgrade as a function of how many are owned, where Cost(k) is the price of the
+// purchase that takes the count from k to k+1. Costs are expected to be positive and nondecreasing.
+type CostCurve interface {
+	// Cost returns the price of the next purchase with owned already bought
+	Cost(owned *Decimal) *Decimal
+	// TotalCost returns the price of the next n purchases, Cost(owned) + ... + Cost(owned+n-1)
+	TotalCost(owned *Decimal, n *Decimal) *Decimal
+	// MaxAffordable returns the largest n such that TotalCost(owned, n) <= budget
+	MaxAffordable(owned *Decimal, budget *Decimal) *Decimal
+	// BuyMax buys as many as the budget allows, returning the new owned count and the remaining budget
+	BuyMax(owned *Decimal, budget *Decimal) (newOwned *Decimal, remaining *Decimal)
+}
+
+// maxSummedTerms bounds how many costs are added one by one when a sum has no closed form
+const maxSummedTerms = 1000
+
+// exactCounts is 2^53, beyond which a Decimal can no longer tell n and n+1 apart
+var exactCounts = dFC_NN(1, 0, 1<<53)
+
+func buyMax(curve CostCurve, owned *Decimal, budget *Decimal) (*Decimal, *Decimal) {
+	n := curve.MaxAffordable(owned, budget)
+	if n.sign == 0 || n.IsNaN() {
+		return decimalFromDecimal(owned), decimalFromDecimal(budget)
+	}
+	// Past 2^53 purchases, n is only as precise as a Decimal and its price can overshoot the budget by a rounding error
+	return owned.Add(n), budget.Subtract(curve.TotalCost(owned, n)).Max(dZero)
+}
+
+// adjustAffordable corrects the rounding of a closed-form estimate of MaxAffordable, as long as n is small enough
+// for a single purchase to make a difference
+func adjustAffordable(total func(n *Decimal) *Decimal, n *Decimal, budget *Decimal) *Decimal {
+	n = n.Floor().Max(dZero)
+	if n.IsNaN() {
+		return decimalFromDecimal(dZero)
+	}
+	if n.Gte(exactCounts) {
+		return n
+	}
+	for i := 0; i < 4 && n.sign > 0 && total(n).Gt(budget); i++ {
+		n = n.Subtract(dOne)
+	}
+	for i := 0; i < 4 && total(n.Add(dOne)).Lte(budget); i++ {
+		n = n.Add(dOne)
+	}
+	return n
+}
+
+// searchAffordable returns the largest n such that total(n) <= budget by binary search on Cmp, for curves without
+// a closed form. The search squares n to bracket the answer, bisects in log space until the bracket is within a factor
+// of 2, then narrows it to the unit with narrowAffordable.
+func searchAffordable(total func(n *Decimal) *Decimal, budget *Decimal) *Decimal {
+	if budget.IsInf() && budget.sign > 0 {
+		return decimalFromDecimal(dInf)
+	}
+	lo, hi := dOne, decimalFromFloat64(2)
+	loTotal, hiTotal := total(lo), total(hi)
+	if loTotal.Gt(budget) {
+		return decimalFromDecimal(dZero)
+	}
+	// 64 squarings reach 2^2^64, past which a count is too large to be worth searching for
+	for i := 0; hiTotal.Lte(budget); i++ {
+		if i == 64 {
+			return hi
+		}
+		lo, loTotal = hi, hiTotal
+		hi = hi.Multiply(hi)
+		hiTotal = total(hi)
+	}
+	for hi.Gt(lo.Multiply(decimalFromFloat64(2))) {
+		mid := lo.Multiply(hi).Sqrt().Floor()
+		if mid.Lte(lo) || mid.Gte(hi) {
+			break
+		}
+		if midTotal := total(mid); midTotal.Lte(budget) {
+			lo, loTotal = mid, midTotal
+		} else {
+			hi, hiTotal = mid, midTotal
+		}
+	}
+	return narrowAffordable(total, budget, lo, hi, loTotal, hiTotal)
+}
+
+// narrowAffordable returns the largest n such that total(n) <= budget, given an affordable lo and an unaffordable hi,
+// to the unit or to the precision of a Decimal beyond 2^53. Each step tries the n at which the total would reach
+// the budget if its logarithm were linear in n, which is close for every curve once the bracket is narrow.
+// When a step fails to halve the bracket, the excess of the end that stayed is halved so that the next step lands
+// past the answer (the Illinois method), and after two such steps in a row the next one bisects.
+// loTotal and hiTotal are the totals at lo and hi, or estimates of them, which only steer the steps.
+func narrowAffordable(total func(n *Decimal) *Decimal, budget *Decimal, lo *Decimal, hi *Decimal, loTotal *Decimal, hiTotal *Decimal) *Decimal {
+	logBudget := budget.Log10()
+	excess := func(t *Decimal) float64 {
+		return t.Log10().Subtract(logBudget).ToFloat64()
+	}
+	loExcess, hiExcess := excess(loTotal), excess(hiTotal)
+	slow := 0
+	for width := hi.Subtract(lo); width.Gt(dOne); width = hi.Subtract(lo) {
+		// A NaN fraction, from a zero or infinite total, bisects too
+		fraction := loExcess / (loExcess - hiExcess)
+		if slow >= 2 || !(fraction > 0 && fraction < 1) {
+			fraction = 0.5
+		}
+		mid := lo.Add(width.Multiply(decimalFromFloat64(fraction))).Floor()
+		if mid.Lte(lo) {
+			mid = lo.Add(dOne)
+		} else if mid.Gte(hi) {
+			mid = hi.Subtract(dOne)
+		}
+		if mid.Lte(lo) || mid.Gte(hi) {
+			break
+		}
+		cost := total(mid)
+		affordable := cost.Lte(budget)
+		if affordable {
+			lo, loExcess = mid, excess(cost)
+		} else {
+			hi, hiExcess = mid, excess(cost)
+		}
+		if hi.Subtract(lo).Lte(width.Divide(decimalFromFloat64(2))) {
+			slow = 0
+			continue
+		}
+		slow++
+		if affordable {
+			hiExcess /= 2
+		} else {
+			loExcess /= 2
+		}
+	}
+	return lo
+}
+
+// sumCosts returns cost(from) + ... + cost(from+n-1) for nondecreasing costs without a closed form. The costs are
+// added from the most expensive down, until the ones left cannot change the total, or until maxSummedTerms have been
+// added. The rest is split into blocks, each summed as the quadratic through the costs at its ends and middle,
+// which is exact for costs up to cubic. A block is halved until its sum agrees with the sum of its halves
+// to 2^-44 of the total, or until maxSummedTerms blocks have been summed.
+func sumCosts(cost func(k *Decimal) *Decimal, from *Decimal, n *Decimal) *Decimal {
+	if n.sign <= 0 {
+		return decimalFromDecimal(dZero)
+	}
+	k := from.Add(n).Subtract(dOne)
+	remaining := n
+	total := dZero
+	for range maxSummedTerms {
+		term := cost(k)
+		// Every cost left is at most this one
+		if total.sign != 0 && term.Multiply(remaining).Lt(total.Multiply(decimalFromFloat64(0x1p-53))) {
+			return total
+		}
+		total = total.Add(term)
+		remaining = remaining.Subtract(dOne)
+		if remaining.sign <= 0 {
+			return total
+		}
+		k = k.Subtract(dOne)
+	}
+
+	// The blocks left to sum, the most expensive last
+	type block struct{ start, count *Decimal }
+	blocks := []block{{from, remaining}}
+	for summed := 0; len(blocks) > 0; summed++ {
+		b := blocks[len(blocks)-1]
+		blocks = blocks[:len(blocks)-1]
+		if b.count.Lte(decimalFromFloat64(2)) {
+			total = total.Add(cost(b.start))
+			if b.count.Gt(dOne) {
+				total = total.Add(cost(b.start.Add(dOne)))
+			}
+			continue
+		}
+		half := b.count.Divide(decimalFromFloat64(2)).Floor()
+		lower, upper := block{b.start, half}, block{b.start.Add(half), b.count.Subtract(half)}
+		whole := sumQuadraticBlock(cost, b.start, b.count)
+		halves := sumQuadraticBlock(cost, lower.start, lower.count).Add(sumQuadraticBlock(cost, upper.start, upper.count))
+		// The halves are about 16 times more precise than the whole, which the difference extrapolates
+		difference := halves.Subtract(whole)
+		if summed >= maxSummedTerms || difference.Abs().Lte(total.Multiply(decimalFromFloat64(0x1p-44))) {
+			total = total.Add(halves).Add(difference.Divide(decimalFromFloat64(15)))
+			continue
+		}
+		blocks = append(blocks, lower, upper)
+	}
+	return total
+}
+
+// sumQuadraticBlock returns the sum of count costs from start, where count is at least 2, as the sum of
+// the quadratic through the first, middle and last costs. With i counted from start, last = count - 1 and
+// middle = floor(last / 2), each cost is weighted by the sum of its Lagrange basis polynomial over i.
+func sumQuadraticBlock(cost func(k *Decimal) *Decimal, start *Decimal, count *Decimal) *Decimal {
+	last := count.Subtract(dOne)
+	middle := last.Divide(decimalFromFloat64(2)).Floor()
+	if middle.sign == 0 {
+		return cost(start).Add(cost(start.Add(dOne)))
+	}
+	// With the middle cost exactly halfway, the sum is also exact for cubic costs, so an even count sheds its first cost
+	if count.Lt(exactCounts) && last.Neq(middle.Multiply(decimalFromFloat64(2))) {
+		return cost(start).Add(sumQuadraticBlock(cost, start.Add(dOne), last))
+	}
+	// The sums of i and i^2 for i from 0 to last
+	s1 := last.Multiply(count).Divide(decimalFromFloat64(2))
+	s2 := s1.Multiply(last.Multiply(decimalFromFloat64(2)).Add(dOne)).Divide(decimalFromFloat64(3))
+	w0 := s2.Subtract(middle.Add(last).Multiply(s1)).Add(middle.Multiply(last).Multiply(count)).Divide(middle.Multiply(last))
+	w1 := s2.Subtract(last.Multiply(s1)).Divide(middle.Multiply(middle.Subtract(last)))
+	w2 := s2.Subtract(middle.Multiply(s1)).Divide(last.Multiply(last.Subtract(middle)))
+	return cost(start).Multiply(w0).Add(cost(start.Add(middle)).Multiply(w1)).Add(cost(start.Add(last)).Multiply(w2))
+}
+
+// summedCost is a curve without closed forms, summed with sumCosts and searched with searchAffordable
+type summedCost struct {
+	cost func(k *Decimal) *Decimal
+}
+
+func (c *summedCost) Cost(owned *Decimal) *Decimal {
+	return c.cost(owned)
+}
+
+func (c *summedCost) TotalCost(owned *Decimal, n *Decimal) *Decimal {
+	return sumCosts(c.cost, owned, n)
+}
+
+func (c *summedCost) MaxAffordable(owned *Decimal, budget *Decimal) *Decimal {
+	return searchAffordable(func(n *Decimal) *Decimal { return c.TotalCost(owned, n) }, budget)
+}
+
+func (c *summedCost) BuyMax(owned *Decimal, budget *Decimal) (*Decimal, *Decimal) {
+	return buyMax(c, owned, budget)
+}
+
+// LinearCost is a curve whose price goes up by the same amount with every purchase: base + increase * owned.
+type LinearCost struct {
+	base     Decimal
+	increase Decimal
+}
+
+// NewLinearCost creates a new LinearCost.
+func NewLinearCost[DS DecimalSource](base DS, increase DS) *LinearCost {
+	return &LinearCost{base: *D(base), increase: *D(increase)}
+}
+
+// Cost returns base + increase * owned
+func (c *LinearCost) Cost(owned *Decimal) *Decimal {
+	return c.base.Add(c.increase.Multiply(owned))
+}
+
+// TotalCost returns n * (Cost(owned) + increase * (n-1) / 2)
+func (c *LinearCost) TotalCost(owned *Decimal, n *Decimal) *Decimal {
+	if n.sign <= 0 {
+		return decimalFromDecimal(dZero)
+	}
+	return n.Multiply(c.Cost(owned).Add(c.increase.Multiply(n.Subtract(dOne)).Divide(decimalFromFloat64(2))))
+}
+
+// MaxAffordable solves increase/2 * n^2 + (Cost(owned) - increase/2) * n = budget for n
+func (c *LinearCost) MaxAffordable(owned *Decimal, budget *Decimal) *Decimal {
+	total := func(n *Decimal) *Decimal { return c.TotalCost(owned, n) }
+	if c.increase.sign == 0 {
+		if c.base.sign <= 0 {
+			return decimalFromDecimal(dInf)
+		}
+		return adjustAffordable(total, budget.Divide(&c.base), budget)
+	}
+	// The positive root, written as 2 * budget / (b + sqrt(b^2 + 2 * increase * budget)) so that it doesn't cancel
+	b := c.Cost(owned).Subtract(c.increase.Divide(decimalFromFloat64(2)))
+	root := b.Multiply(b).Add(c.increase.Multiply(budget).Multiply(decimalFromFloat64(2))).Sqrt()
+	return adjustAffordable(total, budget.Multiply(decimalFromFloat64(2)).Divide(b.Add(root)), budget)
+}
+
+// BuyMax buys as many as the budget allows, returning the new owned count and the remaining budget
+func (c *LinearCost) BuyMax(owned *Decimal, budget *Decimal) (*Decimal, *Decimal) {
+	return buyMax(c, owned, budget)
+}
+
+// GeometricCost is a curve whose price is multiplied by the same ratio with every purchase: base * ratio^owned.
+type GeometricCost struct {
+	base  Decimal
+	ratio Decimal
+}
+
+// NewGeometricCost creates a new GeometricCost.
+func NewGeometricCost[DS DecimalSource](base DS, ratio DS) *GeometricCost {
+	return &GeometricCost{base: *D(base), ratio: *D(ratio)}
+}
+
+// Cost returns base * ratio^owned
+func (c *GeometricCost) Cost(owned *Decimal) *Decimal {
+	return c.base.Multiply(c.ratio.Pow(owned))
+}
+
+// TotalCost returns Cost(owned) * (ratio^n - 1) / (ratio - 1)
+func (c *GeometricCost) TotalCost(owned *Decimal, n *Decimal) *Decimal {
+	if n.sign <= 0 {
+		return decimalFromDecimal(dZero)
+	}
+	if c.ratio.Eq(dOne) {
+		return c.base.Multiply(n)
+	}
+	// When ratio^n is close to 1, expm1 keeps the digits that ratio^n - 1 would cancel
+	if x := n.Multiply(c.ratio.Ln()); x.layer == 0 && x.mag < 1 {
+		return c.Cost(owned).Multiply(D(math.Expm1(x.sign * x.mag))).Divide(c.ratio.Subtract(dOne))
+	}
+	return c.Cost(owned).Multiply(c.ratio.Pow(n).Subtract(dOne)).Divide(c.ratio.Subtract(dOne))
+}
+
+// MaxAffordable returns log(budget * (ratio - 1) / Cost(owned) + 1) in base ratio
+func (c *GeometricCost) MaxAffordable(owned *Decimal, budget *Decimal) *Decimal {
+	total := func(n *Decimal) *Decimal { return c.TotalCost(owned, n) }
+	if c.ratio.Eq(dOne) {
+		return adjustAffordable(total, budget.Divide(&c.base), budget)
+	}
+	x := budget.Multiply(c.ratio.Subtract(dOne)).Divide(c.Cost(owned))
+	// When x is small, log1p keeps the digits that x + 1 would round away
+	if x.layer == 0 && x.mag < 1 {
+		return adjustAffordable(total, D(math.Log1p(x.sign*x.mag)).Divide(c.ratio.Ln()), budget)
+	}
+	return adjustAffordable(total, x.Add(dOne).Log(&c.ratio), budget)
+}
+
+// BuyMax buys as many as the budget allows, returning the new owned count and the remaining budget
+func (c *GeometricCost) BuyMax(owned *Decimal, budget *Decimal) (*Decimal, *Decimal) {
+	return buyMax(c, owned, budget)
+}
+
+// PolynomialCost is a curve whose price is a polynomial of the owned count:
+// coefficients[0] + coefficients[1] * owned + coefficients[2] * owned^2 + ...
+type PolynomialCost struct {
+	coefficients []Decimal
+}
+
+// NewPolynomialCost creates a new PolynomialCost from its coefficients, constant term first.
+func NewPolynomialCost[DS DecimalSource](coefficients ...DS) *PolynomialCost {
+	c := &PolynomialCost{coefficients: make([]Decimal, len(coefficients))}
+	for i, coefficient := range coefficients {
+		c.coefficients[i] = *D(coefficient)
+	}
+	return c
+}
+
+// Cost returns the polynomial at owned
+func (c *PolynomialCost) Cost(owned *Decimal) *Decimal {
+	result := dZero
+	for i := len(c.coefficients) - 1; i >= 0; i-- {
+		result = result.Multiply(owned).Add(&c.coefficients[i])
+	}
+	return result
+}
+
+// TotalCost returns the sum of the polynomial from owned to owned+n-1 in closed form. The polynomial is shifted
+// to start at owned and written in the binomial basis, where the sum of C(j, m) for j below n is C(n, m+1),
+// so the sum is a few products rather than a difference of large sums.
+func (c *PolynomialCost) TotalCost(owned *Decimal, n *Decimal) *Decimal {
+	if n.sign <= 0 {
+		return decimalFromDecimal(dZero)
+	}
+	return sumPolynomial(c.shift(owned), n)
+}
+
+// shift returns the coefficients of the polynomial at owned + j, as a polynomial in j
+func (c *PolynomialCost) shift(owned *Decimal) []*Decimal {
+	degree := len(c.coefficients) - 1
+	shifted := make([]*Decimal, degree+1)
+	for m := range shifted {
+		shifted[m] = dZero
+		for i := m; i <= degree; i++ {
+			if c.coefficients[i].sign == 0 {
+				continue
+			}
+			term := c.coefficients[i].Multiply(decimalFromFloat64(binomial(i, m)))
+			if i > m {
+				term = term.Multiply(owned.Pow(decimalFromFloat64(float64(i - m))))
+			}
+			shifted[m] = shifted[m].Add(term)
+		}
+	}
+	return shifted
+}
+
+// binomialBasis returns the coefficients of C(j, 0), C(j, 1), ... of a polynomial in j given by the coefficients
+// of j^0, j^1, ..., where j^m = sum over l of S(m, l) * l! * C(j, l), with S the Stirling numbers of the second kind
+func binomialBasis(monomials []*Decimal) []*Decimal {
+	basis := make([]*Decimal, len(monomials))
+	factorial := 1.
+	for l := range basis {
+		if l > 0 {
+			factorial *= float64(l)
+		}
+		basis[l] = dZero
+		for m := l; m < len(monomials); m++ {
+			if monomials[m].sign != 0 {
+				basis[l] = basis[l].Add(monomials[m].Multiply(decimalFromFloat64(stirling2(m, l) * factorial)))
+			}
+		}
+	}
+	return basis
+}
+
+// sumPolynomial returns the sum of a polynomial in j for j from 0 to n-1, given the coefficients of j^0, j^1, ...
+func sumPolynomial(monomials []*Decimal, n *Decimal) *Decimal {
+	total := dZero
+	choose := dOne
+	for l, coefficient := range binomialBasis(monomials) {
+		// C(n, l+1) = C(n, l) * (n - l) / (l + 1)
+		choose = choose.Multiply(n.Subtract(decimalFromFloat64(float64(l)))).Divide(decimalFromFloat64(float64(l + 1)))
+		if coefficient.sign != 0 {
+			total = total.Add(coefficient.Multiply(choose))
+		}
+	}
+	return total
+}
+
+// MaxAffordable searches for the largest affordable n
+func (c *PolynomialCost) MaxAffordable(owned *Decimal, budget *Decimal) *Decimal {
+	return searchAffordable(func(n *Decimal) *Decimal { return c.TotalCost(owned, n) }, budget)
+}
+
+// BuyMax buys as many as the budget allows, returning the new owned count and the remaining budget
+func (c *PolynomialCost) BuyMax(owned *Decimal, budget *Decimal) (*Decimal, *Decimal) {
+	return buyMax(c, owned, budget)
+}
+
+func binomial(n int, k int) float64 {
+	result := 1.
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return math.Round(result)
+}
+
+// stirling2 returns the number of ways to split n items into k nonempty groups
+func stirling2(n int, k int) float64 {
+	row := []float64{1}
+	for i := 1; i <= n; i++ {
+		next := make([]float64, i+1)
+		for j := 1; j <= i; j++ {
+			next[j] = float64(j) * rowAt(row, j)
+			next[j] += rowAt(row, j-1)
+		}
+		row = next
+	}
+	return rowAt(row, k)
+}
+
+func rowAt(row []float64, i int) float64 {
+	if i < len(row) {
+		return row[i]
+	}
+	return 0
+}
+
+// ExpPolynomialCost is a curve whose price is 10 raised to a polynomial of the owned count, such as
+// 10^(1 + owned/10 + owned^2/1000), where the ratio between consecutive prices keeps growing.
+type ExpPolynomialCost struct {
+	exponent PolynomialCost
+}
+
+// NewExpPolynomialCost creates a new ExpPolynomialCost from the coefficients of the exponent, constant term first.
+func NewExpPolynomialCost[DS DecimalSource](coefficients ...DS) *ExpPolynomialCost {
+	return &ExpPolynomialCost{exponent: *NewPolynomialCost(coefficients...)}
+}
+
+// curve returns a GeometricCost if the exponent is at most linear, otherwise a curve summed term by term
+func (c *ExpPolynomialCost) curve() CostCurve {
+	if len(c.exponent.coefficients) <= 2 {
+		g := &GeometricCost{base: *c.exponent.Cost(dZero).PowBase10(), ratio: *decimalFromFloat64(1)}
+		if len(c.exponent.coefficients) == 2 {
+			g.ratio = *c.exponent.coefficients[1].PowBase10()
+		}
+		return g
+	}
+	return &summedCost{cost: c.Cost}
+}
+
+// Cost returns 10 raised to the exponent at owned
+func (c *ExpPolynomialCost) Cost(owned *Decimal) *Decimal {
+	return c.exponent.Cost(owned).PowBase10()
+}
+
+// TotalCost returns the price of the next n purchases, in closed form if the exponent is at most linear
+func (c *ExpPolynomialCost) TotalCost(owned *Decimal, n *Decimal) *Decimal {
+	return c.curve().TotalCost(owned, n)
+}
+
+// MaxAffordable returns the largest affordable n, in closed form if the exponent is at most linear.
+// Otherwise the total of n purchases is at least the price of the last one and at most n times it. Both bounds
+// are cheap to search for, so the sums are only needed to narrow the bracket they give.
+func (c *ExpPolynomialCost) MaxAffordable(owned *Decimal, budget *Decimal) *Decimal {
+	curve := c.curve()
+	if _, summed := curve.(*summedCost); !summed || budget.IsInf() || !c.Cost(owned).Lte(budget) {
+		return curve.MaxAffordable(owned, budget)
+	}
+	last := func(n *Decimal) *Decimal { return c.Cost(owned.Add(n).Subtract(dOne)) }
+	lo := searchAffordable(func(n *Decimal) *Decimal { return n.Multiply(last(n)) }, budget)
+	// Every price is at least the first one, which bounds n when they grow slowly
+	hi := searchAffordable(last, budget).Min(budget.Divide(c.Cost(owned)).Floor()).Add(dOne)
+	// Near the answer, the total is close to the sum of a geometric series ending with the last price
+	geometric := func(n *Decimal) *Decimal {
+		price := last(n)
+		return price.Multiply(price).Divide(price.Subtract(last(n.Subtract(dOne))))
+	}
+	total := func(n *Decimal) *Decimal { return curve.TotalCost(owned, n) }
+	return narrowAffordable(total, budget, lo, hi, geometric(lo), geometric(hi))
+}
+
+// BuyMax buys as many as the budget allows, returning the new owned count and the remaining budget
+func (c *ExpPolynomialCost) BuyMax(owned *Decimal, budget *Decimal) (*Decimal, *Decimal) {
+	return buyMax(c, owned, budget)
+}
+
+// SoftcapCost follows another curve until after purchases, then multiplies every price by scale once more
+// per purchase past it: inner.Cost(owned) * scale^(owned - after + 1) once owned >= after.
+type SoftcapCost struct {
+	inner CostCurve
+	after Decimal
+	scale Decimal
+}
+
+// NewSoftcapCost creates a new SoftcapCost.
+func NewSoftcapCost[DS DecimalSource](inner CostCurve, after DS, scale DS) *SoftcapCost {
+	return &SoftcapCost{inner: inner, after: *D(after), scale: *D(scale)}
+}
+
+// Cost returns the price of the next purchase with owned already bought
+func (c *SoftcapCost) Cost(owned *Decimal) *Decimal {
+	if owned.Lt(&c.after) {
+		return c.inner.Cost(owned)
+	}
+	return c.inner.Cost(owned).Multiply(c.scale.Pow(owned.Subtract(&c.after).Add(dOne)))
+}
+
+// above returns the curve past the softcap, counted from after. It has a closed form when inner is geometric,
+// linear or polynomial.
+func (c *SoftcapCost) above() CostCurve {
+	switch inner := c.inner.(type) {
+	case *GeometricCost:
+		return &GeometricCost{base: *inner.Cost(&c.after).Multiply(&c.scale), ratio: *inner.ratio.Multiply(&c.scale)}
+	case *LinearCost:
+		poly := PolynomialCost{coefficients: []Decimal{inner.base, inner.increase}}
+		return &polyGeometricCost{poly: poly, offset: c.after, base: c.scale, ratio: c.scale}
+	case *PolynomialCost:
+		return &polyGeometricCost{poly: *inner, offset: c.after, base: c.scale, ratio: c.scale}
+	}
+	return &summedCost{cost: func(k *Decimal) *Decimal { return c.Cost(k.Add(&c.after)) }}
+}
+
+// below returns how many of the next n purchases are before the softcap
+func (c *SoftcapCost) below(owned *Decimal, n *Decimal) *Decimal {
+	return c.after.Subtract(owned).Clamp(dZero, n)
+}
+
+// TotalCost returns the price of the next n purchases, the ones before the softcap priced by inner
+func (c *SoftcapCost) TotalCost(owned *Decimal, n *Decimal) *Decimal {
+	if n.sign <= 0 {
+		return decimalFromDecimal(dZero)
+	}
+	below := c.below(owned, n)
+	total := c.inner.TotalCost(owned, below)
+	if rest := n.Subtract(below); rest.sign > 0 {
+		total = total.Add(c.above().TotalCost(owned.Add(below).Subtract(&c.after), rest))
+	}
+	return total
+}
+
+// MaxAffordable returns the largest affordable n, spending on the purchases before the softcap first
+func (c *SoftcapCost) MaxAffordable(owned *Decimal, budget *Decimal) *Decimal {
+	below := c.below(owned, decimalFromDecimal(dInf))
+	n := c.inner.MaxAffordable(owned, budget)
+	if n.Lt(below) {
+		return n
+	}
+	remaining := budget.Subtract(c.inner.TotalCost(owned, below))
+	return below.Add(c.above().MaxAffordable(owned.Add(below).Subtract(&c.after), remaining))
+}
+
+// BuyMax buys as many as the budget allows, returning the new owned count and the remaining budget
+func (c *SoftcapCost) BuyMax(owned *Decimal, budget *Decimal) (*Decimal, *Decimal) {
+	return buyMax(c, owned, budget)
+}
+
+// polyGeometricCost is a curve whose price is a polynomial times a geometric factor:
+// base * ratio^owned * poly(offset + owned). It is what a softcap makes of a linear or polynomial curve.
+type polyGeometricCost struct {
+	poly   PolynomialCost
+	offset Decimal
+	base   Decimal
+	ratio  Decimal
+}
+
+func (c *polyGeometricCost) Cost(owned *Decimal) *Decimal {
+	return c.base.Multiply(c.ratio.Pow(owned)).Multiply(c.poly.Cost(c.offset.Add(owned)))
+}
+
+// TotalCost sums q(j) * ratio^j for j below n, with q the polynomial shifted to start at owned. While n * ln(ratio)
+// is small, ratio^j is expanded as a power series in ln(ratio), which leaves sums of polynomials. Otherwise the sum
+// is A(n) * ratio^n - A(0) for the polynomial A with ratio * A(j+1) - A(j) = q(j), which is
+// the sum over k of (-ratio)^k * Δ^k q / (ratio - 1)^(k+1), with Δ the forward difference.
+func (c *polyGeometricCost) TotalCost(owned *Decimal, n *Decimal) *Decimal {
+	if n.sign <= 0 {
+		return decimalFromDecimal(dZero)
+	}
+	monomials := c.poly.shift(c.offset.Add(owned))
+	factor := c.base.Multiply(c.ratio.Pow(owned))
+	lnRatio := c.ratio.Ln()
+	if n.Multiply(lnRatio).Abs().Lte(decimalFromFloat64(2)) {
+		// The terms ln(ratio)^i / i! * (sum of q(j) * j^i) are at most 2^i / i! times the sum of q(j)
+		total := dZero
+		coefficient := dOne
+		for i := 0; i < 60 && coefficient.sign != 0; i++ {
+			powers := append(make([]*Decimal, i), monomials...)
+			for m := range i {
+				powers[m] = dZero
+			}
+			term := sumPolynomial(powers, n).Multiply(coefficient)
+			total = total.Add(term)
+			if i > 2 && term.Abs().Lte(total.Abs().Multiply(decimalFromFloat64(0x1p-60))) {
+				break
+			}
+			coefficient = coefficient.Multiply(lnRatio).Divide(decimalFromFloat64(float64(i + 1)))
+		}
+		return factor.Multiply(total)
+	}
+	// In the binomial basis, Δ^k C(j, l) = C(j, l-k), which is 1 at j = 0 if l = k and 0 otherwise
+	basis := binomialBasis(monomials)
+	u := c.ratio.Subtract(dOne)
+	an, a0 := dZero, dZero
+	coefficient := u.Recip()
+	for k := range basis {
+		difference := dZero
+		choose := dOne
+		for l := k; l < len(basis); l++ {
+			if basis[l].sign != 0 {
+				difference = difference.Add(basis[l].Multiply(choose))
+			}
+			// C(n, i+1) = C(n, i) * (n - i) / (i + 1), with i = l - k
+			choose = choose.Multiply(n.Subtract(decimalFromFloat64(float64(l - k)))).Divide(decimalFromFloat64(float64(l - k + 1)))
+		}
+		an = an.Add(coefficient.Multiply(difference))
+		a0 = a0.Add(coefficient.Multiply(basis[k]))
+		coefficient = coefficient.Multiply(c.ratio.Neg()).Divide(u)
+	}
+	return factor.Multiply(an.Multiply(c.ratio.Pow(n)).Subtract(a0))
+}
+
+func (c *polyGeometricCost) MaxAffordable(owned *Decimal, budget *Decimal) *Decimal {
+	return searchAffordable(func(n *Decimal) *Decimal { return c.TotalCost(owned, n) }, budget)
+}
+
+func (c *polyGeometricCost) BuyMax(owned *Decimal, budget *Decimal) (*Decimal, *Decimal) {
+	return buyMax(c, owned, budget)
+}
@@ -0,0 +1,123 @@
+package breaketernity
+
+import (
+	"math/big"
+	"testing"
+)
+
+// bruteTotal adds the costs of the next n purchases one by one
+func bruteTotal(c CostCurve, owned int, n int) *big.Float {
+	total := new(big.Float).SetPrec(200)
+	for k := owned; k < owned+n; k++ {
+		cost, _, _ := c.Cost(D(k)).ToBigFloat(64)
+		total.Add(total, cost)
+	}
+	return total
+}
+
+func TestTotalCostMatchesBruteForce(t *testing.T) {
+	curves := map[string]CostCurve{
+		"softcap 1 over linear":             NewSoftcapCost(NewLinearCost(1, 1), 10, 1),
+		"softcap 1.0001 over linear":        NewSoftcapCost(NewLinearCost(1, 1), 10, 1.0001),
+		"softcap 1.00001 over polynomial":   NewSoftcapCost(NewPolynomialCost(1, 0, 1), 10, 1.00001),
+		"softcap 1.01 over polynomial":      NewSoftcapCost(NewPolynomialCost(1, 2, 3), 5, 1.01),
+		"softcap 0.999 over polynomial":     NewSoftcapCost(NewPolynomialCost(1, 0, 0, 1), 3, 0.999),
+		"softcap 1.0001 over exp-poly":      NewSoftcapCost(NewExpPolynomialCost(1, 0, 1e-8), 10, 1.0001),
+		"exp-poly with a small square term": NewExpPolynomialCost(0, 0.001, 1e-7),
+		"summed quartic":                    &summedCost{cost: NewPolynomialCost(5, 0, 0, 0, 2).Cost},
+	}
+	for name, c := range curves {
+		for _, span := range [][2]int{{3, 5000}, {0, 7}, {50, 30000}, {1000, 100}} {
+			owned, n := span[0], span[1]
+			want := bruteTotal(c, owned, n)
+			got, _, err := c.TotalCost(D(owned), D(n)).ToBigFloat(200)
+			if err != nil {
+				t.Fatalf("%s: TotalCost(%d, %d): %v", name, owned, n, err)
+			}
+			relative, _ := new(big.Float).Quo(new(big.Float).Sub(got, want), want).Float64()
+			if relative > 1e-11 || relative < -1e-11 {
+				t.Errorf("%s: TotalCost(%d, %d) = %s, want %s", name, owned, n, got.Text('g', 17), want.Text('g', 17))
+			}
+		}
+	}
+}
+
+func TestSoftcapTotalCostOfManyPurchases(t *testing.T) {
+	// A scale of 1 leaves the linear curve as it is: 200000 purchases from 3 cost 200000 * 4 + 200000 * 199999 / 2
+	c := NewSoftcapCost(NewLinearCost(1, 1), 10, 1)
+	if got := c.TotalCost(D(3), D(200000)); !got.EqTolerance(D(20000700000), 1e-12) {
+		t.Errorf("TotalCost(3, 200000) = %s, want 20000700000", got.ToString())
+	}
+}
+
+// bruteAffordable returns the largest n whose costs from owned, added one by one, fit in the budget, and their total
+func bruteAffordable(c CostCurve, owned int, budget float64) (int, float64) {
+	n, spent := 0, 0.
+	for spent+c.Cost(D(owned+n)).ToFloat64() <= budget {
+		spent += c.Cost(D(owned + n)).ToFloat64()
+		n++
+	}
+	return n, spent
+}
+
+var affordableCurves = map[string]CostCurve{
+	"linear":                          NewLinearCost(3, 2),
+	"constant":                        NewLinearCost(1500, 0),
+	"geometric":                       NewGeometricCost(10, 1.15),
+	"geometric close to 1":            NewGeometricCost(2, 1.0001),
+	"polynomial":                      NewPolynomialCost(1, 2, 3),
+	"cubic":                           NewPolynomialCost(5, 0, 0, 0.5),
+	"exp-poly with a linear exponent": NewExpPolynomialCost(1, 0.01),
+	"exp-poly":                        NewExpPolynomialCost(0, 0.001, 1e-6),
+	"exp-poly with a cubic exponent":  NewExpPolynomialCost(0.5, 0, 0, 1e-9),
+	"softcap 1 over linear":           NewSoftcapCost(NewLinearCost(1, 1), 10, 1),
+	"softcap 1.001 over linear":       NewSoftcapCost(NewLinearCost(1, 1), 10, 1.001),
+	"softcap 1.00001 over polynomial": NewSoftcapCost(NewPolynomialCost(1, 0, 1), 10, 1.00001),
+}
+
+func TestMaxAffordableMatchesBruteForce(t *testing.T) {
+	for name, c := range affordableCurves {
+		for _, owned := range []int{0, 7} {
+			for _, budget := range []float64{1, 50, 1e6, 1e9} {
+				want, _ := bruteAffordable(c, owned, budget)
+				if got := c.MaxAffordable(D(owned), D(budget)); got.Neq(D(want)) {
+					t.Errorf("%s: MaxAffordable(%d, %g) = %s, want %d", name, owned, budget, got.ToString(), want)
+				}
+			}
+		}
+	}
+}
+
+func TestBuyMax(t *testing.T) {
+	for name, c := range affordableCurves {
+		for _, owned := range []int{0, 7} {
+			for _, budget := range []float64{1, 50, 1e6, 1e9} {
+				n, spent := bruteAffordable(c, owned, budget)
+				newOwned, remaining := c.BuyMax(D(owned), D(budget))
+				if newOwned.Neq(D(owned + n)) {
+					t.Errorf("%s: BuyMax(%d, %g) owns %s, want %d", name, owned, budget, newOwned.ToString(), owned+n)
+				}
+				// The brute force sum rounds once per purchase
+				if !remaining.Subtract(D(budget - spent)).Abs().Lte(D(budget * 1e-12)) {
+					t.Errorf("%s: BuyMax(%d, %g) leaves %s, want %g", name, owned, budget, remaining.ToString(), budget-spent)
+				}
+			}
+		}
+	}
+}
+
+func TestExpPolynomialBuyMaxOfManyPurchases(t *testing.T) {
+	// About 4.5 million purchases, too many to add up one by one
+	c := NewExpPolynomialCost(0, 1e-6, 1e-12)
+	budget := D(1e30)
+	newOwned, remaining := c.BuyMax(D(0), budget)
+	if want := c.curve().MaxAffordable(D(0), budget); newOwned.Neq(want) {
+		t.Errorf("BuyMax(0, 1e30) owns %s, want %s as found by searching the sums alone", newOwned.ToString(), want.ToString())
+	}
+	if next := c.TotalCost(D(0), newOwned.Add(dOne)); next.Lte(budget) {
+		t.Errorf("BuyMax(0, 1e30) stopped at %s, but %s more cost %s", newOwned.ToString(), newOwned.Add(dOne).ToString(), next.ToString())
+	}
+	if want := budget.Subtract(c.TotalCost(D(0), newOwned)); !remaining.EqTolerance(want, 1e-9) {
+		t.Errorf("BuyMax(0, 1e30) leaves %s, want %s", remaining.ToString(), want.ToString())
+	}
+}
@@ -2,6 +2,7 @@ package breaketernity
 
 import (
 	"math"
+	"math/big"
 	"strconv"
 	"strings"
 )
@@ -13,11 +14,11 @@ type Decimal struct {
 }
 
 type DecimalSource interface {
-	~*Decimal | float64 | float32 | int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64 | string
+	~*Decimal | float64 | float32 | int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64 | string | *big.Int | *big.Float
 }
 
 // D is a function that creates a new Decimal instance from a given source.
-// The source can be another *Decimal, a string, or any numeric type.
+// The source can be another *Decimal, a string, any numeric type, a *big.Int or a *big.Float.
 // The function returns a pointer to the newly created Decimal instance.
 func D[S DecimalSource](source S) *Decimal {
 	return decimalFromSource(source)
@@ -57,6 +58,10 @@ func decimalFromSource[DS DecimalSource](value DS) *Decimal {
 		return decimalFromFloat64(float64(v))
 	case string:
 		return decimalFromString(v, false)
+	case *big.Int:
+		return FromBigInt(v)
+	case *big.Float:
+		return FromBigFloat(v)
 	}
 	return nil
 }
@@ -71,26 +76,29 @@ func decimalFromFloat64(f float64) *Decimal {
 }
 
 func decimalFromString(s string, linearhyper4 bool) *Decimal {
+	opts := DefaultParseOptions()
+	opts.LinearHyper4 = linearhyper4
+	return decimalFromStringWith(s, opts)
+}
+
+func decimalFromStringWith(s string, opts ParseOptions) *Decimal {
 	// TODO: cache
-	if IGNORE_COMMAS {
-		s = strings.Replace(s, ",", "", -1)
-	} else if COMMAS_ARE_DECIMAL_POINTS {
-		s = strings.Replace(s, ",", ".", -1)
-	}
+	s = applyParseOptions(s, opts)
+	linearhyper4 := opts.LinearHyper4
 
 	pentationParts := strings.Split(s, "^^^")
 	if len(pentationParts) == 2 {
-		base, _ := strconv.ParseFloat(pentationParts[0], 64)
-		height, _ := strconv.ParseFloat(pentationParts[1], 64)
+		base := parseFloatJS(pentationParts[0])
+		height := parseFloatJS(pentationParts[1])
 		heightParts := strings.Split(pentationParts[1], ";")
 		payload := float64(1)
 		if len(heightParts) == 2 {
-			payload, _ = strconv.ParseFloat(heightParts[1], 64)
-			if math.IsInf(payload, 0) {
+			payload = parseFloatJS(heightParts[1])
+			if !isFinite(payload) {
 				payload = 1
 			}
 		}
-		if !math.IsInf(base, 0) && !math.IsInf(height, 0) {
+		if isFinite(base) && isFinite(height) {
 			result := Pentate(D(base), height, D(payload), linearhyper4)
 			return &Decimal{sign: result.sign, layer: result.layer, mag: result.mag}
 		}
@@ -98,17 +106,17 @@ func decimalFromString(s string, linearhyper4 bool) *Decimal {
 
 	tetrationParts := strings.Split(s, "^^")
 	if len(tetrationParts) == 2 {
-		base, _ := strconv.ParseFloat(tetrationParts[0], 64)
-		height, _ := strconv.ParseFloat(tetrationParts[1], 64)
+		base := parseFloatJS(tetrationParts[0])
+		height := parseFloatJS(tetrationParts[1])
 		heightParts := strings.Split(tetrationParts[1], ";")
 		payload := float64(1)
 		if len(heightParts) == 2 {
-			payload, _ = strconv.ParseFloat(heightParts[1], 64)
-			if math.IsInf(payload, 0) {
+			payload = parseFloatJS(heightParts[1])
+			if !isFinite(payload) {
 				payload = 1
 			}
 		}
-		if !math.IsInf(base, 0) && !math.IsInf(height, 0) {
+		if isFinite(base) && isFinite(height) {
 			result := Tetrate(D(base), height, D(payload), linearhyper4)
 			return &Decimal{sign: result.sign, layer: result.layer, mag: result.mag}
 		}
@@ -116,9 +124,9 @@ func decimalFromString(s string, linearhyper4 bool) *Decimal {
 
 	powParts := strings.Split(s, "^")
 	if len(powParts) == 2 {
-		base, _ := strconv.ParseFloat(powParts[0], 64)
-		exponent, _ := strconv.ParseFloat(powParts[1], 64)
-		if !math.IsInf(base, 0) && !math.IsInf(exponent, 0) {
+		base := parseFloatJS(powParts[0])
+		exponent := parseFloatJS(powParts[1])
+		if isFinite(base) && isFinite(exponent) {
 			result := Pow(D(base), D(exponent))
 			return &Decimal{sign: result.sign, layer: result.layer, mag: result.mag}
 		}
@@ -130,18 +138,18 @@ func decimalFromString(s string, linearhyper4 bool) *Decimal {
 	if len(ptParts) == 2 {
 		base := 10.0
 		negative := false
-		if ptParts[0][0] == '-' {
+		if strings.HasPrefix(ptParts[0], "-") {
 			negative = true
 			ptParts[0] = ptParts[0][1:]
 		}
-		height, _ := strconv.ParseFloat(ptParts[0], 64) // Error ignored
+		height := parseFloatJS(ptParts[0])
 		ptParts[1] = strings.Replace(ptParts[1], "(", "", -1)
 		ptParts[1] = strings.Replace(ptParts[1], ")", "", -1)
-		payload, _ := strconv.ParseFloat(ptParts[1], 64) // Error ignored
-		if math.IsInf(payload, 0) {
+		payload := parseFloatJS(ptParts[1])
+		if !isFinite(payload) {
 			payload = 1
 		}
-		if !math.IsInf(base, 0) && !math.IsInf(height, 0) {
+		if isFinite(base) && isFinite(height) {
 			result := Tetrate(D(base), height, D(payload), linearhyper4)
 			if negative {
 				result.sign *= -1
@@ -154,18 +162,18 @@ func decimalFromString(s string, linearhyper4 bool) *Decimal {
 	if len(ptParts) == 2 {
 		base := 10.0
 		negative := false
-		if ptParts[0][0] == '-' {
+		if strings.HasPrefix(ptParts[0], "-") {
 			negative = true
 			ptParts[0] = ptParts[0][1:]
 		}
-		height, _ := strconv.ParseFloat(ptParts[0], 64) // Error ignored
+		height := parseFloatJS(ptParts[0])
 		ptParts[1] = strings.Replace(ptParts[1], "(", "", -1)
 		ptParts[1] = strings.Replace(ptParts[1], ")", "", -1)
-		payload, _ := strconv.ParseFloat(ptParts[1], 64) // Error ignored
-		if math.IsInf(payload, 0) {
+		payload := parseFloatJS(ptParts[1])
+		if !isFinite(payload) {
 			payload = 1
 		}
-		if !math.IsInf(base, 0) && !math.IsInf(height, 0) {
+		if isFinite(base) && isFinite(height) {
 			result := Tetrate(D(base), height, D(payload), linearhyper4)
 			if negative {
 				result.sign *= -1
@@ -178,20 +186,20 @@ func decimalFromString(s string, linearhyper4 bool) *Decimal {
 	if len(fParts) == 2 {
 		base := 10.0
 		negative := false
-		if fParts[0][0] == '-' {
+		if strings.HasPrefix(fParts[0], "-") {
 			negative = true
 			fParts[0] = fParts[0][1:]
 		}
 		fParts[0] = strings.Replace(fParts[0], "(", "", -1)
 		fParts[0] = strings.Replace(fParts[0], ")", "", -1)
-		payload, _ := strconv.ParseFloat(fParts[0], 64) // Error ignored
+		payload := parseFloatJS(fParts[0])
 		fParts[1] = strings.Replace(fParts[1], "(", "", -1)
 		fParts[1] = strings.Replace(fParts[1], ")", "", -1)
-		height, _ := strconv.ParseFloat(fParts[1], 64) // Error ignored
-		if math.IsInf(payload, 0) {
+		height := parseFloatJS(fParts[1])
+		if !isFinite(payload) {
 			payload = 1
 		}
-		if !math.IsInf(base, 0) && !math.IsInf(height, 0) {
+		if isFinite(base) && isFinite(height) {
 			result := Tetrate(D(base), height, D(payload), linearhyper4)
 			if negative {
 				result.sign *= -1
@@ -205,48 +213,69 @@ func decimalFromString(s string, linearhyper4 bool) *Decimal {
 
 	// Handle numbers that are exactly floats (0 or 1 "e"s).
 	if eCount == 0 {
-		numberAttempt, _ := strconv.ParseFloat(s, 64)
-		if !math.IsInf(numberAttempt, 0) {
-			if !math.IsInf(numberAttempt, 0) && !math.IsNaN(numberAttempt) {
-				return decimalFromFloat64(numberAttempt)
-			}
+		numberAttempt := parseFloatJS(s)
+		if isFinite(numberAttempt) {
+			return decimalFromFloat64(numberAttempt)
 		}
 	} else if eCount == 1 {
-		numberAttempt, _ := strconv.ParseFloat(s, 64)
-		if !math.IsInf(numberAttempt, 0) && numberAttempt != 0 {
+		// Very small numbers ("2e-3000") and very large numbers ("2e3000") need to be parsed as Decimals,
+		// and so do subnormal numbers ("1e-321"), which have lost precision as a float64
+		numberAttempt := parseFloatJS(s)
+		if isFinite(numberAttempt) && math.Abs(numberAttempt) >= 0x1p-1022 {
 			return decimalFromFloat64(numberAttempt)
 		}
 	}
 
-	// TODO: (e^N)X format
+	// Handle the (e^N)X format, which ToString uses for more than MAX_ES_IN_A_ROW layers
+	layerParts := strings.Split(s, "e^")
+	if len(layerParts) == 2 {
+		result := &Decimal{sign: 1}
+		if strings.HasPrefix(layerParts[0], "-") {
+			result.sign = -1
+		}
+		// The layer count ends at the first character that can't be part of a number
+		end := strings.IndexFunc(layerParts[1], func(r rune) bool {
+			return !(r >= '+' && r <= '9' || r == 'e')
+		})
+		if end >= 0 {
+			result.layer = parseFloatJS(layerParts[1][:end])
+			result.mag = parseFloatJS(layerParts[1][end+1:])
+			// Layers are whole numbers, unlike in break_eternity.js which keeps fractional ones as they are
+			if result.layer >= 0 && result.layer == math.Trunc(result.layer) {
+				return result.Normalize()
+			}
+		}
+	}
 
 	if eCount < 1 {
 		return &Decimal{sign: 0, layer: 0, mag: 0}
 	}
 
-	mantissa, _ := strconv.ParseFloat(eParts[0], 64)
+	mantissa := parseFloatJS(eParts[0])
 	if mantissa == 0 {
 		return &Decimal{sign: 0, layer: 0, mag: 0}
 	}
 
-	exponent, _ := strconv.ParseFloat(eParts[len(eParts)-1], 64)
+	exponent := parseFloatJS(eParts[len(eParts)-1])
 	if eCount >= 2 {
-		me, _ := strconv.ParseFloat(eParts[len(eParts)-2], 64)
-		if !math.IsInf(me, 0) {
+		me := parseFloatJS(eParts[len(eParts)-2])
+		if isFinite(me) {
 			exponent *= sign(me)
 			exponent += fMagLog10(me)
 		}
 	}
 
 	result := &Decimal{sign: sign(mantissa), layer: float64(eCount), mag: 0}
-	if math.IsInf(mantissa, 0) {
+	if math.IsInf(mantissa, 0) || math.IsNaN(mantissa) {
+		// A missing mantissa, like in "ee20" or "-e5", stands for 1
 		if eParts[0] == "-" {
-			return &Decimal{sign: -1, layer: 0, mag: 0}
+			result.sign = -1
 		} else {
-			return &Decimal{sign: 1, layer: 0, mag: 0}
+			result.sign = 1
 		}
+		result.mag = exponent
 	} else if eCount == 1 {
-		return &Decimal{sign: sign(mantissa), layer: 1, mag: exponent + math.Log10(math.Abs(mantissa))}
+		result.mag = exponent + math.Log10(math.Abs(mantissa))
 	} else {
 		if eCount == 2 {
 			result2 := Multiply(dFC(1, 2, exponent), D(mantissa))
@@ -260,6 +289,63 @@ func decimalFromString(s string, linearhyper4 bool) *Decimal {
 	return result
 }
 
+// parseFloatJS parses the longest prefix of s that is a number, like JavaScript's parseFloat:
+// NaN if s doesn't start with a number, and ±Inf if it is out of range.
+func parseFloatJS(s string) float64 {
+	s = strings.TrimLeft(s, " \t\n\r")
+	n := floatPrefixLength(s)
+	if n == 0 {
+		return math.NaN()
+	}
+	f, _ := strconv.ParseFloat(s[:n], 64) // Only range errors are possible, f is then ±Inf or 0
+	return f
+}
+
+// floatPrefixLength returns the length of the longest prefix of s that is a decimal number or Infinity,
+// optionally signed, or 0 if there is none
+func floatPrefixLength(s string) int {
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	if strings.HasPrefix(s[i:], "Infinity") {
+		return i + len("Infinity")
+	}
+	digits := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+		digits++
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		for i < len(s) && isDigit(s[i]) {
+			i++
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0
+	}
+	// An exponent only counts if it has digits, so "5e" and "5e+" are just 5
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && isDigit(s[j]) {
+			for j < len(s) && isDigit(s[j]) {
+				j++
+			}
+			i = j
+		}
+	}
+	return i
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
 func dFC_NN(sign float64, layer float64, mag float64) *Decimal {
 	return &Decimal{sign: sign, layer: layer, mag: mag}
 }
@@ -346,6 +432,14 @@ func (d *Decimal) SetSign(value float64) {
 	}
 }
 
+func (d *Decimal) GetLayer() float64 {
+	return d.layer
+}
+
+func (d *Decimal) GetMag() float64 {
+	return d.mag
+}
+
 func (d *Decimal) MantissaWithNDecimalPlaces(places int) float64 {
 	m := d.GetMantissa()
 	if math.IsNaN(m) {
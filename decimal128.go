@@ -0,0 +1,712 @@
+package breaketernity
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// EXP_LIMIT_128 is the magnitude at which a Decimal128 moves up a layer. It is just below 2^106, so every integer below it is exact.
+const EXP_LIMIT_128 float64 = 8e31
+
+const LAYER_DOWN_128 float64 = 31.903089986991944 // math.Log10(8e31)
+
+const FIRST_NEG_LAYER_128 float64 = 1 / EXP_LIMIT_128
+
+const MAX_SIGNIFICANT_DIGITS_128 float64 = 33
+
+// Decimal128 is a Decimal whose mag is a double-double instead of a float64, carrying about 32 significant digits instead of 15.
+// Layer 0 holds every integer below 8e31 exactly (so 1e20 + 1 is not lost), and layer 1 carries 32 significant digits of the exponent.
+// Arithmetic, logarithms, powers and rounding are computed at full precision.
+// The hyper 4 operations, Gamma and LambertW are computed at Decimal precision.
+type Decimal128 struct {
+	sign  float64
+	layer float64
+	mag   dd
+}
+
+// D128 is a function that creates a new Decimal128 instance from a given source.
+// Integers, *big.Int and *big.Float sources, and plain numeric strings, keep up to 32 significant digits.
+// Other sources are converted through D().
+func D128[S DecimalSource](source S) *Decimal128 {
+	switch v := any(source).(type) {
+	case *Decimal:
+		return decimal128FromDecimal(v)
+	case string:
+		return decimal128FromString(v)
+	case int:
+		return decimal128FromBigFloat(new(big.Float).SetInt64(int64(v)))
+	case int64:
+		return decimal128FromBigFloat(new(big.Float).SetInt64(v))
+	case uint:
+		return decimal128FromBigFloat(new(big.Float).SetUint64(uint64(v)))
+	case uint64:
+		return decimal128FromBigFloat(new(big.Float).SetUint64(v))
+	case *big.Int:
+		return decimal128FromBigFloat(new(big.Float).SetInt(v))
+	case *big.Float:
+		return decimal128FromBigFloat(v)
+	}
+	return decimal128FromDecimal(D(source))
+}
+
+// DFC128 creates a new Decimal128 from its components, like DFC.
+func DFC128(sign float64, layer float64, mag float64) *Decimal128 {
+	return dFC128(sign, layer, dd{mag, 0})
+}
+
+func dFC128(sign float64, layer float64, mag dd) *Decimal128 {
+	d := Decimal128{sign: sign, layer: layer, mag: mag}
+	return d.normalize()
+}
+
+func dFC128_NN(sign float64, layer float64, mag float64) *Decimal128 {
+	return &Decimal128{sign: sign, layer: layer, mag: dd{mag, 0}}
+}
+
+func decimal128NaN() *Decimal128 {
+	return dFC128_NN(math.NaN(), math.NaN(), math.NaN())
+}
+
+func decimal128FromDecimal128(d *Decimal128) *Decimal128 {
+	return &Decimal128{sign: d.sign, layer: d.layer, mag: d.mag}
+}
+
+func decimal128FromDecimal(d *Decimal) *Decimal128 {
+	if d.IsNaN() {
+		return decimal128NaN()
+	}
+	return dFC128(d.sign, d.layer, dd{d.mag, 0})
+}
+
+func decimal128FromBigFloat(f *big.Float) *Decimal128 {
+	if f.IsInf() || f.Sign() == 0 {
+		return decimal128FromDecimal(FromBigFloat(f))
+	}
+	hi, _ := f.Float64()
+	if math.Abs(hi) < 1e-290 || math.Abs(hi) > 1e290 {
+		// Keep clear of subnormal lo parts: go to layer 1, with log10(f) = log10(mant) + exp*log10(2) in double-double
+		mant := new(big.Float)
+		exp := f.MantExp(mant)
+		mag := ddAdd(ddLog10(ddFromBigFloat(mant.Abs(mant))), ddMulFloat64(ddMul(ddLn2, ddLog10E), float64(exp)))
+		return dFC128(float64(f.Sign()), 1, mag)
+	}
+	return dFC128(1, 0, ddFromBigFloat(f))
+}
+
+func decimal128FromString(s string) *Decimal128 {
+	plain := strings.TrimSpace(applyParseOptions(s, DefaultParseOptions()))
+	f, _, err := big.ParseFloat(plain, 10, 128, big.ToNearestEven)
+	if err == nil {
+		return decimal128FromBigFloat(f)
+	}
+	return decimal128FromDecimal(decimalFromString(s, false))
+}
+
+func (d *Decimal128) normalize() *Decimal128 {
+	// Any 0 is totally 0
+	if d.sign == 0 || (d.mag.hi == 0 && d.layer == 0) || (d.mag.hi == math.Inf(-1) && d.layer > 0 && !math.IsInf(d.layer, 0)) {
+		d.sign = 0
+		d.mag = dd{0, 0}
+		d.layer = 0
+		return d
+	}
+
+	// Extract sign from negative mag at layer 0
+	if d.layer == 0 && d.mag.hi < 0 {
+		d.mag = ddNeg(d.mag)
+		d.sign = -d.sign
+	}
+
+	// Handle infinities
+	if math.IsInf(d.mag.hi, 0) || math.IsInf(d.layer, 0) {
+		d.mag = dd{math.Inf(1), 0}
+		d.layer = math.Inf(1)
+		return d
+	}
+
+	// Handle shifting from layer 0 to negative layers
+	if d.layer == 0 && d.mag.hi < FIRST_NEG_LAYER_128 {
+		d.layer += 1
+		d.mag = ddLog10(d.mag)
+		return d
+	}
+
+	absMag := ddAbs(d.mag)
+	signMag := sign(d.mag.hi)
+
+	if absMag.hi >= EXP_LIMIT_128 {
+		d.layer += 1
+		d.mag = ddMulFloat64(ddLog10(absMag), signMag)
+		return d
+	} else {
+		for absMag.hi < LAYER_DOWN_128 && d.layer > 0 {
+			d.layer -= 1
+			if d.layer == 0 {
+				d.mag = ddPow10(d.mag)
+			} else {
+				d.mag = ddMulFloat64(ddPow10(absMag), signMag)
+				absMag = ddAbs(d.mag)
+				signMag = sign(d.mag.hi)
+			}
+		}
+		if d.layer == 0 {
+			if d.mag.hi < 0 {
+				// Extract sign from negative mag at layer 0
+				d.mag = ddNeg(d.mag)
+				d.sign = -d.sign
+			} else if d.mag.hi == 0 {
+				// Excessive rounding can give us all zeroes
+				d.sign = 0
+			}
+		}
+	}
+
+	if math.IsNaN(d.sign) || math.IsNaN(d.layer) || math.IsNaN(d.mag.hi) {
+		d.sign = math.NaN()
+		d.layer = math.NaN()
+		d.mag = dd{math.NaN(), math.NaN()}
+	}
+
+	return d
+}
+
+// ToDecimal rounds the Decimal128 to a Decimal.
+// Converting a Decimal to a Decimal128 and back gives the original Decimal.
+func (d *Decimal128) ToDecimal() *Decimal {
+	if d.IsNaN() {
+		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	}
+	if d.IsInf() {
+		return dFC_NN(d.sign, math.Inf(1), math.Inf(1))
+	}
+	if d.layer == 0 {
+		if d.mag.hi < EXP_LIMIT {
+			return decimalFromFloat64(d.sign * (d.mag.hi + d.mag.lo))
+		}
+		return dFC(d.sign, 1, ddLog10(d.mag).hi)
+	}
+	if d.layer == 1 && math.Abs(d.mag.hi) >= EXP_LIMIT {
+		return dFC(d.sign, 2, sign(d.mag.hi)*ddLog10(ddAbs(d.mag)).hi)
+	}
+	return dFC(d.sign, d.layer, d.mag.hi+d.mag.lo)
+}
+
+// ToFloat64 returns the Decimal128 as a float64.
+func (d *Decimal128) ToFloat64() float64 {
+	return d.ToDecimal().ToFloat64()
+}
+
+// ToString returns the Decimal128 as a string, with up to 31 significant digits.
+func (d *Decimal128) ToString() string {
+	if d.IsNaN() || d.IsInf() || d.layer >= 2 {
+		return d.ToDecimal().ToString()
+	}
+	if d.layer == 0 {
+		v := d.mag.hi + d.mag.lo
+		if (v < 1e21 && v > 1e-7) || v == 0 {
+			return signPrefix(d.sign) + trimDigits(d.mag.toBigFloat().Text('g', 31))
+		}
+		exponent := ddFloor(ddLog10(d.mag)).hi
+		mantissa := ddDiv(d.mag, ddPow10(dd{exponent, 0}))
+		return signPrefix(d.sign) + mantissaExponentString(mantissa, exponent, 31)
+	}
+	// The digits of the exponent use up part of the precision of mag
+	exponent := ddFloor(d.mag).hi
+	mantissa := ddPow10(ddSub(d.mag, dd{exponent, 0}))
+	digits := 31 - int(math.Ceil(math.Log10(math.Abs(exponent)+1)))
+	return signPrefix(d.sign) + mantissaExponentString(mantissa, exponent, max(digits, 1))
+}
+
+func mantissaExponentString(mantissa dd, exponent float64, digits int) string {
+	m := trimDigits(mantissa.toBigFloat().Text('g', digits))
+	if m == "10" {
+		m = "1"
+		exponent++
+	}
+	return m + "e" + strconv.FormatFloat(exponent, 'f', -1, 64)
+}
+
+func trimDigits(s string) string {
+	mantissa, exponent, hasExponent := strings.Cut(s, "e")
+	if strings.Contains(mantissa, ".") {
+		mantissa = strings.TrimRight(strings.TrimRight(mantissa, "0"), ".")
+	}
+	if hasExponent {
+		return mantissa + "e" + exponent
+	}
+	return mantissa
+}
+
+// IsNaN returns true if the Decimal128 is NaN
+func (d *Decimal128) IsNaN() bool {
+	return math.IsNaN(d.layer) || math.IsNaN(d.mag.hi) || math.IsNaN(d.sign)
+}
+
+// IsInf returns true if the Decimal128 is either positive or negative infinity
+func (d *Decimal128) IsInf() bool {
+	return math.IsInf(d.layer, 0) || math.IsInf(d.mag.hi, 0) || math.IsInf(d.sign, 0)
+}
+
+// Cmp returns 1 if d > other, -1 if d < other, and 0 if d == other.
+func (d *Decimal128) Cmp(other *Decimal128) int {
+	if d.sign > other.sign {
+		return 1
+	}
+	if d.sign < other.sign {
+		return -1
+	}
+	return int(d.sign) * d.CmpAbs(other)
+}
+
+// CmpAbs returns 1 if |d| > |other|, -1 if |d| < |other| and 0 if |d| == |other|.
+func (d *Decimal128) CmpAbs(other *Decimal128) int {
+	var layera float64
+	if d.mag.hi > 0 {
+		layera = d.layer
+	} else {
+		layera = -d.layer
+	}
+
+	var layerb float64
+	if other.mag.hi > 0 {
+		layerb = other.layer
+	} else {
+		layerb = -other.layer
+	}
+
+	if layera > layerb {
+		return 1
+	}
+
+	if layera < layerb {
+		return -1
+	}
+
+	return ddCmp(d.mag, other.mag)
+}
+
+// Eq returns true if d == other.
+func (d *Decimal128) Eq(other *Decimal128) bool {
+	return d.sign == other.sign && d.layer == other.layer && d.mag == other.mag
+}
+
+// Neq returns true if d != other.
+func (d *Decimal128) Neq(other *Decimal128) bool {
+	return !d.Eq(other)
+}
+
+// Lt returns true if d < other.
+func (d *Decimal128) Lt(other *Decimal128) bool {
+	return d.Cmp(other) == -1
+}
+
+// Lte returns true if d <= other.
+func (d *Decimal128) Lte(other *Decimal128) bool {
+	return d.Cmp(other) != 1
+}
+
+// Gt returns true if d > other.
+func (d *Decimal128) Gt(other *Decimal128) bool {
+	return d.Cmp(other) == 1
+}
+
+// Gte returns true if d >= other.
+func (d *Decimal128) Gte(other *Decimal128) bool {
+	return d.Cmp(other) != -1
+}
+
+// Max returns the larger of d and other.
+func (d *Decimal128) Max(other *Decimal128) *Decimal128 {
+	if d.Lt(other) {
+		return decimal128FromDecimal128(other)
+	}
+	return decimal128FromDecimal128(d)
+}
+
+// Min returns the smaller of d and other.
+func (d *Decimal128) Min(other *Decimal128) *Decimal128 {
+	if d.Gt(other) {
+		return decimal128FromDecimal128(other)
+	}
+	return decimal128FromDecimal128(d)
+}
+
+// MaxAbs returns the one of d and other with the larger absolute value.
+func (d *Decimal128) MaxAbs(other *Decimal128) *Decimal128 {
+	if d.CmpAbs(other) < 0 {
+		return decimal128FromDecimal128(other)
+	}
+	return decimal128FromDecimal128(d)
+}
+
+// EqTolerance returns true if d and other are equal within a relative tolerance, compared at Decimal precision.
+func (d *Decimal128) EqTolerance(other *Decimal128, tolerance float64) bool {
+	return d.ToDecimal().EqTolerance(other.ToDecimal(), tolerance)
+}
+
+// Abs returns the absolute value of the Decimal128
+func (d *Decimal128) Abs() *Decimal128 {
+	result := decimal128FromDecimal128(d)
+	if result.sign != 0 {
+		result.sign = 1
+	}
+	return result
+}
+
+// Neg returns the negative of the Decimal128
+func (d *Decimal128) Neg() *Decimal128 {
+	result := decimal128FromDecimal128(d)
+	result.sign = -result.sign
+	return result
+}
+
+// Recip returns the reciprocal (1/x) of the Decimal128
+func (d *Decimal128) Recip() *Decimal128 {
+	if d.mag.hi == 0 {
+		return decimal128NaN()
+	} else if math.IsInf(d.mag.hi, 1) {
+		return dFC128_NN(0, 0, 0)
+	} else if d.layer == 0 {
+		return dFC128(d.sign, 0, ddDiv(dd{1, 0}, d.mag))
+	} else {
+		return dFC128(d.sign, d.layer, ddNeg(d.mag))
+	}
+}
+
+// absLog10 returns log10(|d|) as a double-double, for layers 0 and 1.
+func (d *Decimal128) absLog10() dd {
+	if d.layer == 0 {
+		return ddLog10(d.mag)
+	}
+	return d.mag
+}
+
+// Add returns the sum of the Decimal128 and other
+func (d *Decimal128) Add(other *Decimal128) *Decimal128 {
+	if d.IsNaN() || other.IsNaN() {
+		return decimal128NaN()
+	}
+	if d.IsInf() && other.IsInf() && d.sign != other.sign {
+		return decimal128NaN()
+	}
+	if math.IsInf(d.layer, 0) {
+		return decimal128FromDecimal128(d)
+	}
+	if math.IsInf(other.layer, 0) {
+		return decimal128FromDecimal128(other)
+	}
+
+	if d.sign == 0 {
+		return decimal128FromDecimal128(other)
+	}
+	if other.sign == 0 {
+		return decimal128FromDecimal128(d)
+	}
+
+	if d.sign == -other.sign && d.layer == other.layer && d.mag == other.mag {
+		return dFC128_NN(0, 0, 0)
+	}
+
+	if d.layer >= 2 || other.layer >= 2 {
+		return d.MaxAbs(other)
+	}
+
+	var a, b *Decimal128
+	if d.CmpAbs(other) > 0 {
+		a, b = d, other
+	} else {
+		a, b = other, d
+	}
+
+	if a.layer == 0 && b.layer == 0 {
+		return dFC128(1, 0, ddAdd(ddMulFloat64(a.mag, a.sign), ddMulFloat64(b.mag, b.sign)))
+	}
+
+	// At least one side is in layer 1, so add in log10 space: a + b = a * (1 + b/a)
+	logA := a.absLog10()
+	diff := ddSub(logA, b.absLog10())
+	if diff.hi > MAX_SIGNIFICANT_DIGITS_128 {
+		return decimal128FromDecimal128(a)
+	}
+	mantissa := ddAdd(dd{a.sign, 0}, ddMulFloat64(ddPow10(ddNeg(diff)), b.sign))
+	if mantissa.hi == 0 {
+		return dFC128_NN(0, 0, 0)
+	}
+	return dFC128(sign(mantissa.hi), 1, ddAdd(logA, ddLog10(ddAbs(mantissa))))
+}
+
+// Subtract returns the difference between the Decimal128 and other
+func (d *Decimal128) Subtract(other *Decimal128) *Decimal128 {
+	return d.Add(other.Neg())
+}
+
+// Multiply returns the product of the Decimal128 and other
+func (d *Decimal128) Multiply(other *Decimal128) *Decimal128 {
+	if d.IsNaN() || other.IsNaN() {
+		return decimal128NaN()
+	}
+	if (d.IsInf() && other.sign == 0) || (d.sign == 0 && other.IsInf()) {
+		return decimal128NaN()
+	}
+	if d.IsInf() || other.IsInf() {
+		return dFC128_NN(d.sign*other.sign, math.Inf(1), math.Inf(1))
+	}
+	if d.sign == 0 || other.sign == 0 {
+		return dFC128_NN(0, 0, 0)
+	}
+
+	if d.layer == 0 && other.layer == 0 {
+		return dFC128(d.sign*other.sign, 0, ddMul(d.mag, other.mag))
+	}
+
+	// a * b = 10^(log10|a| + log10|b|)
+	result := d.AbsLog10().Add(other.AbsLog10()).PowBase10()
+	result.sign *= d.sign * other.sign
+	return result
+}
+
+// Divide returns the quotient of the Decimal128 and other
+func (d *Decimal128) Divide(other *Decimal128) *Decimal128 {
+	return d.Multiply(other.Recip())
+}
+
+// Modulo returns the remainder of d divided by other, using truncated division like Go's % operator
+// Computed at full precision in layer 0, and at Decimal precision otherwise
+func (d *Decimal128) Modulo(other *Decimal128) *Decimal128 {
+	if other.sign == 0 {
+		return dFC128_NN(0, 0, 0)
+	}
+	if d.layer == 0 && other.layer == 0 {
+		quotient := ddTrunc(ddDiv(d.mag, other.mag))
+		return dFC128(d.sign, 0, ddSub(d.mag, ddMul(quotient, other.mag)))
+	}
+	return decimal128FromDecimal(d.ToDecimal().Modulo(other.ToDecimal()))
+}
+
+// AbsLog10 returns the base10 logarithm of the absolute value of the Decimal128
+func (d *Decimal128) AbsLog10() *Decimal128 {
+	if d.sign == 0 {
+		return decimal128NaN()
+	} else if d.layer > 0 {
+		return dFC128(sign(d.mag.hi), d.layer-1, ddAbs(d.mag))
+	} else {
+		return dFC128(1, 0, ddLog10(d.mag))
+	}
+}
+
+// Log10 returns the base10 logarithm of the Decimal128
+func (d *Decimal128) Log10() *Decimal128 {
+	if d.sign <= 0 {
+		return decimal128NaN()
+	}
+	return d.AbsLog10()
+}
+
+// Ln returns the natural logarithm of the Decimal128
+func (d *Decimal128) Ln() *Decimal128 {
+	if d.sign <= 0 {
+		return decimal128NaN()
+	} else if d.layer == 0 {
+		return dFC128(1, 0, ddLn(d.mag))
+	}
+	return d.Log10().Multiply(dFC128(1, 0, ddLn10))
+}
+
+// Log2 returns the base2 logarithm of the Decimal128
+func (d *Decimal128) Log2() *Decimal128 {
+	return d.Ln().Divide(dFC128(1, 0, ddLn2))
+}
+
+// Log returns the logarithm of the Decimal128 to the given base
+func (d *Decimal128) Log(base *Decimal128) *Decimal128 {
+	if d.sign <= 0 || base.sign <= 0 {
+		return decimal128NaN()
+	}
+	if base.sign == 1 && base.layer == 0 && base.mag == (dd{1, 0}) {
+		return decimal128NaN()
+	}
+	if d.layer == 0 && base.layer == 0 {
+		return dFC128(1, 0, ddDiv(ddLn(d.mag), ddLn(base.mag)))
+	}
+	return d.Log10().Divide(base.Log10())
+}
+
+// PowBase10 returns 10 raised to the power of the Decimal128
+func (d *Decimal128) PowBase10() *Decimal128 {
+	if d.IsNaN() {
+		return decimal128NaN()
+	}
+	if d.IsInf() {
+		if d.sign > 0 {
+			return dFC128_NN(1, math.Inf(1), math.Inf(1))
+		}
+		return dFC128_NN(0, 0, 0)
+	}
+
+	if d.layer == 0 {
+		exponent := ddMulFloat64(d.mag, d.sign)
+		if math.Abs(exponent.hi) >= LAYER_DOWN_128 {
+			return dFC128(1, 1, exponent)
+		}
+		return dFC128(1, 0, ddPow10(exponent))
+	}
+
+	// Handle all 4 layer 1+ cases
+	if d.sign > 0 && d.mag.hi >= 0 {
+		return dFC128(d.sign, d.layer+1, d.mag)
+	}
+	if d.sign < 0 && d.mag.hi >= 0 {
+		return dFC128(-d.sign, d.layer+1, ddNeg(d.mag))
+	}
+	// Both negative mag cases result in the same outcome
+	return dFC128_NN(1, 0, 1)
+}
+
+// PowBaseE returns e raised to the power of the Decimal128
+func (d *Decimal128) PowBaseE() *Decimal128 {
+	return d.Multiply(dFC128(1, 0, ddLog10E)).PowBase10()
+}
+
+// Pow returns the Decimal128 raised to the power of other
+func (d *Decimal128) Pow(other *Decimal128) *Decimal128 {
+	if d.sign == 0 {
+		if other.sign == 0 {
+			return dFC128_NN(1, 0, 1)
+		}
+		return dFC128_NN(0, 0, 0)
+	}
+	if d.sign == 1 && d.layer == 0 && d.mag == (dd{1, 0}) {
+		return dFC128_NN(1, 0, 1)
+	}
+	if other.sign == 0 {
+		return dFC128_NN(1, 0, 1)
+	}
+	if other.sign == 1 && other.layer == 0 && other.mag == (dd{1, 0}) {
+		return decimal128FromDecimal128(d)
+	}
+
+	result := d.AbsLog10().Multiply(other).PowBase10()
+
+	if d.sign == -1 {
+		if other.layer != 0 {
+			// Huge exponents are all even, tiny ones aren't integers
+			if other.mag.hi > 0 {
+				return result
+			}
+			return decimal128NaN()
+		}
+		parity := ddSub(other.mag, ddMulFloat64(ddFloor(ddMulFloat64(other.mag, 0.5)), 2))
+		if parity == (dd{1, 0}) {
+			return result.Neg()
+		} else if parity == (dd{0, 0}) {
+			return result
+		}
+		return decimal128NaN()
+	}
+
+	return result
+}
+
+// Root returns the "degree"th root of the Decimal128
+func (d *Decimal128) Root(degree *Decimal128) *Decimal128 {
+	return d.Pow(degree.Recip())
+}
+
+// Sqrt returns the square root of the Decimal128
+func (d *Decimal128) Sqrt() *Decimal128 {
+	if d.sign < 0 {
+		return decimal128NaN()
+	}
+	if d.layer == 0 {
+		return dFC128(d.sign, 0, ddSqrt(d.mag))
+	}
+	return d.Pow(dFC128_NN(1, 0, 0.5))
+}
+
+// Floor rounds the Decimal128 down to the nearest integer
+func (d *Decimal128) Floor() *Decimal128 {
+	return d.roundWith(ddFloor)
+}
+
+// Ceil rounds the Decimal128 up to the nearest integer
+func (d *Decimal128) Ceil() *Decimal128 {
+	return d.roundWith(ddCeil)
+}
+
+// Round rounds the Decimal128 to the nearest integer
+func (d *Decimal128) Round() *Decimal128 {
+	return d.roundWith(ddRound)
+}
+
+// Trunc returns the integer part of the Decimal128
+func (d *Decimal128) Trunc() *Decimal128 {
+	return d.roundWith(ddTrunc)
+}
+
+func (d *Decimal128) roundWith(round func(dd) dd) *Decimal128 {
+	if d.IsNaN() || d.IsInf() || d.sign == 0 {
+		return decimal128FromDecimal128(d)
+	}
+	if d.layer == 0 || d.mag.hi < 0 {
+		// Values in negative layers are too close to 0 to be anything but 0 after rounding
+		value := ddMulFloat64(d.mag, d.sign)
+		if d.layer > 0 {
+			value = dd{d.sign * FIRST_NEG_LAYER_128, 0}
+		}
+		return dFC128(1, 0, round(value))
+	}
+	// Everything from 8e31 upwards is an integer already
+	return decimal128FromDecimal128(d)
+}
+
+// Factorial returns the factorial of the Decimal128, computed at Decimal precision
+func (d *Decimal128) Factorial() *Decimal128 {
+	return decimal128FromDecimal(d.ToDecimal().Factorial())
+}
+
+// Gamma returns the Gamma function of the Decimal128, computed at Decimal precision
+func (d *Decimal128) Gamma() *Decimal128 {
+	return decimal128FromDecimal(d.ToDecimal().Gamma())
+}
+
+// LambertW returns the Lambert W function of the Decimal128, computed at Decimal precision
+func (d *Decimal128) LambertW(principal bool) *Decimal128 {
+	return decimal128FromDecimal(d.ToDecimal().LambertW(principal))
+}
+
+// Tetrate is the result of exponentiating 'd' to 'payload' 'height' times in a row, computed at Decimal precision
+func (d *Decimal128) Tetrate(height float64, payload *Decimal128, linear bool) *Decimal128 {
+	return decimal128FromDecimal(d.ToDecimal().Tetrate(height, payload.ToDecimal(), linear))
+}
+
+// IteratedExp returns the result of applying exp(base) 'height' times, computed at Decimal precision
+func (d *Decimal128) IteratedExp(height float64, payload *Decimal128, linear bool) *Decimal128 {
+	return d.Tetrate(height, payload, linear)
+}
+
+// IteratedLog returns the result of applying log(base) 'times' times, computed at Decimal precision
+func (d *Decimal128) IteratedLog(base *Decimal128, times float64, linear bool) *Decimal128 {
+	return decimal128FromDecimal(d.ToDecimal().IteratedLog(base.ToDecimal(), times, linear))
+}
+
+// LayerAdd10 adds/removes layers from a Decimal128, even fractional layers, computed at Decimal precision
+func (d *Decimal128) LayerAdd10(diff *Decimal128, linear bool) *Decimal128 {
+	return decimal128FromDecimal(d.ToDecimal().LayerAdd10(diff.ToDecimal(), linear))
+}
+
+// LayerAdd is like adding "diff" to the number's slog(base) representation, computed at Decimal precision
+func (d *Decimal128) LayerAdd(diff *Decimal128, base *Decimal128, linear bool) *Decimal128 {
+	return decimal128FromDecimal(d.ToDecimal().LayerAdd(diff.ToDecimal(), base.ToDecimal(), linear))
+}
+
+// Slog is the super-logarithm of the Decimal128 to the given base, computed at Decimal precision
+func (d *Decimal128) Slog(base *Decimal128, iterations float64, linear bool) *Decimal128 {
+	return decimal128FromDecimal(d.ToDecimal().Slog(base.ToDecimal(), iterations, linear))
+}
+
+// Pentate is the result of tetrating 'height' times in a row, computed at Decimal precision
+func (d *Decimal128) Pentate(height float64, payload *Decimal128, linear bool) *Decimal128 {
+	return decimal128FromDecimal(d.ToDecimal().Pentate(height, payload.ToDecimal(), linear))
+}
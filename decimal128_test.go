@@ -0,0 +1,56 @@
+package breaketernity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDecimal128PowNegativeBase(t *testing.T) {
+	base := D128(-2)
+	if got := base.Pow(D128("1e-400")); !got.IsNaN() {
+		t.Errorf("-2^1e-400 = %s, want NaN", got.ToString())
+	}
+	if got := base.Pow(D128("1e400")); got.IsNaN() || got.sign != 1 {
+		t.Errorf("-2^1e400 = %s, want a positive number", got.ToString())
+	}
+	if got := base.Pow(D128(3)); got.ToFloat64() != -8 {
+		t.Errorf("-2^3 = %s, want -8", got.ToString())
+	}
+}
+
+func TestDecimal128ParseBeyondFloat64Range(t *testing.T) {
+	// Past 1e290 the value moves to layer 1, whose mag must keep the double-double precision of the input
+	cases := []struct {
+		in, want string
+	}{
+		{"1.2345678901234567890123456789e291", "1.234567890123456789012345679e291"},
+		{"-1.2345678901234567890123456789e291", "-1.234567890123456789012345679e291"},
+		{"9.8765432109876543210987654321e-295", "9.876543210987654321098765432e-295"},
+		// A larger exponent leaves fewer digits of the mag for the mantissa
+		{"3.1415926535897932384626433832e12345", "3.1415926535897932384626434e12345"},
+		{"1.5e400", "1.5e400"},
+	}
+	for _, c := range cases {
+		if got := D128(c.in).ToString(); got != c.want {
+			t.Errorf("D128(%q) = %s, want %s", c.in, got, c.want)
+		}
+	}
+	// Both sides of the switch to layer 1 agree
+	below, above := D128("9.99999999999999999999999999e289"), D128("1.00000000000000000000000001e290")
+	if !below.Lt(above) {
+		t.Errorf("%s is not below %s", below.ToString(), above.ToString())
+	}
+}
+
+func TestDecimal128KeepsDigitsBeyondFloat64(t *testing.T) {
+	one := D128(1)
+	if got := one.Add(D128("1e-25")).Subtract(one); math.Abs(got.ToFloat64()-1e-25) > 1e-40 {
+		t.Errorf("(1 + 1e-25) - 1 = %s, want 1e-25", got.ToString())
+	}
+	if got := one.Divide(D128(3)).Multiply(D128(3)).Subtract(one).Abs(); got.Gt(D128("1e-30")) {
+		t.Errorf("1/3*3 - 1 = %s, want below 1e-30", got.ToString())
+	}
+	if got := D128("1e300").Multiply(D128("1.0000000000000000000001")).Divide(D128("1e300")).Subtract(one); math.Abs(got.ToFloat64()-1e-22) > 1e-30 {
+		t.Errorf("1e300 * (1 + 1e-22) / 1e300 - 1 = %s, want 1e-22", got.ToString())
+	}
+}
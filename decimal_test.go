@@ -0,0 +1,102 @@
+package breaketernity
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseMissingMantissa(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"e5", "1e5"},
+		{"-e5", "-1e5"},
+		{"ee20", "1e1e20"},
+		{"-ee20", "-1e1e20"},
+	}
+	for _, c := range cases {
+		if got := D(c.in); !got.Eq(D(c.want)) {
+			t.Errorf("D(%q) = %s, want %s", c.in, got.ToString(), c.want)
+		}
+	}
+}
+
+func TestParseFloatJS(t *testing.T) {
+	cases := []struct {
+		in   string
+		want float64
+	}{
+		{"12", 12},
+		{" -1.5e3", -1500},
+		{"+.5", 0.5},
+		{"5.", 5},
+		{"3x", 3},
+		{"2e", 2},
+		{"2e+", 2},
+		{"2e-2z", 0.02},
+		{"1e400", math.Inf(1)},
+		{"-Infinity", math.Inf(-1)},
+		{"1e-400", 0},
+	}
+	for _, c := range cases {
+		if got := parseFloatJS(c.in); got != c.want {
+			t.Errorf("parseFloatJS(%q) = %g, want %g", c.in, got, c.want)
+		}
+	}
+	for _, in := range []string{"", "-", ".", "e5", "x1", "Inf", "NaN"} {
+		if got := parseFloatJS(in); !math.IsNaN(got) {
+			t.Errorf("parseFloatJS(%q) = %g, want NaN", in, got)
+		}
+	}
+}
+
+func TestParseHyperOperatorFormats(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"2^3", "8"},
+		{"2^3x", "8"},
+		{"2^^3", "16"},
+		{"2^^^2", "4"},
+	}
+	for _, c := range cases {
+		if got := D(c.in); math.Abs(got.ToFloat64()-D(c.want).ToFloat64()) > 1e-12 {
+			t.Errorf("D(%q) = %s, want %s", c.in, got.ToString(), c.want)
+		}
+	}
+	// An empty height used to index past the end of the string
+	for _, in := range []string{"pt2", "-pt2", "p2", "F2"} {
+		D(in)
+	}
+}
+
+func TestParseSubnormal(t *testing.T) {
+	for _, s := range []string{"1e-310", "1e-321", "2.5e-320"} {
+		d := D(s)
+		mantissa, exponent := parseFloatJS(s[:strings.Index(s, "e")]), parseFloatJS(s[strings.Index(s, "e")+1:])
+		want := exponent + math.Log10(mantissa)
+		if d.layer != 1 || math.Abs(d.mag-want) > 1e-12 {
+			t.Errorf("D(%q) = {%g, %g, %.15g}, want mag %.15g on layer 1", s, d.sign, d.layer, d.mag, want)
+		}
+	}
+}
+
+func TestParseLayerNotation(t *testing.T) {
+	cases := []struct {
+		in               string
+		sign, layer, mag float64
+	}{
+		{"(e^6)20", 1, 6, 20},
+		{"-(e^6)20", -1, 6, 20},
+		{"(e^7)1.5", 1, 6, math.Pow(10, 1.5)},
+	}
+	for _, c := range cases {
+		if got := D(c.in); got.sign != c.sign || got.layer != c.layer || math.Abs(got.mag-c.mag) > 1e-12 {
+			t.Errorf("D(%q) = {%g, %g, %g}, want {%g, %g, %g}", c.in, got.sign, got.layer, got.mag, c.sign, c.layer, c.mag)
+		}
+	}
+	if got := D("(e^1.5)20"); got.layer == 1.5 {
+		t.Errorf("D(\"(e^1.5)20\") kept the fractional layer")
+	}
+}
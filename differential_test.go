@@ -0,0 +1,353 @@
+package breaketernity
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Differential tests of the layer 0 and layer 1 arithmetic against math/big. The value of every Decimal
+// involved, sign*mag on layer 0 and sign*10^mag on layer 1, is computed exactly enough at differentialPrec
+// bits for the reference results to be correct to far below a float64 ulp. Run with
+//
+//	go test -run TestDifferential -v -differential-report=report.md
+//
+// to see the relative error statistics of each operation.
+
+var differentialReport = flag.String("differential-report", "", "write a Markdown report of the differential error statistics to this file")
+
+const (
+	// Precision in bits of the reference values
+	differentialPrec = 256
+	// Random operands per operation, and in -short mode
+	differentialSamples      = 2000
+	differentialShortSamples = 200
+	// Operands are drawn with |log10(x)| up to this, which covers layer 0 and the start of layer 1.
+	// The resolution of layer 1 values shrinks as their exponent grows, so the budgets hold for this range only.
+	differentialMaxLog10 = 1000
+)
+
+// differentialOp computes an operation with Decimal and its reference result with big.Float
+type differentialOp struct {
+	name string
+	// budget is the largest relative error accepted, see differentialError
+	budget float64
+	// operands draws the operands of one sample
+	operands func(r *rand.Rand) []*Decimal
+	decimal  func(x []*Decimal) *Decimal
+	// reference returns the exact result from the exact values of the operands
+	reference func(x []*big.Float) *big.Float
+	// relativeToOperands measures the error relative to the largest operand instead of the result,
+	// for operations where cancellation makes the result arbitrarily small
+	relativeToOperands bool
+}
+
+var differentialOps = []differentialOp{
+	{
+		name:               "Add",
+		budget:             1e-12,
+		operands:           func(r *rand.Rand) []*Decimal { return []*Decimal{randomOperand(r), randomOperand(r)} },
+		decimal:            func(x []*Decimal) *Decimal { return x[0].Add(x[1]) },
+		reference:          func(x []*big.Float) *big.Float { return newBig().Add(x[0], x[1]) },
+		relativeToOperands: true,
+	},
+	{
+		name:      "Multiply",
+		budget:    1e-12,
+		operands:  func(r *rand.Rand) []*Decimal { return []*Decimal{randomOperand(r), randomOperand(r)} },
+		decimal:   func(x []*Decimal) *Decimal { return x[0].Multiply(x[1]) },
+		reference: func(x []*big.Float) *big.Float { return newBig().Mul(x[0], x[1]) },
+	},
+	{
+		name:      "Divide",
+		budget:    1e-12,
+		operands:  func(r *rand.Rand) []*Decimal { return []*Decimal{randomOperand(r), randomOperand(r)} },
+		decimal:   func(x []*Decimal) *Decimal { return x[0].Divide(x[1]) },
+		reference: func(x []*big.Float) *big.Float { return newBig().Quo(x[0], x[1]) },
+	},
+	{
+		// Positive bases, with exponents that keep the result on layer 0 or 1
+		name:   "Pow",
+		budget: 1e-11,
+		operands: func(r *rand.Rand) []*Decimal {
+			base := randomOperand(r).Abs()
+			maxExponent := differentialMaxLog10 / math.Max(math.Abs(base.AbsLog10().ToFloat64()), 1)
+			return []*Decimal{base, D((2*r.Float64() - 1) * maxExponent)}
+		},
+		decimal:   func(x []*Decimal) *Decimal { return x[0].Pow(x[1]) },
+		reference: func(x []*big.Float) *big.Float { return bigExp(newBig().Mul(x[1], bigLn(x[0]))) },
+	},
+	{
+		// Sqrt goes through layer 2, so it is as accurate as the exponent of the operand. Like break_eternity.js,
+		// it is NaN below FIRST_NEG_LAYER, which is covered by the conformance suite instead.
+		name:   "Sqrt",
+		budget: 1e-11,
+		operands: func(r *rand.Rand) []*Decimal {
+			minLog10 := math.Log10(FIRST_NEG_LAYER)
+			return []*Decimal{D(minLog10 + (differentialMaxLog10-minLog10)*r.Float64()).PowBase10()}
+		},
+		decimal:   func(x []*Decimal) *Decimal { return x[0].Sqrt() },
+		reference: func(x []*big.Float) *big.Float { return newBig().Sqrt(x[0]) },
+	},
+	{
+		name:      "Ln",
+		budget:    1e-15,
+		operands:  func(r *rand.Rand) []*Decimal { return []*Decimal{randomOperand(r).Abs()} },
+		decimal:   func(x []*Decimal) *Decimal { return x[0].Ln() },
+		reference: func(x []*big.Float) *big.Float { return bigLn(x[0]) },
+	},
+	{
+		// Around the range where the fraction still matters
+		name:      "Floor",
+		budget:    1e-15,
+		operands:  func(r *rand.Rand) []*Decimal { return []*Decimal{randomRoundingOperand(r)} },
+		decimal:   func(x []*Decimal) *Decimal { return x[0].Floor() },
+		reference: func(x []*big.Float) *big.Float { return bigFloor(x[0]) },
+	},
+	{
+		name:     "Round",
+		budget:   1e-15,
+		operands: func(r *rand.Rand) []*Decimal { return []*Decimal{randomRoundingOperand(r)} },
+		decimal:  func(x []*Decimal) *Decimal { return x[0].Round() },
+		reference: func(x []*big.Float) *big.Float {
+			// Half away from zero, like Math.round on the mag
+			half := newBig().SetFloat64(0.5)
+			rounded := bigFloor(newBig().Add(newBig().Abs(x[0]), half))
+			if x[0].Sign() < 0 {
+				rounded.Neg(rounded)
+			}
+			return rounded
+		},
+	},
+}
+
+// randomOperand draws a nonzero value with a random sign and log10(|x|) uniform in [-differentialMaxLog10, differentialMaxLog10]
+func randomOperand(r *rand.Rand) *Decimal {
+	sign := 1.
+	if r.IntN(2) == 0 {
+		sign = -1
+	}
+	return D(sign).Multiply(D((2*r.Float64() - 1) * differentialMaxLog10).PowBase10())
+}
+
+// randomRoundingOperand draws a value with a random sign and log10(|x|) uniform in [-3, 20]
+func randomRoundingOperand(r *rand.Rand) *Decimal {
+	sign := 1.
+	if r.IntN(2) == 0 {
+		sign = -1
+	}
+	return D(sign).Multiply(D(-3 + 23*r.Float64()).PowBase10())
+}
+
+func newBig() *big.Float {
+	return new(big.Float).SetPrec(differentialPrec)
+}
+
+// bigValue returns the value of a finite Decimal on layer 0 or 1
+func bigValue(d *Decimal) *big.Float {
+	if d.layer == 0 {
+		return newBig().SetFloat64(d.sign * d.mag)
+	}
+	value := bigExp(newBig().Mul(newBig().SetFloat64(d.mag), bigLn10()))
+	if d.sign < 0 {
+		value.Neg(value)
+	}
+	return value
+}
+
+// bigLn2 and bigLn10 are computed once with the atanh series of bigLn
+var (
+	bigLn2 = sync.OnceValue(func() *big.Float {
+		return bigAtanhLn(newBig().SetInt64(2))
+	})
+	bigLn10 = sync.OnceValue(func() *big.Float {
+		// ln(10) = 3 ln(2) + ln(1.25)
+		ln := newBig().Mul(newBig().SetInt64(3), bigLn2())
+		return ln.Add(ln, bigAtanhLn(newBig().SetFloat64(1.25)))
+	})
+)
+
+// bigAtanhLn returns ln(x) = 2 atanh((x-1)/(x+1)), which converges quickly for x in [0.5, 2]
+func bigAtanhLn(x *big.Float) *big.Float {
+	one := newBig().SetInt64(1)
+	z := newBig().Quo(newBig().Sub(x, one), newBig().Add(x, one))
+	z2 := newBig().Mul(z, z)
+	sum := newBig()
+	term := newBig().Set(z)
+	for n := int64(1); ; n += 2 {
+		next := newBig().Quo(term, newBig().SetInt64(n))
+		if next.Sign() == 0 || next.MantExp(nil)-sum.MantExp(nil) < -differentialPrec-8 && sum.Sign() != 0 {
+			break
+		}
+		sum.Add(sum, next)
+		term.Mul(term, z2)
+	}
+	return sum.Mul(sum, newBig().SetInt64(2))
+}
+
+// bigLn returns the natural logarithm of a positive x, from its mantissa in [0.5, 1) and binary exponent
+func bigLn(x *big.Float) *big.Float {
+	mant := newBig()
+	exp := x.MantExp(mant)
+	ln := newBig().Mul(newBig().SetInt64(int64(exp)), bigLn2())
+	return ln.Add(ln, bigAtanhLn(mant))
+}
+
+// bigExp returns e^x, as 2^k * e^r with |r| <= ln(2)/2 and e^r from its Taylor series at r/2^16, squared back
+func bigExp(x *big.Float) *big.Float {
+	ln2 := bigLn2()
+	xf, _ := x.Float64()
+	k := math.Round(xf / math.Ln2)
+	r := newBig().Sub(x, newBig().Mul(newBig().SetFloat64(k), ln2))
+	const halvings = 16
+	r.SetMantExp(r, -halvings)
+
+	sum := newBig().SetInt64(1)
+	term := newBig().SetInt64(1)
+	for n := int64(1); ; n++ {
+		term.Mul(term, r)
+		term.Quo(term, newBig().SetInt64(n))
+		if term.Sign() == 0 || term.MantExp(nil) < -differentialPrec-8 {
+			break
+		}
+		sum.Add(sum, term)
+	}
+	for range halvings {
+		sum.Mul(sum, sum)
+	}
+	return sum.SetMantExp(sum, int(k))
+}
+
+// bigFloor rounds x down to an integer
+func bigFloor(x *big.Float) *big.Float {
+	if x.IsInt() {
+		return newBig().Set(x)
+	}
+	i, _ := x.Int(nil)
+	floor := newBig().SetInt(i)
+	if x.Sign() < 0 {
+		floor.Sub(floor, newBig().SetInt64(1))
+	}
+	return floor
+}
+
+// differentialError returns |got - want| / |scale|, or |got - want| if scale is 0
+func differentialError(got *big.Float, want *big.Float, scale *big.Float) float64 {
+	diff := newBig().Sub(got, want)
+	diff.Abs(diff)
+	if scale.Sign() != 0 {
+		diff.Quo(diff, newBig().Abs(scale))
+	}
+	err, _ := diff.Float64()
+	return err
+}
+
+// differentialStats summarizes the relative errors of one operation
+type differentialStats struct {
+	op     differentialOp
+	errors []float64
+	// worst holds the operands of the largest error
+	worst      []*Decimal
+	worstError float64
+}
+
+func (s *differentialStats) add(operands []*Decimal, err float64) {
+	s.errors = append(s.errors, err)
+	if err > s.worstError || s.worst == nil {
+		s.worstError = err
+		s.worst = operands
+	}
+}
+
+func (s *differentialStats) quantile(q float64) float64 {
+	sorted := slices.Sorted(slices.Values(s.errors))
+	return sorted[int(q*float64(len(sorted)-1))]
+}
+
+func (s *differentialStats) mean() float64 {
+	sum := 0.
+	for _, err := range s.errors {
+		sum += err
+	}
+	return sum / float64(len(s.errors))
+}
+
+func TestDifferential(t *testing.T) {
+	samples := differentialSamples
+	if testing.Short() {
+		samples = differentialShortSamples
+	}
+
+	var stats []*differentialStats
+	for i, op := range differentialOps {
+		s := &differentialStats{op: op}
+		stats = append(stats, s)
+		r := rand.New(rand.NewPCG(43, uint64(i)))
+		t.Run(op.name, func(t *testing.T) {
+			for range samples {
+				operands := op.operands(r)
+				got := op.decimal(operands)
+				if got.IsNaN() || got.IsInf() || got.layer > 1 {
+					t.Errorf("%s = %v, outside of layers 0 and 1", differentialCase(op.name, operands), *got)
+					continue
+				}
+				values := make([]*big.Float, len(operands))
+				for j, operand := range operands {
+					values[j] = bigValue(operand)
+				}
+				want := op.reference(values)
+				scale := want
+				if op.relativeToOperands {
+					scale = newBig()
+					for _, value := range values {
+						if newBig().Abs(value).Cmp(newBig().Abs(scale)) > 0 {
+							scale = value
+						}
+					}
+				}
+				s.add(operands, differentialError(bigValue(got), want, scale))
+			}
+			if s.worstError > op.budget {
+				t.Errorf("relative error %.3g of %s exceeds %.3g", s.worstError, differentialCase(op.name, s.worst), op.budget)
+			}
+		})
+	}
+
+	report := differentialReportText(stats)
+	t.Log("\n" + report)
+	if *differentialReport != "" {
+		if err := os.WriteFile(*differentialReport, []byte(report), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func differentialReportText(stats []*differentialStats) string {
+	var b strings.Builder
+	b.WriteString("| Operation | Samples | Mean | Median | p99 | Max | Budget | Worst case |\n")
+	b.WriteString("|---|---|---|---|---|---|---|---|\n")
+	for _, s := range stats {
+		if len(s.errors) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "| %s | %d | %.3g | %.3g | %.3g | %.3g | %.3g | %s |\n", s.op.name, len(s.errors),
+			s.mean(), s.quantile(0.5), s.quantile(0.99), s.worstError, s.op.budget, differentialCase(s.op.name, s.worst))
+	}
+	return b.String()
+}
+
+// differentialCase formats an operation on its operands, e.g. "Add(1e400, 2)"
+func differentialCase(name string, operands []*Decimal) string {
+	formatted := make([]string, len(operands))
+	for i, operand := range operands {
+		formatted[i] = operand.ToString()
+	}
+	return name + "(" + strings.Join(formatted, ", ") + ")"
+}
@@ -0,0 +1,218 @@
+package breaketernity
+
+import (
+	"math"
+	"math/big"
+)
+
+// dd is a double-double: an unevaluated sum hi + lo with |lo| <= ulp(hi)/2, carrying about 32 significant digits.
+type dd struct {
+	hi float64
+	lo float64
+}
+
+var ddLn2 = dd{0.6931471805599453, 2.3190468138462996e-17}
+
+var ddLn10 = dd{2.302585092994046, -2.1707562233822494e-16}
+
+var ddLog10E = dd{0.4342944819032518, 1.098319650216765e-17}
+
+const DD_EPSILON float64 = 4.93038065763132e-32 // 2^-104
+
+func ddFromFloat64(f float64) dd {
+	return dd{f, 0}
+}
+
+func ddFromBigFloat(f *big.Float) dd {
+	hi, _ := f.Float64()
+	if math.IsInf(hi, 0) || hi == 0 {
+		return dd{hi, 0}
+	}
+	rest := new(big.Float).SetPrec(f.Prec()).Sub(f, new(big.Float).SetFloat64(hi))
+	lo, _ := rest.Float64()
+	return dd{hi, lo}
+}
+
+func (a dd) toBigFloat() *big.Float {
+	result := new(big.Float).SetPrec(128).SetFloat64(a.hi)
+	return result.Add(result, new(big.Float).SetFloat64(a.lo))
+}
+
+func twoSum(a, b float64) (float64, float64) {
+	s := a + b
+	bb := s - a
+	return s, (a - (s - bb)) + (b - bb)
+}
+
+func quickTwoSum(a, b float64) (float64, float64) {
+	s := a + b
+	return s, b - (s - a)
+}
+
+func twoProd(a, b float64) (float64, float64) {
+	p := a * b
+	return p, math.FMA(a, b, -p)
+}
+
+func ddAdd(a, b dd) dd {
+	if math.IsInf(a.hi, 0) || math.IsInf(b.hi, 0) || math.IsNaN(a.hi) || math.IsNaN(b.hi) {
+		return dd{a.hi + b.hi, 0}
+	}
+	s1, s2 := twoSum(a.hi, b.hi)
+	t1, t2 := twoSum(a.lo, b.lo)
+	s2 += t1
+	s1, s2 = quickTwoSum(s1, s2)
+	s2 += t2
+	s1, s2 = quickTwoSum(s1, s2)
+	return dd{s1, s2}
+}
+
+func ddNeg(a dd) dd {
+	return dd{-a.hi, -a.lo}
+}
+
+func ddSub(a, b dd) dd {
+	return ddAdd(a, ddNeg(b))
+}
+
+func ddMul(a, b dd) dd {
+	p1, p2 := twoProd(a.hi, b.hi)
+	if math.IsInf(p1, 0) || math.IsNaN(p1) || p1 == 0 {
+		return dd{p1, 0}
+	}
+	p2 += a.hi*b.lo + a.lo*b.hi
+	p1, p2 = quickTwoSum(p1, p2)
+	return dd{p1, p2}
+}
+
+func ddMulFloat64(a dd, b float64) dd {
+	return ddMul(a, dd{b, 0})
+}
+
+func ddDiv(a, b dd) dd {
+	q1 := a.hi / b.hi
+	if math.IsInf(q1, 0) || math.IsNaN(q1) || q1 == 0 {
+		return dd{q1, 0}
+	}
+	r := ddSub(a, ddMulFloat64(b, q1))
+	q2 := r.hi / b.hi
+	r = ddSub(r, ddMulFloat64(b, q2))
+	q3 := r.hi / b.hi
+	q1, q2 = quickTwoSum(q1, q2)
+	return ddAdd(dd{q1, q2}, dd{q3, 0})
+}
+
+func ddCmp(a, b dd) int {
+	if a.hi < b.hi || (a.hi == b.hi && a.lo < b.lo) {
+		return -1
+	}
+	if a.hi > b.hi || (a.hi == b.hi && a.lo > b.lo) {
+		return 1
+	}
+	return 0
+}
+
+func ddAbs(a dd) dd {
+	if a.hi < 0 {
+		return ddNeg(a)
+	}
+	return a
+}
+
+func ddFloor(a dd) dd {
+	hi := math.Floor(a.hi)
+	lo := 0.
+	if hi == a.hi {
+		lo = math.Floor(a.lo)
+		hi, lo = quickTwoSum(hi, lo)
+	}
+	return dd{hi, lo}
+}
+
+func ddCeil(a dd) dd {
+	return ddNeg(ddFloor(ddNeg(a)))
+}
+
+func ddTrunc(a dd) dd {
+	if a.hi < 0 {
+		return ddCeil(a)
+	}
+	return ddFloor(a)
+}
+
+func ddRound(a dd) dd {
+	return ddFloor(ddAdd(a, dd{0.5, 0}))
+}
+
+func ddSqrt(a dd) dd {
+	if a.hi <= 0 {
+		return dd{math.Sqrt(a.hi), 0}
+	}
+	// One Newton step from the float64 estimate doubles the precision: (x + e)^2 = a => e = (a - x^2) / 2x
+	x := math.Sqrt(a.hi)
+	p1, p2 := twoProd(x, x)
+	e := ddSub(a, dd{p1, p2}).hi / (2 * x)
+	s1, s2 := quickTwoSum(x, e)
+	return dd{s1, s2}
+}
+
+func ddExp(a dd) dd {
+	if a.hi > 709.782712893384 {
+		return dd{math.Inf(1), 0}
+	}
+	if a.hi < -745.1332191019412 {
+		return dd{0, 0}
+	}
+	if a.hi == 0 {
+		return dd{1, 0}
+	}
+
+	// Reduce to |r| <= ln(2)/1024: a = k*ln(2) + 512*r
+	k := math.Round(a.hi / ddLn2.hi)
+	r := ddSub(a, ddMulFloat64(ddLn2, k))
+	r = dd{math.Ldexp(r.hi, -9), math.Ldexp(r.lo, -9)}
+
+	// exp(r) - 1 by Taylor series
+	sum := r
+	term := r
+	for i := 2.; i < 30; i++ {
+		term = ddDiv(ddMul(term, r), dd{i, 0})
+		sum = ddAdd(sum, term)
+		if math.Abs(term.hi) < DD_EPSILON*math.Abs(sum.hi) {
+			break
+		}
+	}
+
+	// Undo the scaling: (1 + s)^2 - 1 = 2s + s^2
+	for i := 0; i < 9; i++ {
+		sum = ddAdd(ddMulFloat64(sum, 2), ddMul(sum, sum))
+	}
+	sum = ddAdd(sum, dd{1, 0})
+	return dd{math.Ldexp(sum.hi, int(k)), math.Ldexp(sum.lo, int(k))}
+}
+
+func ddLn(a dd) dd {
+	if a.hi <= 0 {
+		if a.hi == 0 {
+			return dd{math.Inf(-1), 0}
+		}
+		return dd{math.NaN(), 0}
+	}
+	if math.IsInf(a.hi, 1) {
+		return a
+	}
+	// Scale to [0.5, 1) so exp(-x) can't overflow, then one Newton step on exp(x) = a from the float64 estimate
+	_, e := math.Frexp(a.hi)
+	a = dd{math.Ldexp(a.hi, -e), math.Ldexp(a.lo, -e)}
+	x := dd{math.Log(a.hi), 0}
+	x = ddSub(ddAdd(x, ddMul(a, ddExp(ddNeg(x)))), dd{1, 0})
+	return ddAdd(x, ddMulFloat64(ddLn2, float64(e)))
+}
+
+func ddLog10(a dd) dd {
+	return ddMul(ddLn(a), ddLog10E)
+}
+
+func ddPow10(a dd) dd {
+	return ddExp(ddMul(a, ddLn10))
+}
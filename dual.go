@@ -0,0 +1,260 @@
+package breaketernity
+
+import (
+	"fmt"
+	"math"
+)
+
+// Scalar is the arithmetic shared by Decimal and Dual. A formula written against it, such as
+//
+//	func income[T Scalar[T]](base, multiplier, owned T) T {
+//		return base.Multiply(multiplier.Pow(owned))
+//	}
+//
+// computes values when called with Decimals, and values with their derivatives when called with Duals,
+// e.g. through Gradient or Elasticities. Use ScalarOf for the constants of the formula.
+type Scalar[T any] interface {
+	Add(other T) T
+	Subtract(other T) T
+	Multiply(other T) T
+	Divide(other T) T
+	Neg() T
+	Pow(other T) T
+	Ln() T
+	Log10() T
+	PowBaseE() T
+	Gamma() T
+}
+
+// ScalarOf converts a value to a Decimal, or to a constant Dual, for the constants of a formula written against Scalar
+func ScalarOf[T Scalar[T], DS DecimalSource](value DS) T {
+	var result any
+	switch any(*new(T)).(type) {
+	case *Decimal:
+		result = D(value)
+	case *Dual:
+		result = DualConst(value)
+	default:
+		panic(fmt.Sprintf("breaketernity: ScalarOf does not support %T", *new(T)))
+	}
+	return result.(T)
+}
+
+// Dual is a dual number: a Decimal value together with its partial derivatives with respect to one or more variables.
+// Every operation applies the chain rule to the exact derivative, so derivatives keep their precision at any layer,
+// where finite differences lose every digit. A constant has no partial derivatives, which count as 0.
+type Dual struct {
+	value Decimal
+	// partials are the derivatives with respect to each variable; missing ones are 0
+	partials []Decimal
+}
+
+// DualConst creates a new Dual for a constant, whose derivatives are 0.
+func DualConst[DS DecimalSource](value DS) *Dual {
+	return &Dual{value: *D(value)}
+}
+
+// NewDual creates a new Dual of a single variable from its value and its derivative.
+func NewDual[DS DecimalSource](value DS, derivative DS) *Dual {
+	return &Dual{value: *D(value), partials: []Decimal{*D(derivative)}}
+}
+
+// dualVariable returns the i-th of n variables, whose derivative is 1 with respect to itself and 0 to the others
+func dualVariable(value *Decimal, i int, n int) *Dual {
+	partials := make([]Decimal, n)
+	partials[i] = *dOne
+	return &Dual{value: *decimalFromDecimal(value), partials: partials}
+}
+
+// Value returns the value of the dual number
+func (x *Dual) Value() *Decimal {
+	return decimalFromDecimal(&x.value)
+}
+
+// Derivative returns the derivative of a dual number of a single variable
+func (x *Dual) Derivative() *Decimal {
+	return x.Partial(0)
+}
+
+// Partial returns the derivative with respect to the i-th variable
+func (x *Dual) Partial(i int) *Decimal {
+	if i < len(x.partials) {
+		return decimalFromDecimal(&x.partials[i])
+	}
+	return decimalFromDecimal(dZero)
+}
+
+// IsConstant returns true if every derivative is 0
+func (x *Dual) IsConstant() bool {
+	for i := range x.partials {
+		if x.partials[i].sign != 0 || x.partials[i].IsNaN() {
+			return false
+		}
+	}
+	return true
+}
+
+// ToString returns the dual number as a string in the form "value (d: derivative, ...)", or "value" if it is constant
+func (x *Dual) ToString() string {
+	if x.IsConstant() {
+		return x.value.ToString()
+	}
+	s := x.value.ToString() + " (d: "
+	for i := range x.partials {
+		if i > 0 {
+			s += ", "
+		}
+		s += x.partials[i].ToString()
+	}
+	return s + ")"
+}
+
+// chain returns a Dual with the given value and the derivatives dx * x' + dy * y'. A nil factor skips its operand,
+// and derivatives that are 0 stay out of the sum, so a constant never turns 0 * Infinity into NaN.
+func chain(value *Decimal, x *Dual, dx *Decimal, y *Dual, dy *Decimal) *Dual {
+	n := 0
+	if dx != nil {
+		n = len(x.partials)
+	}
+	if dy != nil {
+		n = max(n, len(y.partials))
+	}
+	result := &Dual{value: *value}
+	if n == 0 {
+		return result
+	}
+	result.partials = make([]Decimal, n)
+	for i := range result.partials {
+		sum := dZero
+		if dx != nil && i < len(x.partials) && x.partials[i].sign != 0 {
+			sum = sum.Add(x.partials[i].Multiply(dx))
+		}
+		if dy != nil && i < len(y.partials) && y.partials[i].sign != 0 {
+			sum = sum.Add(y.partials[i].Multiply(dy))
+		}
+		result.partials[i] = *sum
+	}
+	return result
+}
+
+// Neg returns the negative of the dual number
+func (x *Dual) Neg() *Dual {
+	return chain(x.value.Neg(), x, dNegOne, nil, nil)
+}
+
+// Add returns the sum of the two dual numbers
+func (x *Dual) Add(other *Dual) *Dual {
+	return chain(x.value.Add(&other.value), x, dOne, other, dOne)
+}
+
+// Subtract returns the difference of the two dual numbers
+func (x *Dual) Subtract(other *Dual) *Dual {
+	return chain(x.value.Subtract(&other.value), x, dOne, other, dNegOne)
+}
+
+// Multiply returns the product of the two dual numbers
+func (x *Dual) Multiply(other *Dual) *Dual {
+	return chain(x.value.Multiply(&other.value), x, &other.value, other, &x.value)
+}
+
+// Divide returns the quotient of the two dual numbers
+func (x *Dual) Divide(other *Dual) *Dual {
+	value := x.value.Divide(&other.value)
+	// (x/y)' = x'/y - y' * (x/y) / y
+	return chain(value, x, other.value.Recip(), other, value.Divide(&other.value).Neg())
+}
+
+// Pow returns the dual number raised to the power of other
+func (x *Dual) Pow(other *Dual) *Dual {
+	value := x.value.Pow(&other.value)
+	var dx, dy *Decimal
+	// (x^y)' = y * x^(y-1) * x' + x^y * ln(x) * y', where each term is only needed if its operand varies,
+	// so that a negative base with a constant integer exponent keeps a derivative
+	if !x.IsConstant() {
+		dx = other.value.Multiply(x.value.Pow(other.value.Subtract(dOne)))
+	}
+	if !other.IsConstant() {
+		dy = value.Multiply(x.value.Ln())
+	}
+	return chain(value, x, dx, other, dy)
+}
+
+// Ln returns the natural logarithm of the dual number
+func (x *Dual) Ln() *Dual {
+	return chain(x.value.Ln(), x, x.value.Recip(), nil, nil)
+}
+
+// Log10 returns the base10 logarithm of the dual number
+func (x *Dual) Log10() *Dual {
+	return chain(x.value.Log10(), x, x.value.Multiply(D(math.Ln10)).Recip(), nil, nil)
+}
+
+// PowBaseE returns e raised to the power of the dual number, the exponential function
+func (x *Dual) PowBaseE() *Dual {
+	value := x.value.PowBaseE()
+	return chain(value, x, value, nil, nil)
+}
+
+// Gamma returns the Gamma function of the dual number, whose derivative is Gamma(x) * digamma(x)
+func (x *Dual) Gamma() *Dual {
+	value := x.value.Gamma()
+	var dx *Decimal
+	if !x.IsConstant() {
+		dx = value.Multiply(digamma(&x.value))
+	}
+	return chain(value, x, dx, nil, nil)
+}
+
+// digamma returns the derivative of ln(Gamma(d)), following the same approximations as Gamma
+func digamma(d *Decimal) *Decimal {
+	switch {
+	case d.IsNaN():
+		return decimalFromDecimal(d)
+	case d.layer == 0:
+		return D(fDigamma(d.sign * d.mag))
+	case d.mag < 0:
+		// Gamma(d) is 1/d, and digamma(d) is -1/d - EULER_MASCHERONI
+		return d.Recip().Neg().Subtract(D(EULER_MASCHERONI))
+	case d.sign > 0:
+		// digamma(d) is ln(d) - 1/(2d) - ..., and the other terms are lost beyond layer 0
+		return d.Ln()
+	}
+	return D(math.NaN())
+}
+
+// Derivative returns f(x) and its derivative at x
+func Derivative[DS DecimalSource](f func(x *Dual) *Dual, x DS) (value *Decimal, derivative *Decimal) {
+	result := f(dualVariable(D(x), 0, 1))
+	return result.Value(), result.Partial(0)
+}
+
+// Gradient returns f(x) and its partial derivatives with respect to every x[i]
+func Gradient(f func(x []*Dual) *Dual, x ...*Decimal) (value *Decimal, gradient []*Decimal) {
+	vars := make([]*Dual, len(x))
+	for i := range x {
+		vars[i] = dualVariable(x[i], i, len(x))
+	}
+	result := f(vars)
+	gradient = make([]*Decimal, len(x))
+	for i := range gradient {
+		gradient[i] = result.Partial(i)
+	}
+	return result.Value(), gradient
+}
+
+// Elasticity returns the relative sensitivity of f at x, f'(x) * x / f(x): how many percent f(x) changes
+// for a 1% change of x. An elasticity of 2 means that 1% more x gives about 2% more f(x).
+func Elasticity[DS DecimalSource](f func(x *Dual) *Dual, x DS) *Decimal {
+	at := D(x)
+	value, derivative := Derivative(f, at)
+	return derivative.Multiply(at).Divide(value)
+}
+
+// Elasticities returns the elasticity of f with respect to every x[i]
+func Elasticities(f func(x []*Dual) *Dual, x ...*Decimal) []*Decimal {
+	value, gradient := Gradient(f, x...)
+	for i := range gradient {
+		gradient[i] = gradient[i].Multiply(x[i]).Divide(value)
+	}
+	return gradient
+}
@@ -0,0 +1,109 @@
+package breaketernity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDerivative(t *testing.T) {
+	cases := []struct {
+		name         string
+		f            func(x *Dual) *Dual
+		at           *Decimal
+		value, slope *Decimal
+		tolerance    float64
+	}{
+		{"x^1e5", func(x *Dual) *Dual { return x.Pow(DualConst(1e5)) }, D(10), D("1e100000"), D("1e100004"), 1e-9},
+		{"Gamma", (*Dual).Gamma, D(-0.5), D(-2 * math.Sqrt(math.Pi)), D(-0.12935358979550046), 1e-9},
+		{"2^x", func(x *Dual) *Dual { return DualConst(2).Pow(x) }, D(3), D(8), D(8 * math.Ln2), 1e-12},
+		{"x/(1+x)", func(x *Dual) *Dual { return x.Divide(DualConst(1).Add(x)) }, D(3), D(0.75), D(1.0 / 16), 1e-12},
+		{"x - x*x", func(x *Dual) *Dual { return x.Subtract(x.Multiply(x)) }, D(2), D(-2), D(-3), 1e-12},
+		{"-ln(x)", func(x *Dual) *Dual { return x.Ln().Neg() }, D(4), D(-math.Log(4)), D(-0.25), 1e-12},
+		{"log10(x)", (*Dual).Log10, D("1e400"), D(400), D("1e-400").Multiply(D(math.Log10E)), 1e-12},
+		{"e^e^x", func(x *Dual) *Dual { return x.PowBaseE().PowBaseE() }, D(1000), D(1000).PowBaseE().PowBaseE(),
+			D(1000).PowBaseE().PowBaseE().Multiply(D(1000).PowBaseE()), 1e-9},
+	}
+	for _, c := range cases {
+		value, slope := Derivative(c.f, c.at)
+		if !value.EqTolerance(c.value, c.tolerance) || !slope.EqTolerance(c.slope, c.tolerance) {
+			t.Errorf("%s at %s = %s with derivative %s, want %s and %s", c.name, c.at.ToString(),
+				value.ToString(), slope.ToString(), c.value.ToString(), c.slope.ToString())
+		}
+	}
+}
+
+func TestDualConstantsAndString(t *testing.T) {
+	c := DualConst(5)
+	if !c.IsConstant() || !c.Derivative().Eq(dZero) || c.ToString() != "5" {
+		t.Errorf("DualConst(5) = %s, constant %v", c.ToString(), c.IsConstant())
+	}
+	x := NewDual(2, 3)
+	if x.IsConstant() || !x.Value().Eq(D(2)) || !x.Derivative().Eq(D(3)) || x.ToString() != "2 (d: 3)" {
+		t.Errorf("NewDual(2, 3) = %s", x.ToString())
+	}
+	if !x.Partial(5).Eq(dZero) {
+		t.Errorf("NewDual(2, 3).Partial(5) = %s, want 0", x.Partial(5).ToString())
+	}
+	// A constant Infinity doesn't turn the derivative into NaN
+	if _, slope := Derivative(func(x *Dual) *Dual { return x.Add(DualConst(math.Inf(1))) }, 1); !slope.Eq(dOne) {
+		t.Errorf("d/dx (x + Infinity) = %s, want 1", slope.ToString())
+	}
+}
+
+func TestGradientAndElasticities(t *testing.T) {
+	f := func(x []*Dual) *Dual { return x[0].Multiply(x[1].Pow(DualConst(2))) }
+	value, gradient := Gradient(f, D(3), D(2))
+	if !value.EqTolerance(D(12), 1e-12) || !gradient[0].EqTolerance(D(4), 1e-12) || !gradient[1].EqTolerance(D(12), 1e-12) {
+		t.Errorf("Gradient of x*y^2 at (3, 2) = %s, [%s, %s], want 12, [4, 12]", value.ToString(), gradient[0].ToString(), gradient[1].ToString())
+	}
+	elasticities := Elasticities(f, D(3), D(2))
+	if !elasticities[0].EqTolerance(D(1), 1e-12) || !elasticities[1].EqTolerance(D(2), 1e-12) {
+		t.Errorf("Elasticities of x*y^2 = [%s, %s], want [1, 2]", elasticities[0].ToString(), elasticities[1].ToString())
+	}
+	cube := func(x *Dual) *Dual { return x.Pow(DualConst(3)) }
+	for _, at := range []string{"0.5", "7", "1e400", "1e-400"} {
+		if got := Elasticity(cube, at); !got.EqTolerance(D(3), 1e-9) {
+			t.Errorf("Elasticity of x^3 at %s = %s, want 3", at, got.ToString())
+		}
+	}
+}
+
+func income[T Scalar[T]](base, owned T) T {
+	return base.Multiply(ScalarOf[T](1.15).Pow(owned))
+}
+
+func TestScalarOf(t *testing.T) {
+	plain := income(D(10), D(20))
+	value, gradient := Gradient(func(x []*Dual) *Dual { return income(x[0], x[1]) }, D(10), D(20))
+	if !value.Eq(plain) {
+		t.Errorf("income with Duals = %s, with Decimals %s", value.ToString(), plain.ToString())
+	}
+	if want := plain.Divide(D(10)); !gradient[0].EqTolerance(want, 1e-12) {
+		t.Errorf("d income / d base = %s, want %s", gradient[0].ToString(), want.ToString())
+	}
+	if want := plain.Multiply(D(math.Log(1.15))); !gradient[1].EqTolerance(want, 1e-12) {
+		t.Errorf("d income / d owned = %s, want %s", gradient[1].ToString(), want.ToString())
+	}
+}
+
+func TestFDigamma(t *testing.T) {
+	cases := []struct {
+		x, want float64
+	}{
+		{1, -0.5772156649015329},
+		{0.5, -1.9635100260214235},
+		{10, 2.251752589066721},
+		{-0.5, 0.03648997397857652},
+		{100, 4.600161852738087},
+	}
+	for _, c := range cases {
+		if got := fDigamma(c.x); math.Abs(got-c.want) > 1e-12*math.Max(1, math.Abs(c.want)) {
+			t.Errorf("fDigamma(%v) = %v, want %v", c.x, got, c.want)
+		}
+	}
+	for _, x := range []float64{0, -2, math.Inf(-1), math.NaN()} {
+		if got := fDigamma(x); !math.IsNaN(got) {
+			t.Errorf("fDigamma(%v) = %v, want NaN", x, got)
+		}
+	}
+}
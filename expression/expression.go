@@ -0,0 +1,219 @@
+// Package expression parses, validates and evaluates formulas over Decimal, such as "base * 1.15^owned".
+//
+// Formulas support + - * / % ^ (power) ^^ (tetration) ^^^ (pentation) and ! (factorial), parentheses, the
+// constants e, pi, inf and nan, variables, and the operations of math.go as functions (see Functions).
+// Compile a formula once and evaluate it many times:
+//
+//	cost := expression.MustCompile("base * 1.15^owned")
+//	price, err := cost.Eval(map[string]*bet.Decimal{"base": bet.D(10), "owned": bet.D(25)})
+package expression
+
+import (
+	"fmt"
+	"slices"
+
+	bet "github.com/aapedro/breaketernity.go"
+)
+
+// Options controls how CompileWith checks a formula.
+type Options struct {
+	// Variables are the only variable names the formula may use. All names are allowed if it is nil.
+	Variables []string
+}
+
+// Expression is a compiled formula. It is safe for concurrent use.
+type Expression struct {
+	src  string
+	root Node
+	// vars are the variables used by the formula, in order of first use; eval reads them by index
+	vars []string
+	eval func(values []*bet.Decimal) *bet.Decimal
+}
+
+// Compile parses a formula and checks its functions and their number of arguments.
+func Compile(src string) (*Expression, error) {
+	return CompileWith(src, Options{})
+}
+
+// MustCompile is like Compile but panics if the formula cannot be compiled.
+func MustCompile(src string) *Expression {
+	e, err := Compile(src)
+	if err != nil {
+		panic(fmt.Sprintf("expression: compiling %q: %v", src, err))
+	}
+	return e
+}
+
+// CompileWith parses a formula and checks its functions, their number of arguments and, with Options.Variables,
+// its variables.
+func CompileWith(src string, opts Options) (*Expression, error) {
+	root, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return CompileNode(root, src, opts)
+}
+
+// CompileNode compiles a syntax tree, e.g. one rewritten after Parse. src is only kept for String.
+func CompileNode(root Node, src string, opts Options) (*Expression, error) {
+	for _, name := range opts.Variables {
+		if !IsName(name) || IsReserved(name) {
+			return nil, fmt.Errorf("expression: invalid variable name %q", name)
+		}
+	}
+	e := &Expression{src: src, root: root}
+	c := &compiler{expression: e, allowed: opts.Variables}
+	var err error
+	if e.eval, err = c.compile(root); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// String returns the source of the formula
+func (e *Expression) String() string {
+	return e.src
+}
+
+// Root returns the syntax tree of the formula
+func (e *Expression) Root() Node {
+	return e.root
+}
+
+// Variables returns the names of the variables used by the formula, in order of first use
+func (e *Expression) Variables() []string {
+	return slices.Clone(e.vars)
+}
+
+// Eval evaluates the formula. Every variable it uses must be set in vars.
+func (e *Expression) Eval(vars map[string]*bet.Decimal) (*bet.Decimal, error) {
+	values := make([]*bet.Decimal, len(e.vars))
+	for i, name := range e.vars {
+		value, ok := vars[name]
+		if !ok || value == nil {
+			return nil, fmt.Errorf("expression: variable %s is not set", name)
+		}
+		values[i] = value
+	}
+	return e.run(values), nil
+}
+
+// EvalValues evaluates the formula with the values of its variables in the order of Variables,
+// which avoids the map lookups of Eval in hot loops.
+func (e *Expression) EvalValues(values ...*bet.Decimal) (*bet.Decimal, error) {
+	if len(values) != len(e.vars) {
+		return nil, fmt.Errorf("expression: got %d values for %d variables", len(values), len(e.vars))
+	}
+	for i, value := range values {
+		if value == nil {
+			return nil, fmt.Errorf("expression: variable %s is not set", e.vars[i])
+		}
+	}
+	return e.run(values), nil
+}
+
+// run evaluates the formula, copying the result so it never aliases a literal of the formula or a value passed in
+func (e *Expression) run(values []*bet.Decimal) *bet.Decimal {
+	return bet.D(e.eval(values))
+}
+
+// Eval compiles and evaluates a formula once.
+func Eval(src string, vars map[string]*bet.Decimal) (*bet.Decimal, error) {
+	e, err := Compile(src)
+	if err != nil {
+		return nil, err
+	}
+	return e.Eval(vars)
+}
+
+type compiler struct {
+	expression *Expression
+	allowed    []string
+}
+
+// slot returns the index of a variable in the values passed to eval
+func (c *compiler) slot(name string) int {
+	if i := slices.Index(c.expression.vars, name); i >= 0 {
+		return i
+	}
+	c.expression.vars = append(c.expression.vars, name)
+	return len(c.expression.vars) - 1
+}
+
+// compile turns a node into a closure over the values of the variables
+func (c *compiler) compile(n Node) (func(values []*bet.Decimal) *bet.Decimal, error) {
+	switch n := n.(type) {
+	case *Number:
+		value := n.Value
+		return func([]*bet.Decimal) *bet.Decimal { return value }, nil
+
+	case *Variable:
+		if value, ok := constants[n.Name]; ok {
+			return func([]*bet.Decimal) *bet.Decimal { return value }, nil
+		}
+		if _, ok := functions[n.Name]; ok {
+			return nil, errorAt(n.Offset, "%s is a function, call it as %s", n.Name, functions[n.Name].usage)
+		}
+		if c.allowed != nil && !slices.Contains(c.allowed, n.Name) {
+			return nil, errorAt(n.Offset, "unknown variable %s", n.Name)
+		}
+		i := c.slot(n.Name)
+		return func(values []*bet.Decimal) *bet.Decimal { return values[i] }, nil
+
+	case *Unary:
+		x, err := c.compile(n.X)
+		if err != nil {
+			return nil, err
+		}
+		switch n.Op {
+		case "+":
+			return x, nil
+		case "-":
+			return func(values []*bet.Decimal) *bet.Decimal { return x(values).Neg() }, nil
+		}
+		return nil, errorAt(n.Offset, "unknown operator %s", n.Op)
+
+	case *Binary:
+		op, ok := binaryOps[n.Op]
+		if !ok {
+			return nil, errorAt(n.Offset, "unknown operator %s", n.Op)
+		}
+		x, err := c.compile(n.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := c.compile(n.Y)
+		if err != nil {
+			return nil, err
+		}
+		return func(values []*bet.Decimal) *bet.Decimal { return op(x(values), y(values)) }, nil
+
+	case *Call:
+		f, ok := functions[n.Func]
+		if !ok {
+			return nil, errorAt(n.Offset, "unknown function %s", n.Func)
+		}
+		if len(n.Args) < f.min || f.max >= 0 && len(n.Args) > f.max {
+			return nil, errorAt(n.Offset, "wrong number of arguments, expected %s", f.usage)
+		}
+		args := make([]func([]*bet.Decimal) *bet.Decimal, len(n.Args))
+		for i, arg := range n.Args {
+			var err error
+			if args[i], err = c.compile(arg); err != nil {
+				return nil, err
+			}
+		}
+		var defaults []*bet.Decimal
+		if len(args) < f.max {
+			defaults = f.defaults[len(args)-f.min:]
+		}
+		return func(values []*bet.Decimal) *bet.Decimal {
+			a := make([]*bet.Decimal, len(args), len(args)+len(defaults))
+			for i, arg := range args {
+				a[i] = arg(values)
+			}
+			return f.call(append(a, defaults...))
+		}, nil
+	}
+	return nil, fmt.Errorf("expression: unknown node %T", n)
+}
@@ -0,0 +1,164 @@
+package expression
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	bet "github.com/aapedro/breaketernity.go"
+)
+
+func TestCompileVariables(t *testing.T) {
+	e, err := CompileWith("base * 1.15^owned + base", Options{Variables: []string{"owned", "base"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.Variables(), []string{"base", "owned"}; !slices.Equal(got, want) {
+		t.Errorf("Variables() = %v, want %v", got, want)
+	}
+	if e.String() != "base * 1.15^owned + base" {
+		t.Errorf("String() = %q", e.String())
+	}
+	got, err := e.Eval(map[string]*bet.Decimal{"base": bet.D(10), "owned": bet.D(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := bet.D(10*1.15*1.15 + 10); !got.EqTolerance(want, 1e-12) {
+		t.Errorf("Eval = %s, want %s", got.ToString(), want.ToString())
+	}
+	if got, err := e.EvalValues(bet.D(10), bet.D(2)); err != nil || !got.EqTolerance(bet.D(10*1.15*1.15+10), 1e-12) {
+		t.Errorf("EvalValues(10, 2) = %v, %v", got, err)
+	}
+
+	cases := []struct {
+		src    string
+		vars   []string
+		offset int
+	}{
+		{"base * cost", []string{"base"}, 7},
+		{"x + 1", []string{}, 0},
+		{"pi * r^2", []string{"r"}, -1},
+		{"e * x", []string{"x"}, -1},
+	}
+	for _, c := range cases {
+		_, err := CompileWith(c.src, Options{Variables: c.vars})
+		if c.offset < 0 {
+			if err != nil {
+				t.Errorf("CompileWith(%q, %v): %v", c.src, c.vars, err)
+			}
+			continue
+		}
+		if perr, ok := err.(*Error); !ok || perr.Offset != c.offset {
+			t.Errorf("CompileWith(%q, %v) = %v, want an error at offset %d", c.src, c.vars, err, c.offset)
+		}
+	}
+	for _, name := range []string{"pi", "log", "2x", ""} {
+		if _, err := CompileWith("1", Options{Variables: []string{name}}); err == nil {
+			t.Errorf("CompileWith with variable %q succeeded, want an error", name)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	e := MustCompile("x + y")
+	if _, err := e.Eval(map[string]*bet.Decimal{"x": bet.D(1)}); err == nil {
+		t.Errorf("Eval without y succeeded")
+	}
+	if _, err := e.Eval(map[string]*bet.Decimal{"x": bet.D(1), "y": nil}); err == nil {
+		t.Errorf("Eval with a nil y succeeded")
+	}
+	if _, err := e.EvalValues(bet.D(1)); err == nil {
+		t.Errorf("EvalValues with one value succeeded")
+	}
+	if _, err := e.EvalValues(bet.D(1), nil); err == nil {
+		t.Errorf("EvalValues with a nil value succeeded")
+	}
+	if _, err := Eval("1 +", nil); err == nil {
+		t.Errorf("Eval(\"1 +\") succeeded")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustCompile(\"(1\") didn't panic")
+		}
+	}()
+	MustCompile("(1")
+}
+
+func TestCompileChecksCalls(t *testing.T) {
+	cases := []struct {
+		src    string
+		offset int
+	}{
+		{"foo(1)", 0},
+		{"1 + bar(x)", 4},
+		{"sqrt(1, 2)", 0},
+		{"clamp(1, 2)", 0},
+		{"x * pow(2)", 4},
+		{"log(1, 2, 3)", 0},
+		{"2 * sqrt", 4},
+	}
+	for _, c := range cases {
+		_, err := Compile(c.src)
+		if perr, ok := err.(*Error); !ok || perr.Offset != c.offset {
+			t.Errorf("Compile(%q) = %v, want an error at offset %d", c.src, err, c.offset)
+		}
+	}
+	for _, src := range []string{"log(100)", "log(8, 2)", "max(1)", "max(1, 2, 3, 4)", "tetrate(2, 3)", "iteratedlog(1e100)"} {
+		if _, err := Compile(src); err != nil {
+			t.Errorf("Compile(%q): %v", src, err)
+		}
+	}
+}
+
+func TestPrecedence(t *testing.T) {
+	cases := map[string]float64{
+		"-2^2":                     -4,
+		"(-2)^2":                   4,
+		"2^3^2":                    512,
+		"2^3!":                     64,
+		"-3!":                      -6,
+		"2*3^2":                    18,
+		"2^-1":                     0.5,
+		"1-2-3":                    -4,
+		"12/2/3":                   2,
+		"7%4*2":                    6,
+		"2+3*4":                    14,
+		"log(8, 2)":                3,
+		"max(1, 5, 3) - min(4, 2)": 3,
+	}
+	for src, want := range cases {
+		got, err := Eval(src, nil)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", src, err)
+		} else if !got.EqTolerance(bet.D(want), 1e-12) {
+			t.Errorf("Eval(%q) = %s, want %v", src, got.ToString(), want)
+		}
+	}
+}
+
+func TestConcurrentEval(t *testing.T) {
+	e := MustCompile("a * 1.15^n + max(n, 3)")
+	var wg sync.WaitGroup
+	errs := make(chan error, 32)
+	for g := 0; g < 32; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 200; n++ {
+				a := bet.D(g + 1)
+				got, err := e.Eval(map[string]*bet.Decimal{"a": a, "n": bet.D(n)})
+				want := a.Multiply(bet.D(1.15).Pow(bet.D(n))).Add(bet.D(max(n, 3)))
+				if err != nil || !got.Eq(want) {
+					errs <- fmt.Errorf("Eval(a = %d, n = %d) = %v, %v, want %s", g+1, n, got, err, want.ToString())
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,147 @@
+package expression
+
+import (
+	"math"
+	"slices"
+
+	bet "github.com/aapedro/breaketernity.go"
+)
+
+// constants are reserved names that can be used like variables
+var constants = map[string]*bet.Decimal{
+	"e":   bet.D(math.E),
+	"pi":  bet.D(math.Pi),
+	"inf": bet.D(math.Inf(1)),
+	"nan": bet.D(math.NaN()),
+}
+
+// function is a builtin taking between min and max arguments, or any number from min if max is -1.
+// Optional arguments are filled in from defaults, counted from the first optional one.
+type function struct {
+	min, max int
+	defaults []*bet.Decimal
+	usage    string
+	call     func(args []*bet.Decimal) *bet.Decimal
+}
+
+func unary(usage string, f func(x *bet.Decimal) *bet.Decimal) function {
+	return function{1, 1, nil, usage, func(a []*bet.Decimal) *bet.Decimal { return f(a[0]) }}
+}
+
+func binary(usage string, f func(x, y *bet.Decimal) *bet.Decimal) function {
+	return function{2, 2, nil, usage, func(a []*bet.Decimal) *bet.Decimal { return f(a[0], a[1]) }}
+}
+
+// fold applies f pairwise over one or more arguments
+func fold(usage string, f func(x, y *bet.Decimal) *bet.Decimal) function {
+	return function{1, -1, nil, usage, func(a []*bet.Decimal) *bet.Decimal {
+		result := a[0]
+		for _, x := range a[1:] {
+			result = f(result, x)
+		}
+		return result
+	}}
+}
+
+var functions = map[string]function{
+	"abs":       unary("abs(x)", (*bet.Decimal).Abs),
+	"neg":       unary("neg(x)", (*bet.Decimal).Neg),
+	"round":     unary("round(x)", (*bet.Decimal).Round),
+	"floor":     unary("floor(x)", (*bet.Decimal).Floor),
+	"ceil":      unary("ceil(x)", (*bet.Decimal).Ceil),
+	"trunc":     unary("trunc(x)", (*bet.Decimal).Trunc),
+	"recip":     unary("recip(x)", (*bet.Decimal).Recip),
+	"sqrt":      unary("sqrt(x)", (*bet.Decimal).Sqrt),
+	"ln":        unary("ln(x)", (*bet.Decimal).Ln),
+	"log10":     unary("log10(x)", (*bet.Decimal).Log10),
+	"log2":      unary("log2(x)", (*bet.Decimal).Log2),
+	"plog10":    unary("plog10(x)", (*bet.Decimal).PLog10),
+	"abslog10":  unary("abslog10(x)", (*bet.Decimal).AbsLog10),
+	"exp":       unary("exp(x)", (*bet.Decimal).PowBaseE),
+	"pow10":     unary("pow10(x)", (*bet.Decimal).PowBase10),
+	"gamma":     unary("gamma(x)", (*bet.Decimal).Gamma),
+	"fact":      unary("fact(x)", (*bet.Decimal).Factorial),
+	"factorial": unary("factorial(x)", (*bet.Decimal).Factorial),
+
+	"pow":     binary("pow(x, y)", (*bet.Decimal).Pow),
+	"root":    binary("root(x, n)", (*bet.Decimal).Root),
+	"mod":     binary("mod(x, y)", (*bet.Decimal).Modulo),
+	"powbase": binary("powbase(x, base)", (*bet.Decimal).PowBaseN),
+	"min":     fold("min(x, ...)", (*bet.Decimal).Min),
+	"max":     fold("max(x, ...)", (*bet.Decimal).Max),
+	"minabs":  fold("minabs(x, ...)", (*bet.Decimal).MinAbs),
+	"maxabs":  fold("maxabs(x, ...)", (*bet.Decimal).MaxAbs),
+	"cmp": binary("cmp(x, y)", func(x, y *bet.Decimal) *bet.Decimal {
+		return bet.D(x.Cmp(y))
+	}),
+	"clamp": {3, 3, nil, "clamp(x, min, max)", func(a []*bet.Decimal) *bet.Decimal {
+		return a[0].Clamp(a[1], a[2])
+	}},
+
+	"log": {1, 2, []*bet.Decimal{bet.D(10)}, "log(x, base = 10)", func(a []*bet.Decimal) *bet.Decimal {
+		return a[0].Log(a[1])
+	}},
+	"lambertw": {1, 2, []*bet.Decimal{bet.D(1)}, "lambertw(x, principal = 1)", func(a []*bet.Decimal) *bet.Decimal {
+		return a[0].LambertW(a[1].Neq(bet.D(0)))
+	}},
+	"tetrate": {2, 3, []*bet.Decimal{bet.D(1)}, "tetrate(x, height, payload = 1)", func(a []*bet.Decimal) *bet.Decimal {
+		return a[0].Tetrate(a[1].ToFloat64(), a[2], false)
+	}},
+	"pentate": {2, 3, []*bet.Decimal{bet.D(1)}, "pentate(x, height, payload = 1)", func(a []*bet.Decimal) *bet.Decimal {
+		return a[0].Pentate(a[1].ToFloat64(), a[2], false)
+	}},
+	"iteratedexp": {2, 3, []*bet.Decimal{bet.D(1)}, "iteratedexp(x, height, payload = 1)", func(a []*bet.Decimal) *bet.Decimal {
+		return a[0].IteratedExp(a[1].ToFloat64(), a[2], false)
+	}},
+	"iteratedlog": {1, 3, []*bet.Decimal{bet.D(10), bet.D(1)}, "iteratedlog(x, base = 10, times = 1)", func(a []*bet.Decimal) *bet.Decimal {
+		return a[0].IteratedLog(a[1], a[2].ToFloat64(), false)
+	}},
+	"slog": {1, 2, []*bet.Decimal{bet.D(10)}, "slog(x, base = 10)", func(a []*bet.Decimal) *bet.Decimal {
+		return a[0].Slog(a[1], bet.DEFAULT_SLOG_ITERATIONS, false)
+	}},
+	"layeradd10": binary("layeradd10(x, diff)", func(x, diff *bet.Decimal) *bet.Decimal {
+		return x.LayerAdd10(diff, false)
+	}),
+	"layeradd": {2, 3, []*bet.Decimal{bet.D(10)}, "layeradd(x, diff, base = 10)", func(a []*bet.Decimal) *bet.Decimal {
+		return a[0].LayerAdd(a[1], a[2], false)
+	}},
+}
+
+// binaryOps are the operators of Binary
+var binaryOps = map[string]func(x, y *bet.Decimal) *bet.Decimal{
+	"+":   (*bet.Decimal).Add,
+	"-":   (*bet.Decimal).Subtract,
+	"*":   (*bet.Decimal).Multiply,
+	"/":   (*bet.Decimal).Divide,
+	"%":   (*bet.Decimal).Modulo,
+	"^":   (*bet.Decimal).Pow,
+	"^^":  func(x, y *bet.Decimal) *bet.Decimal { return x.Tetrate(y.ToFloat64(), bet.D(1), false) },
+	"^^^": func(x, y *bet.Decimal) *bet.Decimal { return x.Pentate(y.ToFloat64(), bet.D(1), false) },
+}
+
+// Functions returns the usage of every function, e.g. "log(x, base = 10)", sorted
+func Functions() []string {
+	usages := make([]string, 0, len(functions))
+	for _, f := range functions {
+		usages = append(usages, f.usage)
+	}
+	slices.Sort(usages)
+	return usages
+}
+
+// Constants returns the names of the constants, sorted
+func Constants() []string {
+	names := make([]string, 0, len(constants))
+	for name := range constants {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// IsReserved reports whether name is a constant or a function, which cannot be used as a variable
+func IsReserved(name string) bool {
+	_, isConstant := constants[name]
+	_, isFunction := functions[name]
+	return isConstant || isFunction
+}
@@ -0,0 +1,359 @@
+package expression
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	bet "github.com/aapedro/breaketernity.go"
+)
+
+// The grammar, from the loosest to the tightest binding:
+//
+//	expr    = term {("+" | "-") term}
+//	term    = unary {("*" | "/" | "%") unary}
+//	unary   = ("-" | "+") unary | power
+//	power   = postfix [("^" | "^^" | "^^^") unary]
+//	postfix = primary {"!"}
+//	primary = number | string | name | name "(" [expr {"," expr}] ")" | "(" expr ")"
+//
+// Powers are right associative, so 2^3^2 is 2^9, and bind tighter than a leading minus, so -2^2 is -4.
+
+// Error is a syntax or validation error at a byte offset of the source
+type Error struct {
+	Offset int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d: %s", e.Offset+1, e.Msg)
+}
+
+func errorAt(offset int, format string, args ...any) error {
+	return &Error{offset, fmt.Sprintf(format, args...)}
+}
+
+// Node is an expression of the syntax tree: *Number, *Variable, *Unary, *Binary or *Call
+type Node interface {
+	// Pos returns the byte offset of the node in the source
+	Pos() int
+}
+
+// Number is a literal, read with D
+type Number struct {
+	Value  *bet.Decimal
+	Offset int
+}
+
+// Variable is a name, either one of the Constants or a variable given to Eval
+type Variable struct {
+	Name   string
+	Offset int
+}
+
+// Unary is a leading "-" or "+"
+type Unary struct {
+	Op     string
+	X      Node
+	Offset int
+}
+
+// Binary is one of "+", "-", "*", "/", "%", "^", "^^" (tetration) and "^^^" (pentation)
+type Binary struct {
+	Op     string
+	X, Y   Node
+	Offset int
+}
+
+// Call is a call of one of the functions, a trailing "!" is a call of factorial
+type Call struct {
+	Func   string
+	Args   []Node
+	Offset int
+}
+
+func (n *Number) Pos() int   { return n.Offset }
+func (n *Variable) Pos() int { return n.Offset }
+func (n *Unary) Pos() int    { return n.Offset }
+func (n *Binary) Pos() int   { return n.Offset }
+func (n *Call) Pos() int     { return n.Offset }
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenName
+	tokenOperator
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+var (
+	// A mantissa with optional exponents, e.g. 1.5, 1e50, 2e3e4, 1ee10
+	numberPattern = regexp.MustCompile(`^(\d+\.?\d*|\.\d+)([eE]+[+-]?(\d+\.?\d*|\.\d+))*`)
+	// Leading e's read as powers of 10, e.g. e5, ee10, eee1e5. A sign after them is an operator on the constant e,
+	// so e-1 is e minus 1, like e - 1
+	ePattern = regexp.MustCompile(`^e+(\d+\.?\d*|\.\d+)([eE]+[+-]?(\d+\.?\d*|\.\d+))*`)
+	// The formats D reads from a quoted string, where M is an unsigned decimal and N a float: numbers with any e's,
+	// like 1e5, ee10 or -e5,
+	// N^N, N^^N and N^^^N with an optional ;payload, NptN, NpN, NfN and (e^N)N
+	literalPattern = regexp.MustCompile(strings.ReplaceAll(strings.ReplaceAll(`(?i)^\s*(`+
+		`[+-]?(M(e+[+-]?M)*|(e+[+-]?M)+)`+
+		`|N\^{1,3}N(;N)?`+
+		`|[+-]?N(pt|p)\(?N\)?`+
+		`|[+-]?\(?N\)?fN`+
+		`|[+-]?\(e\^\d+\)N`+
+		`)\s*$`, "N", `[+-]?M(e[+-]?\d+)?`), "M", `(\d+\.?\d*|\.\d+)`))
+	namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	namePrefix  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// operators are matched longest first
+var operators = []string{"^^^", "^^", "^", "+", "-", "*", "/", "%", "!", "(", ")", ","}
+
+// IsName reports whether s can be used as a variable name. Names that read as numbers, like "e5" or "ee10", cannot.
+func IsName(s string) bool {
+	return namePattern.MatchString(s) && ePattern.FindString(s) != s
+}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for offset := 0; offset < len(src); {
+		rest := src[offset:]
+		switch {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\n' || rest[0] == '\r':
+			offset++
+			continue
+		case rest[0] == '"':
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return nil, errorAt(offset, "unterminated string")
+			}
+			tokens = append(tokens, token{tokenString, rest[1 : end+1], offset})
+			offset += end + 2
+			continue
+		}
+		if m := numberPattern.FindString(rest); m != "" {
+			tokens = append(tokens, token{tokenNumber, m, offset})
+			offset += len(m)
+			continue
+		}
+		if m := ePattern.FindString(rest); m != "" && !namePrefix.MatchString(rest[len(m):]) {
+			tokens = append(tokens, token{tokenNumber, m, offset})
+			offset += len(m)
+			continue
+		}
+		if m := namePrefix.FindString(rest); m != "" {
+			tokens = append(tokens, token{tokenName, m, offset})
+			offset += len(m)
+			continue
+		}
+		matched := false
+		for _, op := range operators {
+			if strings.HasPrefix(rest, op) {
+				tokens = append(tokens, token{tokenOperator, op, offset})
+				offset += len(op)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, errorAt(offset, "unexpected %q", rest[:1])
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(src)}), nil
+}
+
+type parser struct {
+	tokens []token
+	next   int
+}
+
+// Parse reads an expression into its syntax tree. Numbers are read with D, so "1e1e10" and "ee10" are numbers,
+// and any other format of D can be written as a quoted string, e.g. "(e^6)20" or "5pt2".
+// Parse only checks the syntax, Compile also checks the functions and variables.
+func Parse(src string) (Node, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, unexpected(t, "an operator")
+	}
+	return root, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) take() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+// accept takes the next token if it is one of the operators
+func (p *parser) accept(ops ...string) (token, bool) {
+	t := p.peek()
+	if t.kind == tokenOperator {
+		for _, op := range ops {
+			if t.text == op {
+				return p.take(), true
+			}
+		}
+	}
+	return t, false
+}
+
+func (p *parser) expect(op string) error {
+	if t, ok := p.accept(op); !ok {
+		return unexpected(t, fmt.Sprintf("%q", op))
+	}
+	return nil
+}
+
+func unexpected(t token, want string) error {
+	if t.kind == tokenEOF {
+		return errorAt(t.offset, "unexpected end of input, expected %s", want)
+	}
+	return errorAt(t.offset, "unexpected %q, expected %s", t.text, want)
+}
+
+func (p *parser) expr() (Node, error) {
+	x, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept("+", "-")
+		if !ok {
+			return x, nil
+		}
+		y, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		x = &Binary{t.text, x, y, t.offset}
+	}
+}
+
+func (p *parser) term() (Node, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept("*", "/", "%")
+		if !ok {
+			return x, nil
+		}
+		y, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		x = &Binary{t.text, x, y, t.offset}
+	}
+}
+
+func (p *parser) unary() (Node, error) {
+	if t, ok := p.accept("-", "+"); ok {
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{t.text, x, t.offset}, nil
+	}
+	return p.power()
+}
+
+func (p *parser) power() (Node, error) {
+	x, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := p.accept("^", "^^", "^^^")
+	if !ok {
+		return x, nil
+	}
+	y, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return &Binary{t.text, x, y, t.offset}, nil
+}
+
+func (p *parser) postfix() (Node, error) {
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept("!")
+		if !ok {
+			return x, nil
+		}
+		x = &Call{"factorial", []Node{x}, t.offset}
+	}
+}
+
+func (p *parser) primary() (Node, error) {
+	t := p.take()
+	switch t.kind {
+	case tokenNumber:
+		return &Number{bet.D(t.text), t.offset}, nil
+	case tokenString:
+		// D never fails, it reads what it can't parse as 0. Commas are ignored, like D does
+		if !literalPattern.MatchString(strings.ReplaceAll(t.text, ",", "")) {
+			return nil, errorAt(t.offset, "invalid number %q", t.text)
+		}
+		return &Number{bet.D(t.text), t.offset}, nil
+	case tokenName:
+		if _, ok := p.accept("("); !ok {
+			return &Variable{t.text, t.offset}, nil
+		}
+		call := &Call{Func: t.text, Offset: t.offset}
+		if _, ok := p.accept(")"); ok {
+			return call, nil
+		}
+		for {
+			arg, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			call.Args = append(call.Args, arg)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return call, nil
+	case tokenOperator:
+		if t.text == "(" {
+			x, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+	}
+	return nil, unexpected(t, "a number, a name or \"(\"")
+}
@@ -0,0 +1,46 @@
+package expression
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEvalBareE(t *testing.T) {
+	cases := map[string]float64{
+		"e+1":   math.E + 1,
+		"e-1":   math.E - 1,
+		"e - 1": math.E - 1,
+		"2*e-1": 2*math.E - 1,
+		"e5":    1e5,
+		"ee1":   1e10,
+		"1e-1":  0.1,
+	}
+	for src, want := range cases {
+		got, err := Eval(src, nil)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", src, err)
+			continue
+		}
+		if f := got.ToFloat64(); math.Abs(f-want) > 1e-12*math.Abs(want) {
+			t.Errorf("Eval(%q) = %v, want %v", src, f, want)
+		}
+	}
+}
+
+func TestParseQuotedLiteral(t *testing.T) {
+	for _, src := range []string{`"(e^6)20"`, `"5pt2"`, `"2p(3)"`, `"10f2"`, `"2^^3;2"`, `"1,000"`, `"-e5"`, `"ee20"`, `" 1.5e300 "`} {
+		if _, err := Parse(src); err != nil {
+			t.Errorf("Parse(%s): %v", src, err)
+		}
+	}
+	for _, src := range []string{`"abc"`, `""`, `"1.5x"`, `"NaN"`, `"--5"`, `"e"`, `1 + "abc"`} {
+		_, err := Parse(src)
+		perr, ok := err.(*Error)
+		if !ok {
+			t.Errorf("Parse(%s) = %v, want an *Error", src, err)
+		} else if want := strings.IndexByte(src, '"'); perr.Offset != want {
+			t.Errorf("Parse(%s) reported offset %d, want %d", src, perr.Offset, want)
+		}
+	}
+}
@@ -0,0 +1,543 @@
+package expression
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	bet "github.com/aapedro/breaketernity.go"
+)
+
+// Precedences of the grammar, from the loosest to the tightest binding
+const (
+	precAdd = iota + 1
+	precMul
+	precUnary
+	precPow
+	precPostfix
+	precPrimary
+)
+
+var binaryPrec = map[string]int{
+	"+": precAdd, "-": precAdd,
+	"*": precMul, "/": precMul, "%": precMul,
+	"^": precPow, "^^": precPow, "^^^": precPow,
+}
+
+// isFactorial reports whether a call is written as a trailing "!"
+func isFactorial(n *Call) bool {
+	return (n.Func == "factorial" || n.Func == "fact") && len(n.Args) == 1
+}
+
+func precedence(n Node) int {
+	switch n := n.(type) {
+	case *Number:
+		if n.Value.GetSign() < 0 {
+			return precUnary
+		}
+	case *Unary:
+		return precUnary
+	case *Binary:
+		return binaryPrec[n.Op]
+	case *Call:
+		if isFactorial(n) {
+			return precPostfix
+		}
+	}
+	return precPrimary
+}
+
+// leftParens reports whether the left operand of op must be parenthesized. Powers are right associative.
+func leftParens(op string, x Node) bool {
+	if binaryPrec[op] == precPow {
+		return precedence(x) <= precPow
+	}
+	return precedence(x) < binaryPrec[op]
+}
+
+// rightParens reports whether the right operand of op must be parenthesized. The exponent of a power may
+// have a leading minus.
+func rightParens(op string, y Node) bool {
+	if binaryPrec[op] == precPow {
+		return precedence(y) < precUnary
+	}
+	return precedence(y) <= binaryPrec[op]
+}
+
+// Text renders a syntax tree as a formula that Parse reads back to the same tree, with the fewest parentheses.
+// A negative Number is quoted, like "-5", since Parse reads -5 as the negation of 5. The exception is a Number of
+// negative infinity, which is written -inf and reads back as the negation of the constant inf.
+func Text(n Node) string {
+	var b strings.Builder
+	writeText(&b, n)
+	return b.String()
+}
+
+func writeText(b *strings.Builder, n Node) {
+	paren := func(x Node, parens bool) {
+		// Quoted negative numbers need no parentheses
+		if number, ok := x.(*Number); ok && !number.Value.IsInf() {
+			parens = false
+		}
+		if parens {
+			b.WriteString("(")
+			writeText(b, x)
+			b.WriteString(")")
+		} else {
+			writeText(b, x)
+		}
+	}
+	switch n := n.(type) {
+	case *Number:
+		b.WriteString(numberText(n.Value))
+	case *Variable:
+		b.WriteString(n.Name)
+	case *Unary:
+		b.WriteString(n.Op)
+		paren(n.X, precedence(n.X) < precUnary)
+	case *Binary:
+		paren(n.X, leftParens(n.Op, n.X))
+		if binaryPrec[n.Op] == precPow {
+			b.WriteString(n.Op)
+		} else {
+			b.WriteString(" " + n.Op + " ")
+		}
+		paren(n.Y, rightParens(n.Op, n.Y))
+	case *Call:
+		// Parse reads x! as factorial, so fact(x) is written as it is
+		if isFactorial(n) && n.Func == "factorial" {
+			paren(n.Args[0], precedence(n.Args[0]) < precPostfix)
+			b.WriteString("!")
+			return
+		}
+		b.WriteString(n.Func + "(")
+		for i, arg := range n.Args {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeText(b, arg)
+		}
+		b.WriteString(")")
+	}
+}
+
+// numberText writes a number as a literal, quoting negative numbers and the formats of D that are not numbers in
+// the grammar
+func numberText(d *bet.Decimal) string {
+	switch {
+	case d.IsNaN():
+		return "nan"
+	case d.IsInf() && d.GetSign() < 0:
+		return "-inf"
+	case d.IsInf():
+		return "inf"
+	}
+	s := d.ToString()
+	if d.GetSign() >= 0 && (numberPattern.FindString(s) == s || ePattern.FindString(s) == s) {
+		return s
+	}
+	return `"` + s + `"`
+}
+
+// numberParts splits a positive finite number into the mantissa and exponent of m×10^e, or into the number of
+// towers of 10 and the top exponent of 10^10^…^x for layer 2 and above. The tower is negative when
+// the top exponent is.
+func numberParts(d *bet.Decimal) (mantissa string, exponent string, towers int, top string) {
+	s := d.ToString()
+	if d.GetLayer() < 2 {
+		if i := strings.IndexAny(s, "e"); i >= 0 {
+			return s[:i], strings.TrimPrefix(s[i+1:], "+"), 0, ""
+		}
+		return s, "", 0, ""
+	}
+	return "", "", int(d.GetLayer()), strconv.FormatFloat(d.GetMag(), 'f', -1, 64)
+}
+
+// LaTeX renders a syntax tree as a LaTeX math formula.
+func LaTeX(n Node) string {
+	var b strings.Builder
+	writeLaTeX(&b, n)
+	return b.String()
+}
+
+// latexNames are the functions written as operator names
+var latexNames = map[string]string{
+	"ln":    `\ln`,
+	"gamma": `\Gamma`,
+	"exp":   `\exp`,
+	"min":   `\min`,
+	"max":   `\max`,
+}
+
+func writeLaTeX(b *strings.Builder, n Node) {
+	paren := func(x Node, parens bool) {
+		if parens {
+			b.WriteString(`\left(`)
+			writeLaTeX(b, x)
+			b.WriteString(`\right)`)
+		} else {
+			writeLaTeX(b, x)
+		}
+	}
+	group := func(x Node) {
+		b.WriteString("{")
+		writeLaTeX(b, x)
+		b.WriteString("}")
+	}
+	call := func(name string, args []Node) {
+		b.WriteString(name + `\left(`)
+		for i, arg := range args {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeLaTeX(b, arg)
+		}
+		b.WriteString(`\right)`)
+	}
+
+	switch n := n.(type) {
+	case *Number:
+		b.WriteString(latexNumber(n.Value))
+	case *Variable:
+		b.WriteString(latexVariable(n.Name))
+	case *Unary:
+		b.WriteString(n.Op)
+		paren(n.X, precedence(n.X) < precUnary)
+	case *Binary:
+		switch n.Op {
+		case "/":
+			b.WriteString(`\frac`)
+			group(n.X)
+			group(n.Y)
+		case "^":
+			b.WriteString("{")
+			paren(n.X, leftParens(n.Op, n.X))
+			b.WriteString("}^")
+			group(n.Y)
+		case "^^":
+			b.WriteString("{}^")
+			group(n.Y)
+			paren(n.X, precedence(n.X) < precPostfix)
+		default:
+			paren(n.X, leftParens(n.Op, n.X))
+			b.WriteString(map[string]string{"+": " + ", "-": " - ", "*": ` \cdot `, "%": ` \bmod `, "^^^": ` \uparrow\uparrow\uparrow `}[n.Op])
+			paren(n.Y, rightParens(n.Op, n.Y))
+		}
+	case *Call:
+		a := n.Args
+		switch {
+		case isFactorial(n):
+			paren(a[0], precedence(a[0]) < precPostfix)
+			b.WriteString("!")
+		case n.Func == "sqrt" && len(a) == 1:
+			b.WriteString(`\sqrt`)
+			group(a[0])
+		case n.Func == "root" && len(a) == 2:
+			b.WriteString(`\sqrt[`)
+			writeLaTeX(b, a[1])
+			b.WriteString("]")
+			group(a[0])
+		case n.Func == "abs" && len(a) == 1:
+			b.WriteString(`\left|`)
+			writeLaTeX(b, a[0])
+			b.WriteString(`\right|`)
+		case n.Func == "floor" && len(a) == 1:
+			b.WriteString(`\left\lfloor `)
+			writeLaTeX(b, a[0])
+			b.WriteString(`\right\rfloor`)
+		case n.Func == "ceil" && len(a) == 1:
+			b.WriteString(`\left\lceil `)
+			writeLaTeX(b, a[0])
+			b.WriteString(`\right\rceil`)
+		case n.Func == "recip" && len(a) == 1:
+			b.WriteString(`\frac{1}`)
+			group(a[0])
+		case n.Func == "exp" && len(a) == 1:
+			b.WriteString("e^")
+			group(a[0])
+		case n.Func == "pow10" && len(a) == 1:
+			b.WriteString("10^")
+			group(a[0])
+		case n.Func == "pow" && len(a) == 2:
+			writeLaTeX(b, &Binary{"^", a[0], a[1], n.Offset})
+		case n.Func == "powbase" && len(a) == 2:
+			writeLaTeX(b, &Binary{"^", a[1], a[0], n.Offset})
+		case (n.Func == "tetrate" || n.Func == "iteratedexp") && len(a) == 2:
+			writeLaTeX(b, &Binary{"^^", a[0], a[1], n.Offset})
+		case n.Func == "pentate" && len(a) == 2:
+			writeLaTeX(b, &Binary{"^^^", a[0], a[1], n.Offset})
+		case n.Func == "log10" || n.Func == "log2" || n.Func == "log" || n.Func == "slog":
+			name, base := latexLogBase(n)
+			b.WriteString(name + "_")
+			b.WriteString("{" + base + "}")
+			call("", a[:min(len(a), 1)])
+		default:
+			name, ok := latexNames[n.Func]
+			if !ok {
+				name = `\operatorname{` + n.Func + "}"
+			}
+			call(name, a)
+		}
+	}
+}
+
+// latexLogBase returns the name and the rendered base of a logarithm, whose base is the second argument if any
+func latexLogBase(n *Call) (string, string) {
+	name := `\log`
+	if n.Func == "slog" {
+		name = `\operatorname{slog}`
+	}
+	switch {
+	case n.Func == "log2":
+		return name, "2"
+	case len(n.Args) >= 2 && (n.Func == "log" || n.Func == "slog"):
+		return name, LaTeX(n.Args[1])
+	}
+	return name, "10"
+}
+
+func latexVariable(name string) string {
+	switch name {
+	case "pi":
+		return `\pi`
+	case "inf":
+		return `\infty`
+	case "nan":
+		return `\mathrm{NaN}`
+	}
+	if len(name) == 1 {
+		return name
+	}
+	return `\mathit{` + strings.ReplaceAll(name, "_", `\_`) + "}"
+}
+
+func latexNumber(d *bet.Decimal) string {
+	switch {
+	case d.IsNaN():
+		return `\mathrm{NaN}`
+	case d.GetSign() < 0:
+		return "-" + latexNumber(d.Abs())
+	case d.IsInf():
+		return `\infty`
+	}
+	mantissa, exponent, towers, top := numberParts(d)
+	switch {
+	case towers > 0:
+		negative := strings.HasPrefix(top, "-")
+		s := strings.TrimPrefix(top, "-")
+		if towers > 4 {
+			s = fmt.Sprintf(`\left(10\uparrow\right)^{%d} %s`, towers-1, s)
+			towers = 1
+		}
+		// The sign of a negative top exponent applies to the outermost exponent
+		for i := 0; i < towers; i++ {
+			if negative && i == towers-1 {
+				s = "-" + s
+			}
+			s = "10^{" + s + "}"
+		}
+		return s
+	case exponent == "":
+		return mantissa
+	case mantissa == "1":
+		return "10^{" + exponent + "}"
+	}
+	return mantissa + ` \times 10^{` + exponent + "}"
+}
+
+// MathML renders a syntax tree as a presentation MathML <math> element.
+func MathML(n Node) string {
+	var b strings.Builder
+	b.WriteString(`<math xmlns="http://www.w3.org/1998/Math/MathML">`)
+	writeMathML(&b, n)
+	b.WriteString("</math>")
+	return b.String()
+}
+
+// mathmlOps are the symbols of the infix operators
+var mathmlOps = map[string]string{
+	"+":   "+",
+	"-":   "-",
+	"*":   "&#x22C5;",
+	"%":   "mod",
+	"^^^": "&#x2191;&#x2191;&#x2191;",
+}
+
+func writeMathML(b *strings.Builder, n Node) {
+	paren := func(x Node, parens bool) {
+		b.WriteString("<mrow>")
+		if parens {
+			b.WriteString("<mo>(</mo>")
+		}
+		writeMathML(b, x)
+		if parens {
+			b.WriteString("<mo>)</mo>")
+		}
+		b.WriteString("</mrow>")
+	}
+	row := func(x Node) {
+		paren(x, false)
+	}
+	fenced := func(open string, x Node, close string) {
+		b.WriteString("<mrow><mo>" + open + "</mo>")
+		writeMathML(b, x)
+		b.WriteString("<mo>" + close + "</mo></mrow>")
+	}
+	call := func(name string, args []Node) {
+		b.WriteString("<mrow>" + name + "<mo>&#x2061;</mo><mrow><mo>(</mo>")
+		for i, arg := range args {
+			if i > 0 {
+				b.WriteString("<mo>,</mo>")
+			}
+			writeMathML(b, arg)
+		}
+		b.WriteString("<mo>)</mo></mrow></mrow>")
+	}
+
+	switch n := n.(type) {
+	case *Number:
+		b.WriteString(mathmlNumber(n.Value))
+	case *Variable:
+		b.WriteString(mathmlVariable(n.Name))
+	case *Unary:
+		b.WriteString("<mrow><mo>" + n.Op + "</mo>")
+		paren(n.X, precedence(n.X) < precUnary)
+		b.WriteString("</mrow>")
+	case *Binary:
+		switch n.Op {
+		case "/":
+			b.WriteString("<mfrac>")
+			row(n.X)
+			row(n.Y)
+			b.WriteString("</mfrac>")
+		case "^":
+			b.WriteString("<msup>")
+			paren(n.X, leftParens(n.Op, n.X))
+			row(n.Y)
+			b.WriteString("</msup>")
+		case "^^":
+			b.WriteString("<mmultiscripts>")
+			paren(n.X, precedence(n.X) < precPostfix)
+			b.WriteString("<mprescripts/><none/>")
+			row(n.Y)
+			b.WriteString("</mmultiscripts>")
+		default:
+			b.WriteString("<mrow>")
+			paren(n.X, leftParens(n.Op, n.X))
+			b.WriteString("<mo>" + mathmlOps[n.Op] + "</mo>")
+			paren(n.Y, rightParens(n.Op, n.Y))
+			b.WriteString("</mrow>")
+		}
+	case *Call:
+		a := n.Args
+		switch {
+		case isFactorial(n):
+			b.WriteString("<mrow>")
+			paren(a[0], precedence(a[0]) < precPostfix)
+			b.WriteString("<mo>!</mo></mrow>")
+		case n.Func == "sqrt" && len(a) == 1:
+			b.WriteString("<msqrt>")
+			writeMathML(b, a[0])
+			b.WriteString("</msqrt>")
+		case n.Func == "root" && len(a) == 2:
+			b.WriteString("<mroot>")
+			row(a[0])
+			row(a[1])
+			b.WriteString("</mroot>")
+		case n.Func == "abs" && len(a) == 1:
+			fenced("|", a[0], "|")
+		case n.Func == "floor" && len(a) == 1:
+			fenced("&#x230A;", a[0], "&#x230B;")
+		case n.Func == "ceil" && len(a) == 1:
+			fenced("&#x2308;", a[0], "&#x2309;")
+		case n.Func == "recip" && len(a) == 1:
+			b.WriteString("<mfrac><mn>1</mn>")
+			row(a[0])
+			b.WriteString("</mfrac>")
+		case n.Func == "exp" && len(a) == 1:
+			b.WriteString("<msup><mi>e</mi>")
+			row(a[0])
+			b.WriteString("</msup>")
+		case n.Func == "pow10" && len(a) == 1:
+			b.WriteString("<msup><mn>10</mn>")
+			row(a[0])
+			b.WriteString("</msup>")
+		case n.Func == "pow" && len(a) == 2:
+			writeMathML(b, &Binary{"^", a[0], a[1], n.Offset})
+		case n.Func == "powbase" && len(a) == 2:
+			writeMathML(b, &Binary{"^", a[1], a[0], n.Offset})
+		case (n.Func == "tetrate" || n.Func == "iteratedexp") && len(a) == 2:
+			writeMathML(b, &Binary{"^^", a[0], a[1], n.Offset})
+		case n.Func == "pentate" && len(a) == 2:
+			writeMathML(b, &Binary{"^^^", a[0], a[1], n.Offset})
+		case n.Func == "log10" || n.Func == "log2" || n.Func == "log" || n.Func == "slog":
+			name := map[string]string{"slog": "slog"}[n.Func]
+			if name == "" {
+				name = "log"
+			}
+			var base string
+			switch {
+			case n.Func == "log2":
+				base = "<mn>2</mn>"
+			case len(a) >= 2 && (n.Func == "log" || n.Func == "slog"):
+				var sub strings.Builder
+				writeMathML(&sub, a[1])
+				base = "<mrow>" + sub.String() + "</mrow>"
+			default:
+				base = "<mn>10</mn>"
+			}
+			call("<msub><mi>"+name+"</mi>"+base+"</msub>", a[:min(len(a), 1)])
+		case n.Func == "gamma":
+			call("<mi>&#x393;</mi>", a)
+		default:
+			call("<mi>"+html.EscapeString(n.Func)+"</mi>", a)
+		}
+	}
+}
+
+func mathmlVariable(name string) string {
+	switch name {
+	case "pi":
+		return "<mi>&#x3C0;</mi>"
+	case "inf":
+		return "<mi>&#x221E;</mi>"
+	case "nan":
+		return "<mi>NaN</mi>"
+	}
+	return "<mi>" + html.EscapeString(name) + "</mi>"
+}
+
+func mathmlNumber(d *bet.Decimal) string {
+	switch {
+	case d.IsNaN():
+		return "<mi>NaN</mi>"
+	case d.GetSign() < 0:
+		return "<mrow><mo>-</mo>" + mathmlNumber(d.Abs()) + "</mrow>"
+	case d.IsInf():
+		return "<mi>&#x221E;</mi>"
+	}
+	mantissa, exponent, towers, top := numberParts(d)
+	switch {
+	case towers > 0:
+		negative := strings.HasPrefix(top, "-")
+		s := "<mn>" + strings.TrimPrefix(top, "-") + "</mn>"
+		if towers > 4 {
+			s = fmt.Sprintf("<mrow><msup><mrow><mo>(</mo><mn>10</mn><mo>&#x2191;</mo><mo>)</mo></mrow><mn>%d</mn></msup>%s</mrow>", towers-1, s)
+			towers = 1
+		}
+		// The sign of a negative top exponent applies to the outermost exponent
+		for i := 0; i < towers; i++ {
+			if negative && i == towers-1 {
+				s = "<mrow><mo>-</mo>" + s + "</mrow>"
+			}
+			s = "<msup><mn>10</mn>" + s + "</msup>"
+		}
+		return s
+	case exponent == "":
+		return "<mn>" + mantissa + "</mn>"
+	case mantissa == "1":
+		return "<msup><mn>10</mn><mn>" + exponent + "</mn></msup>"
+	}
+	return "<mrow><mn>" + mantissa + "</mn><mo>&#xD7;</mo><msup><mn>10</mn><mn>" + exponent + "</mn></msup></mrow>"
+}
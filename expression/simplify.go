@@ -0,0 +1,403 @@
+package expression
+
+import (
+	"math"
+	"math/big"
+
+	bet "github.com/aapedro/breaketernity.go"
+)
+
+// maxSimplifyPasses bounds the rewriting, in case rules ever undo each other
+const maxSimplifyPasses = 64
+
+// Simplify rewrites a syntax tree into a simpler equivalent one. It assumes that the values of the variables
+// are in the domain of the functions applied to them, e.g. that x > 0 in ln(x^2) = 2 * ln(x).
+//
+//   - Constant folding: operations on numbers are evaluated with Decimal when the result is exact, so 2^3^2 becomes
+//     512 but 0.1 + 0.2 and ln(2) are kept. A negative result becomes the negation of a number, as Parse reads it.
+//   - Identities: x + 0, x - 0, x * 1, x / 1, x^1, --x and +x become x.
+//   - Powers: (x^a)^b becomes x^(a * b).
+//   - Logarithms of ln, log10, log2 and log: log(a * b) becomes log(a) + log(b), log(a / b) becomes
+//     log(a) - log(b), and log(a^b) becomes b * log(a).
+//   - Inverses: slog(b^^h, b), b^^slog(x, b), ln(exp(x)), exp(ln(x)), log10(pow10(x)) and pow10(log10(x)) cancel,
+//     as do the same with tetrate and the default base 10 of slog.
+//
+// The tree passed in is not modified.
+func Simplify(n Node) Node {
+	for range maxSimplifyPasses {
+		next := simplifyPass(n)
+		if Equal(next, n) {
+			return next
+		}
+		n = next
+	}
+	return n
+}
+
+// simplifyPass simplifies the children of n, then n itself
+func simplifyPass(n Node) Node {
+	switch n := n.(type) {
+	case *Unary:
+		return simplifyUnary(&Unary{n.Op, simplifyPass(n.X), n.Offset})
+	case *Binary:
+		return simplifyBinary(&Binary{n.Op, simplifyPass(n.X), simplifyPass(n.Y), n.Offset})
+	case *Call:
+		args := make([]Node, len(n.Args))
+		for i, arg := range n.Args {
+			args[i] = simplifyPass(arg)
+		}
+		return simplifyCall(&Call{n.Func, args, n.Offset})
+	}
+	return n
+}
+
+// foldValue returns the result of an operation on numbers as a Number, or as the negation of one if it is negative,
+// unless it is NaN or inexact. Folding an inexact result would write its rounding error into the tree.
+func foldValue(value *bet.Decimal, exact bool, offset int) (Node, bool) {
+	if !exact || value.IsNaN() {
+		return nil, false
+	}
+	if value.GetSign() < 0 {
+		return &Unary{"-", &Number{value.Abs(), offset}, offset}, true
+	}
+	return &Number{value, offset}, true
+}
+
+// exactBinary returns the exact result of x op y, if it is a float64. The arithmetic operators and integer powers are
+// computed with rationals, since Decimal rounds 2^9 to 511.99999999999955, and % is only folded on integers.
+func exactBinary(op string, x, y *bet.Decimal) (*bet.Decimal, bool) {
+	rx, xOK := ratOf(x)
+	ry, yOK := ratOf(y)
+	if !xOK || !yOK {
+		return nil, false
+	}
+	r := new(big.Rat)
+	switch op {
+	case "+":
+		r.Add(rx, ry)
+	case "-":
+		r.Sub(rx, ry)
+	case "*":
+		r.Mul(rx, ry)
+	case "/":
+		if ry.Sign() == 0 {
+			return nil, false
+		}
+		r.Quo(rx, ry)
+	case "^":
+		var ok bool
+		if r, ok = ratPow(rx, ry); !ok {
+			return nil, false
+		}
+	case "%":
+		value := binaryOps[op](x, y)
+		return value, exactIntegers(x, y, value)
+	default:
+		// Tetration and pentation go through logarithms, so even an integer result may be a rounded one
+		return nil, false
+	}
+	f, exact := r.Float64()
+	return bet.D(f), exact && !math.IsInf(f, 0)
+}
+
+// ratOf returns the exact value of d if it is a finite number on layer 0, where it is a float64
+func ratOf(d *bet.Decimal) (*big.Rat, bool) {
+	if d.GetLayer() != 0 || d.IsInf() || d.IsNaN() {
+		return nil, false
+	}
+	return new(big.Rat).SetFloat64(d.ToFloat64()), true
+}
+
+// ratPow returns x^n for an integer n of at most 64, exactly
+func ratPow(x *big.Rat, n *big.Rat) (*big.Rat, bool) {
+	f, _ := n.Float64()
+	if !n.IsInt() || math.Abs(f) > 64 || x.Sign() == 0 && f < 0 {
+		return nil, false
+	}
+	r := new(big.Rat).SetInt64(1)
+	for range int(math.Abs(f)) {
+		r.Mul(r, x)
+	}
+	if f < 0 {
+		r.Inv(r)
+	}
+	return r, true
+}
+
+// exactFunctions only round, compare or change signs, so their result is exact for any float64 arguments
+var exactFunctions = map[string]bool{
+	"abs": true, "neg": true, "round": true, "floor": true, "ceil": true, "trunc": true,
+	"min": true, "max": true, "minabs": true, "maxabs": true, "cmp": true, "clamp": true,
+}
+
+// logBases are the bases of the logarithms whose results are checked by raising the base to them
+var logBases = map[string]int64{"log2": 2, "log10": 10}
+
+// exactCall reports whether value is the exact result of the function on the arguments. A result that merely looks
+// exact is not enough: log2(2^52 - 1) rounds to 52. So only the functions that are exact by construction are trusted,
+// mod on integers, and sqrt and the logarithms once their inverse gives the argument back exactly.
+func exactCall(name string, args []*bet.Decimal, value *bet.Decimal) bool {
+	rv, ok := ratOf(value)
+	if !ok {
+		return false
+	}
+	rargs := make([]*big.Rat, len(args))
+	for i, arg := range args {
+		if rargs[i], ok = ratOf(arg); !ok {
+			return false
+		}
+	}
+	switch {
+	case exactFunctions[name]:
+		return true
+	case name == "mod":
+		return exactIntegers(append(args, value)...)
+	case name == "sqrt":
+		return rv.Sign() >= 0 && new(big.Rat).Mul(rv, rv).Cmp(rargs[0]) == 0
+	case logBases[name] != 0:
+		power, ok := ratPow(new(big.Rat).SetInt64(logBases[name]), rv)
+		return ok && power.Cmp(rargs[0]) == 0
+	}
+	return false
+}
+
+// exactIntegers reports whether the values are all integers below 2^52, where a float64 still has fractional bits,
+// so that an integer result is not a rounded one
+func exactIntegers(values ...*bet.Decimal) bool {
+	for _, v := range values {
+		f := v.ToFloat64()
+		if v.GetLayer() != 0 || f != math.Trunc(f) || math.Abs(f) >= 0x1p52 {
+			return false
+		}
+	}
+	return true
+}
+
+// numberValue returns the value of n if it is a Number or the negation of one
+func numberValue(n Node) (*bet.Decimal, bool) {
+	switch n := n.(type) {
+	case *Number:
+		return n.Value, true
+	case *Unary:
+		if number, ok := n.X.(*Number); ok && n.Op == "-" {
+			return number.Value.Neg(), true
+		}
+	}
+	return nil, false
+}
+
+// isNumber reports whether n is a Number equal to x
+func isNumber(n Node, x float64) bool {
+	value, ok := numberValue(n)
+	return ok && value.Eq(bet.D(x))
+}
+
+func simplifyUnary(n *Unary) Node {
+	if n.Op == "+" {
+		return n.X
+	}
+	if inner, ok := n.X.(*Unary); ok && inner.Op == "-" {
+		return inner.X
+	}
+	// -x is left as it is when x is a number, it is how Parse reads a negative number
+	if value, ok := numberValue(n.X); ok && value.GetSign() < 0 {
+		return &Number{value.Neg(), n.Offset}
+	}
+	return n
+}
+
+func simplifyBinary(n *Binary) Node {
+	x, xIsNumber := numberValue(n.X)
+	y, yIsNumber := numberValue(n.Y)
+	if xIsNumber && yIsNumber {
+		value, exact := exactBinary(n.Op, x, y)
+		if folded, ok := foldValue(value, exact, n.Offset); ok {
+			return folded
+		}
+	}
+
+	switch n.Op {
+	case "+":
+		if isNumber(n.Y, 0) {
+			return n.X
+		}
+		if isNumber(n.X, 0) {
+			return n.Y
+		}
+	case "-":
+		if isNumber(n.Y, 0) {
+			return n.X
+		}
+	case "*":
+		if isNumber(n.Y, 1) {
+			return n.X
+		}
+		if isNumber(n.X, 1) {
+			return n.Y
+		}
+	case "/":
+		if isNumber(n.Y, 1) {
+			return n.X
+		}
+	case "^":
+		if isNumber(n.Y, 1) {
+			return n.X
+		}
+		// (x^a)^b = x^(a * b)
+		if inner, ok := n.X.(*Binary); ok && inner.Op == "^" {
+			return &Binary{"^", inner.X, &Binary{"*", inner.Y, n.Y, n.Offset}, n.Offset}
+		}
+	case "^^":
+		// b^^slog(x, b) = x
+		if x, ok := slogOf(n.Y, n.X); ok {
+			return x
+		}
+	}
+	return n
+}
+
+// logarithms maps each logarithm to its inverse
+var logarithms = map[string]string{
+	"ln":    "exp",
+	"log10": "pow10",
+	"log2":  "",
+	"log":   "",
+}
+
+func simplifyCall(n *Call) Node {
+	if f, ok := functions[n.Func]; ok && len(n.Args) >= f.min && (f.max < 0 || len(n.Args) <= f.max) {
+		values := make([]*bet.Decimal, len(n.Args), max(len(n.Args), f.max))
+		allNumbers := true
+		for i, arg := range n.Args {
+			if values[i], allNumbers = numberValue(arg); !allNumbers {
+				break
+			}
+		}
+		if allNumbers {
+			if len(values) < f.max {
+				values = append(values, f.defaults[len(values)-f.min:]...)
+			}
+			value := f.call(values)
+			if folded, ok := foldValue(value, exactCall(n.Func, values, value), n.Offset); ok {
+				return folded
+			}
+		}
+	}
+
+	if inverse, ok := logarithms[n.Func]; ok && len(n.Args) >= 1 {
+		if expanded, ok := expandLog(n); ok {
+			return expanded
+		}
+		// ln(exp(x)) = x, log10(pow10(x)) = x
+		if inner, ok := n.Args[0].(*Call); ok && inverse != "" && inner.Func == inverse && len(inner.Args) == 1 {
+			return inner.Args[0]
+		}
+	}
+
+	switch n.Func {
+	case "exp", "pow10":
+		// exp(ln(x)) = x, pow10(log10(x)) = x
+		inverse := map[string]string{"exp": "ln", "pow10": "log10"}[n.Func]
+		if len(n.Args) == 1 {
+			if inner, ok := n.Args[0].(*Call); ok && inner.Func == inverse && len(inner.Args) == 1 {
+				return inner.Args[0]
+			}
+		}
+	case "slog":
+		// slog(b^^h, b) = h
+		if len(n.Args) >= 1 {
+			if height, ok := tetrationHeight(n.Args[0], slogBase(n)); ok {
+				return height
+			}
+		}
+	case "tetrate", "iteratedexp":
+		// tetrate(b, slog(x, b)) = x
+		if len(n.Args) == 2 {
+			if x, ok := slogOf(n.Args[1], n.Args[0]); ok {
+				return x
+			}
+		}
+	}
+	return n
+}
+
+// expandLog splits the logarithm of a product, quotient or power
+func expandLog(n *Call) (Node, bool) {
+	arg, ok := n.Args[0].(*Binary)
+	if !ok {
+		return nil, false
+	}
+	log := func(x Node) Node {
+		return &Call{n.Func, append([]Node{x}, n.Args[1:]...), n.Offset}
+	}
+	switch arg.Op {
+	case "*":
+		return &Binary{"+", log(arg.X), log(arg.Y), n.Offset}, true
+	case "/":
+		return &Binary{"-", log(arg.X), log(arg.Y), n.Offset}, true
+	case "^":
+		return &Binary{"*", arg.Y, log(arg.X), n.Offset}, true
+	}
+	return nil, false
+}
+
+// slogBase returns the base of a call of slog
+func slogBase(n *Call) Node {
+	if len(n.Args) >= 2 {
+		return n.Args[1]
+	}
+	return &Number{bet.D(10), n.Offset}
+}
+
+// tetrationHeight returns h if n is base^^h or tetrate(base, h)
+func tetrationHeight(n Node, base Node) (Node, bool) {
+	switch n := n.(type) {
+	case *Binary:
+		if n.Op == "^^" && Equal(n.X, base) {
+			return n.Y, true
+		}
+	case *Call:
+		if (n.Func == "tetrate" || n.Func == "iteratedexp") && len(n.Args) == 2 && Equal(n.Args[0], base) {
+			return n.Args[1], true
+		}
+	}
+	return nil, false
+}
+
+// slogOf returns x if n is slog(x, base)
+func slogOf(n Node, base Node) (Node, bool) {
+	if call, ok := n.(*Call); ok && call.Func == "slog" && len(call.Args) >= 1 && Equal(slogBase(call), base) {
+		return call.Args[0], true
+	}
+	return nil, false
+}
+
+// Equal reports whether two syntax trees are the same, ignoring offsets. Numbers are compared with Eq.
+func Equal(a, b Node) bool {
+	switch a := a.(type) {
+	case *Number:
+		b, ok := b.(*Number)
+		return ok && (a.Value.Eq(b.Value) || a.Value.IsNaN() && b.Value.IsNaN())
+	case *Variable:
+		b, ok := b.(*Variable)
+		return ok && a.Name == b.Name
+	case *Unary:
+		b, ok := b.(*Unary)
+		return ok && a.Op == b.Op && Equal(a.X, b.X)
+	case *Binary:
+		b, ok := b.(*Binary)
+		return ok && a.Op == b.Op && Equal(a.X, b.X) && Equal(a.Y, b.Y)
+	case *Call:
+		b, ok := b.(*Call)
+		if !ok || a.Func != b.Func || len(a.Args) != len(b.Args) {
+			return false
+		}
+		for i := range a.Args {
+			if !Equal(a.Args[i], b.Args[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package expression
+
+import "testing"
+
+func TestSimplifyFoldsExactResults(t *testing.T) {
+	cases := map[string]string{
+		"2^3^2":       "512",
+		"x^(1/2)^2":   "x^0.25",
+		"2^-2":        "0.25",
+		"1 - 3":       "-2",
+		"x * (3-5)":   "x * -2",
+		"sqrt(4)":     "2",
+		"0.1 + 0.2":   "0.1 + 0.2",
+		"1 / 3":       "1 / 3",
+		"ln(2)":       "ln(2)",
+		"2^0.5":       "2^0.5",
+		"floor(2.5)":  "2",
+		"mod(7, 3)":   "1",
+		"max(2, 3)":   "3",
+		"abs(-4)":     "4",
+		"log2(8)":     "3",
+		"log10(1000)": "3",
+		"sqrt(2)":     "sqrt(2)",
+		"2^^3":        "2^^3",
+		// Rounded to a whole number in float64, but not exactly
+		"log2(4503599627370495)":           "log2(4.503599627370495e+15)",
+		"iteratedlog(4503599627370495, 2)": "iteratedlog(4.503599627370495e+15, 2)",
+	}
+	for src, want := range cases {
+		n, err := Parse(src)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", src, err)
+		}
+		if got := Text(Simplify(n)); got != want {
+			t.Errorf("Text(Simplify(%q)) = %q, want %q", src, got, want)
+		}
+	}
+}
+
+func TestTextRoundTrips(t *testing.T) {
+	for _, src := range []string{"(-2)^x", "x - -1", "1 - 3", "neg(2)^x", `"-5"^x`, `"-e5" * x`, "fact(5)", "5!", "0 - inf"} {
+		n, err := Parse(src)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", src, err)
+		}
+		for _, tree := range []Node{n, Simplify(n)} {
+			text := Text(tree)
+			back, err := Parse(text)
+			if err != nil {
+				t.Errorf("Parse(Text(%q)) = Parse(%q): %v", src, text, err)
+			} else if !Equal(back, tree) {
+				t.Errorf("Parse(Text(%q)) = Parse(%q) is not the same tree", src, text)
+			}
+		}
+	}
+}
+
+func TestSimplifyRewrites(t *testing.T) {
+	cases := []struct {
+		src, want string
+	}{
+		{"x + 0", "x"},
+		{"x * 1", "x"},
+		{"--x", "x"},
+		{"(x^2)^3", "x^6"},
+		{"ln(a*b)", "ln(a) + ln(b)"},
+		{"log10(a/b)", "log10(a) - log10(b)"},
+		{"log2(x^3)", "3 * log2(x)"},
+		{"ln(exp(x))", "x"},
+		{"pow10(log10(x))", "x"},
+		{"slog(10^^x)", "x"},
+		{"2^^x", "2^^x"},
+	}
+	for _, c := range cases {
+		n, err := Parse(c.src)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", c.src, err)
+		}
+		if got := Text(Simplify(n)); got != c.want {
+			t.Errorf("Text(Simplify(%q)) = %q, want %q", c.src, got, c.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		src, latex, mathml string
+	}{
+		{"sqrt(x)/2", `\frac{\sqrt{x}}{2}`, `<mfrac><mrow><msqrt><mi>x</mi></msqrt></mrow><mrow><mn>2</mn></mrow></mfrac>`},
+		{"(x^2)^3", `{\left({x}^{2}\right)}^{3}`, `<msup><mrow><mo>(</mo><msup><mrow><mi>x</mi></mrow><mrow><mn>2</mn></mrow></msup><mo>)</mo></mrow><mrow><mn>3</mn></mrow></msup>`},
+		{"2^^x", `{}^{x}2`, `<mmultiscripts><mrow><mn>2</mn></mrow><mprescripts/><none/><mrow><mi>x</mi></mrow></mmultiscripts>`},
+		{"abs(x)", `\left|x\right|`, `<mrow><mo>|</mo><mi>x</mi><mo>|</mo></mrow>`},
+		{"log(x, 3)", `\log_{3}\left(x\right)`, `<mrow><msub><mi>log</mi><mrow><mn>3</mn></mrow></msub><mo>&#x2061;</mo><mrow><mo>(</mo><mi>x</mi><mo>)</mo></mrow></mrow>`},
+		{"floor(2.5)", `\left\lfloor 2.5\right\rfloor`, `<mrow><mo>&#x230A;</mo><mn>2.5</mn><mo>&#x230B;</mo></mrow>`},
+	}
+	const math = `<math xmlns="http://www.w3.org/1998/Math/MathML">`
+	for _, c := range cases {
+		n, err := Parse(c.src)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", c.src, err)
+		}
+		if got := LaTeX(n); got != c.latex {
+			t.Errorf("LaTeX(%q) = %q, want %q", c.src, got, c.latex)
+		}
+		if got, want := MathML(n), math+c.mathml+"</math>"; got != want {
+			t.Errorf("MathML(%q) = %q, want %q", c.src, got, want)
+		}
+	}
+}
@@ -3,7 +3,17 @@ package breaketernity
 import "math"
 
 func fMagLog10(x float64) float64 {
-	return math.Copysign(1, x) * math.Log10(math.Abs(x))
+	return math.Copysign(1, x) * fLog10(math.Abs(x))
+}
+
+// fLog10 is math.Log10, except for subnormal numbers where math.Log10 loses accuracy,
+// e.g. math.Log10(1e-312) is about -307.95 on amd64
+func fLog10(x float64) float64 {
+	if x > 0 && x < 0x1p-1022 {
+		frac, exp := math.Frexp(x)
+		return math.Log10(frac) + float64(exp)*math.Log10(2)
+	}
+	return math.Log10(x)
 }
 
 func fGamma(n float64) float64 {
@@ -48,6 +58,32 @@ func fGamma(n float64) float64 {
 	return math.Exp(l) / scal1
 }
 
+const EULER_MASCHERONI = 0.57721566490153286061 // -digamma(1)
+
+// fDigamma returns the digamma function, the derivative of ln(Gamma(x))
+func fDigamma(x float64) float64 {
+	if math.IsNaN(x) || math.IsInf(x, -1) || x <= 0 && x == math.Trunc(x) {
+		return math.NaN()
+	}
+	if math.IsInf(x, 1) {
+		return x
+	}
+	// Reflection: digamma(1 - x) - digamma(x) = pi / tan(pi * x)
+	if x < 0 {
+		return fDigamma(1-x) - math.Pi/math.Tan(math.Pi*x)
+	}
+
+	result := 0.0
+	for x < 10 {
+		result -= 1 / x
+		x++
+	}
+	x2 := 1 / (x * x)
+	result += math.Log(x) - 0.5/x
+	result -= x2 * (1.0/12 - x2*(1.0/120-x2*(1.0/252-x2*(1.0/240-x2/132))))
+	return result
+}
+
 const EXPN1 = 0.36787944117144232159553 // exp(-1)
 const OMEGA = 0.56714329040978387299997 // W(1, 0)
 func fLambertW(z float64, tol float64, principal bool) float64 {
@@ -81,13 +117,21 @@ func fLambertW(z float64, tol float64, principal bool) float64 {
 		}
 	}
 
+	previousStep := math.Inf(1)
 	for i := 0; i < 100; i++ {
 		wn = (z*math.Exp(-w) + w*w) / (w + 1)
-		if math.Abs(wn-w) < tol*math.Abs(wn) {
+		step := math.Abs(wn - w)
+		// A tolerance below float64 precision means iterating until the last bit, where wn can alternate between two
+		// neighbours instead of settling
+		if step <= math.Max(tol, 0x1p-52)*math.Abs(wn) {
+			return wn
+		}
+		// Once small steps stop shrinking, rounding dominates and wn is as precise as float64 allows,
+		// e.g. near the branch point at -1/e
+		if step >= previousStep && step <= 1e-6*math.Abs(wn) {
 			return wn
-		} else {
-			w = wn
 		}
+		w, previousStep = wn, step
 	}
 
 	panic("Iteration failed to converge")
@@ -0,0 +1,45 @@
+package breaketernity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFLog10Subnormal(t *testing.T) {
+	for _, exp := range []int{-1023, -1040, -1074} {
+		x := math.Ldexp(1, exp)
+		want := float64(exp) * math.Log10(2)
+		if got := fLog10(x); math.Abs(got-want) > 1e-12 {
+			t.Errorf("fLog10(2^%d) = %.15g, want %.15g", exp, got, want)
+		}
+	}
+	if got := fLog10(1000); got != 3 {
+		t.Errorf("fLog10(1000) = %g, want 3", got)
+	}
+}
+
+func TestFLambertWTerminates(t *testing.T) {
+	cases := []struct {
+		z         float64
+		principal bool
+	}{
+		// With a tolerance below float64 precision, these used to alternate between two neighbours until the panic
+		{7.5, true},
+		{2, true},
+		{1e5, true},
+		{-0.1, true},
+		// Near the branch point at -1/e, rounding stops the steps from shrinking long before they reach tol
+		{-EXPN1 + 1e-12, true},
+		{-EXPN1 + 1e-12, false},
+		{-0.2, false},
+		{-1e-5, false},
+	}
+	for _, c := range cases {
+		for _, tol := range []float64{0, 1e-100, 1e-10} {
+			w := fLambertW(c.z, tol, c.principal)
+			if got := w * math.Exp(w); math.Abs(got-c.z) > 1e-9*math.Abs(c.z) {
+				t.Errorf("fLambertW(%g, %g, %v) = %v, and w*e^w = %v", c.z, tol, c.principal, w, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package breaketernity
+
+import (
+	"math"
+	"testing"
+)
+
+// Fuzz targets for the invariants of Decimal. Run one with e.g.
+//
+//	go test -fuzz=FuzzDecimalFromString
+//
+// Inputs that once failed are kept in testdata/fuzz and replayed by go test.
+
+// Tolerances of the properties, relative to the magnitudes involved
+const (
+	// ToString prints the shortest representation of each float64, but the mantissa of layer 1 values is
+	// recomputed from the mag, which loses the low bits of large exponents
+	fuzzRoundTripTolerance = 1e-14
+	// Inverse pairs of operations round at every step
+	fuzzInverseTolerance = 1e-9
+	// Slog refines its result by bisection on Tetrate
+	fuzzSlogTolerance = 1e-6
+)
+
+// isNormalized reports whether d is in the form Normalize produces: NaN everywhere,
+// or a sign in {-1, 0, 1} and a whole layer that Normalize leaves unchanged.
+func isNormalized(d *Decimal) bool {
+	if math.IsNaN(d.sign) || math.IsNaN(d.layer) || math.IsNaN(d.mag) {
+		return math.IsNaN(d.sign) && math.IsNaN(d.layer) && math.IsNaN(d.mag)
+	}
+	if d.sign != -1 && d.sign != 0 && d.sign != 1 {
+		return false
+	}
+	if d.layer < 0 || d.layer != math.Trunc(d.layer) && !math.IsInf(d.layer, 1) {
+		return false
+	}
+	n := *d
+	n.Normalize()
+	return n == *d
+}
+
+// fuzzDecimal builds a finite Decimal from fuzzed components, with the layer brought between 0 and 4
+func fuzzDecimal(sign float64, layer float64, mag float64) (*Decimal, bool) {
+	if math.IsNaN(sign) || math.IsNaN(layer) || math.IsNaN(mag) || math.IsInf(layer, 0) || math.IsInf(mag, 0) {
+		return nil, false
+	}
+	s := 0.
+	if sign > 0 {
+		s = 1
+	} else if sign < 0 {
+		s = -1
+	}
+	d := dFC(s, math.Mod(math.Abs(math.Trunc(layer)), 5), mag)
+	return d, !d.IsNaN() && !d.IsInf()
+}
+
+// resolution returns the relative precision with which d is represented: that of a float64 on layer 0,
+// and that of its exponent on layer 1, which gets coarser as the exponent grows.
+func resolution(d *Decimal) float64 {
+	if d.layer == 0 {
+		return 0x1p-52
+	}
+	return math.Max(math.Abs(d.mag)*math.Ln10, 1) * 0x1p-52
+}
+
+// withinTolerance reports whether err <= |scale| * tolerance, where scale is at least 1,
+// allowing a few roundings at the resolution of scale. From layer 2 on, a relative error is too fine to mean
+// anything, so the tolerance applies to the logarithms instead: log10(err) may exceed log10(scale) by as much
+// as log10(scale) * tolerance, recursively until the logarithm is on layer 1 or below.
+func withinTolerance(err *Decimal, scale *Decimal, tolerance float64) bool {
+	scale = scale.Abs().Max(dOne)
+	if scale.layer >= 2 {
+		if err.sign == 0 {
+			return true
+		}
+		logScale := scale.Log10()
+		return withinTolerance(err.AbsLog10().Subtract(logScale).Max(dZero), logScale, tolerance)
+	}
+	return err.Lte(scale.Multiply(D(tolerance + 16*resolution(scale))))
+}
+
+// logCloseTo reports whether got and want have the same sign and base10 logarithms within tolerance,
+// relative to scale, the largest logarithm involved in computing got
+func logCloseTo(got *Decimal, want *Decimal, tolerance float64, scale *Decimal) bool {
+	if got.sign != want.sign {
+		return false
+	}
+	if want.sign == 0 {
+		return true
+	}
+	return withinTolerance(got.AbsLog10().Subtract(want.AbsLog10()).Abs(), scale, tolerance)
+}
+
+func FuzzDecimalFromString(f *testing.F) {
+	for _, s := range []string{
+		"0", "1", "-1.5", "1e400", "-1e-400", "ee20", "-ee20", "e5", "1e1e10", "2e3e4", "eeee5",
+		"(e^6)20", "-(e^10)1.5", "10^^3", "2^^^3", "10^^1.5;2", "3^4", "5pt2", "5p(2)", "2f5", "1,000", "Infinity", "NaN", "",
+	} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		d := decimalFromString(s, false)
+		if !isNormalized(d) {
+			t.Errorf("D(%q) = %v is not normalized", s, *d)
+		}
+	})
+}
+
+func FuzzToStringRoundTrip(f *testing.F) {
+	f.Add(1., 0., 1.5)
+	f.Add(-1., 0., 1e300)
+	f.Add(1., 1., -400.)
+	f.Add(1., 2., 20.5)
+	f.Add(-1., 4., 1e15)
+	f.Add(1., 1., 123456789.987654321)
+	f.Fuzz(func(t *testing.T, sign float64, layer float64, mag float64) {
+		d, ok := fuzzDecimal(sign, layer, mag)
+		if !ok {
+			return
+		}
+		s := d.ToString()
+		back := D(s)
+		if back.sign != d.sign || back.layer != d.layer || !closeTo(back.mag, d.mag, fuzzRoundTripTolerance) {
+			t.Errorf("D(%v.ToString() = %q) = %v", *d, s, *back)
+		}
+	})
+}
+
+func FuzzAddSubtract(f *testing.F) {
+	f.Add(1., 0., 3., -1., 0., 5.)
+	f.Add(1., 1., 400., 1., 1., 399.)
+	f.Add(1., 2., 20., -1., 0., 1.)
+	f.Add(1., 0., 1e-10, 1., 0., 1e10)
+	f.Fuzz(func(t *testing.T, s1, l1, m1, s2, l2, m2 float64) {
+		a, ok1 := fuzzDecimal(s1, l1, m1)
+		b, ok2 := fuzzDecimal(s2, l2, m2)
+		if !ok1 || !ok2 {
+			return
+		}
+		// (a + b) - b can only be as accurate as the largest of the values involved
+		sum := a.Add(b)
+		got := sum.Subtract(b)
+		if !withinTolerance(got.Subtract(a).Abs(), a.MaxAbs(b).MaxAbs(sum), fuzzInverseTolerance) {
+			t.Errorf("(%v + %v) - %v = %v", *a, *b, *b, *got)
+		}
+	})
+}
+
+func FuzzMultiplyDivide(f *testing.F) {
+	f.Add(1., 0., 3., -1., 0., 5.)
+	f.Add(1., 1., 400., 1., 1., -399.)
+	f.Add(1., 2., 20., -1., 0., 7.)
+	f.Fuzz(func(t *testing.T, s1, l1, m1, s2, l2, m2 float64) {
+		a, ok1 := fuzzDecimal(s1, l1, m1)
+		b, ok2 := fuzzDecimal(s2, l2, m2)
+		if !ok1 || !ok2 || b.sign == 0 {
+			return
+		}
+		product := a.Multiply(b)
+		if product.IsInf() || product.sign == 0 && a.sign != 0 {
+			return
+		}
+		// The logarithm of a * b is the sum of the logarithms, which is as accurate as the largest of them
+		got := product.Divide(b)
+		scale := a.AbsLog10().Abs().Max(b.AbsLog10().Abs())
+		if !logCloseTo(got, a, fuzzInverseTolerance, scale) {
+			t.Errorf("(%v * %v) / %v = %v", *a, *b, *b, *got)
+		}
+	})
+}
+
+func FuzzPowRoot(f *testing.F) {
+	f.Add(1., 0., 3., 2.)
+	f.Add(1., 1., 400., 0.5)
+	f.Add(1., 2., 20., -3.)
+	f.Fuzz(func(t *testing.T, s, l, m float64, exponent float64) {
+		a, ok := fuzzDecimal(s, l, m)
+		if !ok || a.sign <= 0 || math.IsNaN(exponent) || math.IsInf(exponent, 0) || math.Abs(exponent) < 1e-3 || math.Abs(exponent) > 1e3 {
+			return
+		}
+		n := D(exponent)
+		power := a.Pow(n)
+		if power.IsInf() || power.sign == 0 {
+			return
+		}
+		got := power.Root(n)
+		scale := a.AbsLog10().Abs().Multiply(D(math.Max(math.Abs(exponent), 1/math.Abs(exponent))))
+		if !logCloseTo(got, a, fuzzInverseTolerance, scale) {
+			t.Errorf("Root(Pow(%v, %v), %v) = %v", *a, exponent, exponent, *got)
+		}
+	})
+}
+
+func FuzzLog10PowBase10(f *testing.F) {
+	f.Add(1., 0., 3.)
+	f.Add(1., 1., -400.)
+	f.Add(1., 3., 20.)
+	f.Fuzz(func(t *testing.T, s, l, m float64) {
+		a, ok := fuzzDecimal(s, l, m)
+		if !ok || a.sign <= 0 {
+			return
+		}
+		got := a.Log10().PowBase10()
+		if !logCloseTo(got, a, fuzzInverseTolerance, a.AbsLog10()) {
+			t.Errorf("PowBase10(Log10(%v)) = %v", *a, *got)
+		}
+	})
+}
+
+func FuzzTetrateSlog(f *testing.F) {
+	f.Add(3.)
+	f.Add(0.5)
+	f.Add(-0.7)
+	f.Add(5.25)
+	f.Fuzz(func(t *testing.T, height float64) {
+		if math.IsNaN(height) || height <= -1 || height > 8 {
+			return
+		}
+		tower := D(10).Tetrate(height, dOne, false)
+		got := tower.Slog(D(10), DEFAULT_SLOG_ITERATIONS, false).ToFloat64()
+		if math.Abs(got-height) > fuzzSlogTolerance*math.Max(1, math.Abs(height)) {
+			t.Errorf("Slog(Tetrate(10, %v)) = %v", height, got)
+		}
+	})
+}
+
+func FuzzCmp(f *testing.F) {
+	f.Add(1., 0., 3., -1., 0., 5., 1., 1., 400.)
+	f.Add(1., 1., -400., 0., 0., 0., -1., 1., -400.)
+	f.Add(1., 2., 20., 1., 2., 20., 1., 1., 20.)
+	f.Fuzz(func(t *testing.T, s1, l1, m1, s2, l2, m2, s3, l3, m3 float64) {
+		a, ok1 := fuzzDecimal(s1, l1, m1)
+		b, ok2 := fuzzDecimal(s2, l2, m2)
+		c, ok3 := fuzzDecimal(s3, l3, m3)
+		if !ok1 || !ok2 || !ok3 {
+			return
+		}
+		if a.Cmp(b) != -b.Cmp(a) {
+			t.Errorf("Cmp(%v, %v) = %d but Cmp(%v, %v) = %d", *a, *b, a.Cmp(b), *b, *a, b.Cmp(a))
+		}
+		if (a.Cmp(b) == 0) != a.Eq(b) {
+			t.Errorf("Cmp(%v, %v) = %d but Eq is %v", *a, *b, a.Cmp(b), a.Eq(b))
+		}
+		if a.Lte(b) && b.Lte(c) && !a.Lte(c) {
+			t.Errorf("%v <= %v <= %v but not %v <= %v", *a, *b, *c, *a, *c)
+		}
+	})
+}
+
+func TestWithinTolerance(t *testing.T) {
+	cases := []struct {
+		err, scale string
+		want       bool
+	}{
+		{"1e-10", "1", true},
+		{"1e-5", "1", false},
+		{"1e390", "1e400", true},
+		{"1e395", "1e400", false},
+		{"ee20", "ee20", true},
+		{"ee20", "ee19", false},
+		{"eee20", "eee20", true},
+		{"eee20", "eee19", false},
+		{"0", "eeee5", true},
+	}
+	for _, c := range cases {
+		if got := withinTolerance(D(c.err), D(c.scale), fuzzInverseTolerance); got != c.want {
+			t.Errorf("withinTolerance(%s, %s) = %v, want %v", c.err, c.scale, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package breaketernity
+
+import (
+	"math"
+	"math/big"
+)
+
+// MAX_SAFE_INTEGER is the largest integer below which every integer is exactly representable by a float64 (2^53).
+const MAX_SAFE_INTEGER float64 = 9007199254740992
+
+// MAX_INTEGER_BITS is the largest size an Integer keeps exactly before falling back to a Decimal (about 1.8e308).
+const MAX_INTEGER_BITS = 1024
+
+// Integer is an integer that stays exact while it is small, and automatically falls back to the layered
+// Decimal representation when it outgrows MAX_INTEGER_BITS. Values that fit in an int64 take a fast path,
+// and are promoted to a big.Int on overflow. Inexact results that come back into the exactly representable
+// range (below 2^53) become exact again.
+// Useful for counts such as owned buildings, which are small integers most of the time but must not overflow.
+type Integer struct {
+	small int64
+	big   *big.Int // set when the value is exact but does not fit in an int64
+	d     *Decimal // set when the value is no longer exact
+}
+
+// I creates a new exact Integer.
+func I(value int64) *Integer {
+	return &Integer{small: value}
+}
+
+// IntegerFrom creates a new Integer from a given source, rounding it down to an integer.
+func IntegerFrom[DS DecimalSource](source DS) *Integer {
+	return integerFromDecimal(D(source).Floor())
+}
+
+func integerFromDecimal(d *Decimal) *Integer {
+	if math.Abs(d.ToFloat64()) < MAX_SAFE_INTEGER {
+		if i, ok := d.ToInt64(); ok {
+			return I(i)
+		}
+	}
+	return &Integer{d: d}
+}
+
+func integerFromBigInt(b *big.Int) *Integer {
+	if b.IsInt64() {
+		return I(b.Int64())
+	}
+	if b.BitLen() > MAX_INTEGER_BITS {
+		return &Integer{d: FromBigInt(b)}
+	}
+	return &Integer{big: b}
+}
+
+// IsExact returns true if the integer is held exactly, in an int64 or a big.Int.
+func (n *Integer) IsExact() bool {
+	return n.d == nil
+}
+
+// ToInt64 returns the integer as an int64. ok is false if it is not exact or does not fit in an int64.
+func (n *Integer) ToInt64() (int64, bool) {
+	if n.d == nil && n.big == nil {
+		return n.small, true
+	}
+	return 0, false
+}
+
+// ToBigInt returns the integer as a big.Int. ok is false if it is not exact.
+func (n *Integer) ToBigInt() (*big.Int, bool) {
+	if n.d != nil {
+		return nil, false
+	}
+	return n.bigInt(), true
+}
+
+func (n *Integer) bigInt() *big.Int {
+	if n.big != nil {
+		return new(big.Int).Set(n.big)
+	}
+	return big.NewInt(n.small)
+}
+
+// ToDecimal returns the integer as a Decimal.
+func (n *Integer) ToDecimal() *Decimal {
+	if n.d != nil {
+		return decimalFromDecimal(n.d)
+	}
+	if n.big != nil {
+		return FromBigInt(n.big)
+	}
+	return decimalFromFloat64(float64(n.small))
+}
+
+// ToString returns the integer as a string, with every digit while it is exact.
+func (n *Integer) ToString() string {
+	if n.d != nil {
+		return n.d.ToString()
+	}
+	return n.bigInt().String()
+}
+
+// Cmp returns 1 if n > other, -1 if n < other, and 0 if n == other.
+func (n *Integer) Cmp(other *Integer) int {
+	if n.isSmall() && other.isSmall() {
+		if n.small > other.small {
+			return 1
+		} else if n.small < other.small {
+			return -1
+		}
+		return 0
+	}
+	if n.IsExact() && other.IsExact() {
+		return n.bigInt().Cmp(other.bigInt())
+	}
+	return n.ToDecimal().Cmp(other.ToDecimal())
+}
+
+func (n *Integer) isSmall() bool {
+	return n.d == nil && n.big == nil
+}
+
+// Eq returns true if n == other.
+func (n *Integer) Eq(other *Integer) bool {
+	return n.Cmp(other) == 0
+}
+
+// Lt returns true if n < other.
+func (n *Integer) Lt(other *Integer) bool {
+	return n.Cmp(other) == -1
+}
+
+// Lte returns true if n <= other.
+func (n *Integer) Lte(other *Integer) bool {
+	return n.Cmp(other) != 1
+}
+
+// Gt returns true if n > other.
+func (n *Integer) Gt(other *Integer) bool {
+	return n.Cmp(other) == 1
+}
+
+// Gte returns true if n >= other.
+func (n *Integer) Gte(other *Integer) bool {
+	return n.Cmp(other) != -1
+}
+
+// Neg returns the negative of the integer
+func (n *Integer) Neg() *Integer {
+	if n.isSmall() && n.small != math.MinInt64 {
+		return I(-n.small)
+	}
+	if n.IsExact() {
+		return integerFromBigInt(n.bigInt().Neg(n.bigInt()))
+	}
+	result := decimalFromDecimal(n.d)
+	result.sign = -result.sign
+	return integerFromDecimal(result)
+}
+
+// Abs returns the absolute value of the integer
+func (n *Integer) Abs() *Integer {
+	if n.Lt(I(0)) {
+		return n.Neg()
+	}
+	return n.Floor()
+}
+
+// Floor returns the integer itself, since it is already rounded down.
+func (n *Integer) Floor() *Integer {
+	if n.d != nil {
+		return integerFromDecimal(n.d.Floor())
+	}
+	return &Integer{small: n.small, big: n.big}
+}
+
+// Add returns the sum of the integer and other
+func (n *Integer) Add(other *Integer) *Integer {
+	if n.isSmall() && other.isSmall() {
+		sum := n.small + other.small
+		// Overflow happened if both operands have the same sign and the sum has the other one
+		if (n.small >= 0) != (other.small >= 0) || (sum >= 0) == (n.small >= 0) {
+			return I(sum)
+		}
+	}
+	if n.IsExact() && other.IsExact() {
+		return integerFromBigInt(new(big.Int).Add(n.bigInt(), other.bigInt()))
+	}
+	return integerFromDecimal(n.ToDecimal().Add(other.ToDecimal()))
+}
+
+// Subtract returns the difference between the integer and other
+func (n *Integer) Subtract(other *Integer) *Integer {
+	return n.Add(other.Neg())
+}
+
+// Multiply returns the product of the integer and other
+func (n *Integer) Multiply(other *Integer) *Integer {
+	if n.isSmall() && other.isSmall() {
+		if product, ok := multiplyInt64(n.small, other.small); ok {
+			return I(product)
+		}
+	}
+	if n.IsExact() && other.IsExact() {
+		return integerFromBigInt(new(big.Int).Mul(n.bigInt(), other.bigInt()))
+	}
+	return integerFromDecimal(n.ToDecimal().Multiply(other.ToDecimal()))
+}
+
+// Pow returns the integer raised to the given power
+func (n *Integer) Pow(exponent uint64) *Integer {
+	if n.isSmall() {
+		result := int64(1)
+		base := n.small
+		ok := true
+		for e := exponent; e > 0 && ok; e >>= 1 {
+			if e&1 == 1 {
+				result, ok = multiplyInt64(result, base)
+			}
+			if e > 1 && ok {
+				base, ok = multiplyInt64(base, base)
+			}
+		}
+		if ok {
+			return I(result)
+		}
+	}
+	if n.IsExact() {
+		base := n.bigInt()
+		// Only materialize results that will stay exact
+		if float64(base.BitLen()-1)*float64(exponent) <= MAX_INTEGER_BITS {
+			return integerFromBigInt(new(big.Int).Exp(base, new(big.Int).SetUint64(exponent), nil))
+		}
+	}
+	return integerFromDecimal(n.ToDecimal().Pow(decimalFromFloat64(float64(exponent))))
+}
+
+func multiplyInt64(a int64, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	return product, true
+}
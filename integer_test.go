@@ -0,0 +1,125 @@
+package breaketernity
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func bigPow(base int64, exponent int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(base), big.NewInt(exponent), nil)
+}
+
+func TestIntegerPromotesOnOverflow(t *testing.T) {
+	maxPlusOne := new(big.Int).Add(big.NewInt(math.MaxInt64), big.NewInt(1))
+	cases := []struct {
+		name string
+		got  *Integer
+		want *big.Int
+	}{
+		{"MaxInt64 + 1", I(math.MaxInt64).Add(I(1)), maxPlusOne},
+		{"MinInt64 - 1", I(math.MinInt64).Subtract(I(1)), new(big.Int).Sub(big.NewInt(math.MinInt64), big.NewInt(1))},
+		{"-MinInt64", I(math.MinInt64).Neg(), maxPlusOne},
+		{"|MinInt64|", I(math.MinInt64).Abs(), maxPlusOne},
+		{"MinInt64 * -1", I(math.MinInt64).Multiply(I(-1)), maxPlusOne},
+		{"3037000500^2", I(3037000500).Multiply(I(3037000500)), new(big.Int).Mul(big.NewInt(3037000500), big.NewInt(3037000500))},
+		{"3^40", I(3).Pow(40), bigPow(3, 40)},
+		{"-7^301", I(-7).Pow(301), bigPow(-7, 301)},
+		{"2^1023", I(2).Pow(1023), bigPow(2, 1023)},
+		{"2^1023 * 1", I(2).Pow(1023).Multiply(I(1)), bigPow(2, 1023)},
+	}
+	for _, c := range cases {
+		got, ok := c.got.ToBigInt()
+		if !ok || got.Cmp(c.want) != 0 {
+			t.Errorf("%s = %s, exact %v, want %s", c.name, c.got.ToString(), ok, c.want.String())
+		}
+		if c.got.ToString() != c.want.String() {
+			t.Errorf("%s prints as %s, want %s", c.name, c.got.ToString(), c.want.String())
+		}
+		if _, ok := c.got.ToInt64(); ok {
+			t.Errorf("%s fits in an int64", c.name)
+		}
+	}
+}
+
+func TestIntegerDemotesBackToInt64(t *testing.T) {
+	big := I(math.MaxInt64).Add(I(10))
+	if got, ok := big.Subtract(I(10)).ToInt64(); !ok || got != math.MaxInt64 {
+		t.Errorf("MaxInt64 + 10 - 10 = %d, %v, want MaxInt64", got, ok)
+	}
+	if got, ok := I(3).Pow(40).Subtract(I(3).Pow(40)).ToInt64(); !ok || got != 0 {
+		t.Errorf("3^40 - 3^40 = %d, %v, want 0", got, ok)
+	}
+}
+
+func TestIntegerFallsBackToDecimal(t *testing.T) {
+	cases := []struct {
+		name string
+		got  *Integer
+		want *Decimal
+	}{
+		{"2^1024", I(2).Pow(1024), D(2).Pow(D(1024))},
+		{"2^1023 * 4", I(2).Pow(1023).Multiply(I(4)), D(2).Pow(D(1025))},
+		{"10^400", I(10).Pow(400), D("1e400")},
+		{"-(10^400)", I(10).Pow(400).Neg(), D("-1e400")},
+		{"10^400 + 1", I(10).Pow(400).Add(I(1)), D("1e400")},
+		{"floor(1e400)", IntegerFrom("1e400"), D("1e400")},
+	}
+	for _, c := range cases {
+		if c.got.IsExact() {
+			t.Errorf("%s = %s is still exact", c.name, c.got.ToString())
+		}
+		if got := c.got.ToDecimal(); !got.EqTolerance(c.want, 1e-12) {
+			t.Errorf("%s = %s, want %s", c.name, got.ToString(), c.want.ToString())
+		}
+	}
+	// Results that come back below 2^53 are exact again
+	huge := IntegerFrom("1e400")
+	if got, ok := huge.Add(I(7)).Subtract(huge).ToInt64(); !ok || got != 0 {
+		t.Errorf("1e400 + 7 - 1e400 = %d, %v, want an exact 0", got, ok)
+	}
+}
+
+func TestIntegerFrom(t *testing.T) {
+	cases := []struct {
+		source float64
+		want   int64
+	}{
+		{2.7, 2},
+		{-2.5, -3},
+		{0, 0},
+		{1e15, 1e15},
+	}
+	for _, c := range cases {
+		if got, ok := IntegerFrom(c.source).ToInt64(); !ok || got != c.want {
+			t.Errorf("IntegerFrom(%g) = %d, %v, want %d", c.source, got, ok, c.want)
+		}
+	}
+}
+
+func TestIntegerCmpAcrossRepresentations(t *testing.T) {
+	// Small, big.Int and Decimal integers, in increasing order
+	ordered := []*Integer{
+		I(10).Pow(400).Neg(),
+		I(math.MinInt64).Subtract(I(1)),
+		I(-5),
+		I(0),
+		I(math.MaxInt64),
+		I(math.MaxInt64).Add(I(1)),
+		I(2).Pow(1023),
+		I(10).Pow(400),
+	}
+	for i, a := range ordered {
+		for j, b := range ordered {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := a.Cmp(b); got != want {
+				t.Errorf("Cmp(%s, %s) = %d, want %d", a.ToString(), b.ToString(), got, want)
+			}
+		}
+	}
+}
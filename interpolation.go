@@ -0,0 +1,196 @@
+package breaketernity
+
+import "math"
+
+// Space is the space in which values are interpolated.
+type Space int
+
+const (
+	// SPACE_AUTO picks one of the other spaces from the bounds, see AutoSpace
+	SPACE_AUTO Space = iota
+	// SPACE_LINEAR interpolates the values themselves
+	SPACE_LINEAR
+	// SPACE_LOG interpolates the base10 logarithms of the values
+	SPACE_LOG
+	// SPACE_SLOG interpolates the base10 super-logarithms of the values
+	SPACE_SLOG
+)
+
+// AutoSpace returns the space in which to interpolate between a and b so that progress looks steady,
+// from how many layers of exponents apart they are:
+// SPACE_LINEAR if they are within a factor of 100 of each other (or either is not positive),
+// SPACE_LOG if their numbers of digits are within a factor of 1000 of each other, and SPACE_SLOG otherwise.
+func AutoSpace[DS DecimalSource](a DS, b DS) Space {
+	return autoSpace(D(a), D(b))
+}
+
+func autoSpace(a *Decimal, b *Decimal) Space {
+	if a.sign <= 0 || b.sign <= 0 {
+		return SPACE_LINEAR
+	}
+	if a.Gt(b) {
+		a, b = b, a
+	}
+	la, lb := a.Log10(), b.Log10()
+	if lb.Subtract(la).Lte(D(2)) {
+		return SPACE_LINEAR
+	}
+	// Compare the numbers of digits, counting values below 10 as having 1
+	if la.Lt(dOne) {
+		la = dOne
+	}
+	if lb.Lt(dOne) {
+		lb = dOne
+	}
+	if lb.Log10().Subtract(la.Log10()).Lte(D(3)) {
+		return SPACE_LOG
+	}
+	return SPACE_SLOG
+}
+
+// Lerp returns the value a fraction t of the way from a to b, in linear space
+func Lerp[DS DecimalSource](a DS, b DS, t float64) *Decimal {
+	return lerpIn(SPACE_LINEAR, D(a), D(b), t)
+}
+
+// LogLerp returns the value a fraction t of the way from a to b, interpolating their base10 logarithms.
+// Both must be positive, otherwise the result is NaN.
+func LogLerp[DS DecimalSource](a DS, b DS, t float64) *Decimal {
+	return lerpIn(SPACE_LOG, D(a), D(b), t)
+}
+
+// SlogLerp returns the value a fraction t of the way from a to b, interpolating their base10 super-logarithms.
+// Both must be positive, otherwise the result is NaN.
+func SlogLerp[DS DecimalSource](a DS, b DS, t float64) *Decimal {
+	return lerpIn(SPACE_SLOG, D(a), D(b), t)
+}
+
+// LerpIn returns the value a fraction t of the way from a to b in the given space
+func LerpIn[DS DecimalSource](space Space, a DS, b DS, t float64) *Decimal {
+	return lerpIn(space, D(a), D(b), t)
+}
+
+func lerpIn(space Space, a *Decimal, b *Decimal, t float64) *Decimal {
+	return newLerper(space, a, b).at(t)
+}
+
+// lerper interpolates between two fixed bounds, converting them to the interpolation space only once.
+type lerper struct {
+	space   Space
+	a       *Decimal // bounds in the interpolation space, for SPACE_LINEAR and SPACE_LOG
+	b       *Decimal
+	slogA   float64 // bounds in the interpolation space, for SPACE_SLOG
+	slogB   float64
+	invalid bool
+}
+
+func newLerper(space Space, a *Decimal, b *Decimal) *lerper {
+	if space == SPACE_AUTO {
+		space = autoSpace(a, b)
+	}
+	l := &lerper{space: space, a: a, b: b}
+	if space != SPACE_LINEAR && (a.sign <= 0 || b.sign <= 0) {
+		l.invalid = true
+	} else if space == SPACE_LOG {
+		l.a, l.b = a.Log10(), b.Log10()
+	} else if space == SPACE_SLOG {
+		base := D(10)
+		l.slogA = a.Slog(base, DEFAULT_SLOG_ITERATIONS, false).ToFloat64()
+		l.slogB = b.Slog(base, DEFAULT_SLOG_ITERATIONS, false).ToFloat64()
+	}
+	return l
+}
+
+// at returns the value a fraction t of the way between the bounds
+func (l *lerper) at(t float64) *Decimal {
+	if l.invalid {
+		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	}
+	switch l.space {
+	case SPACE_LOG:
+		return lerpLinear(l.a, l.b, t).PowBase10()
+	case SPACE_SLOG:
+		return D(10).Tetrate(l.slogA+(l.slogB-l.slogA)*t, dOne, false)
+	default:
+		return lerpLinear(l.a, l.b, t)
+	}
+}
+
+// fraction returns the fraction of the way x is between the bounds, the inverse of at
+func (l *lerper) fraction(x *Decimal) float64 {
+	if l.invalid || (l.space != SPACE_LINEAR && x.sign <= 0) {
+		return math.NaN()
+	}
+	switch l.space {
+	case SPACE_LOG:
+		x = x.Log10()
+	case SPACE_SLOG:
+		if l.slogA == l.slogB {
+			return math.NaN()
+		}
+		slogX := x.Slog(D(10), DEFAULT_SLOG_ITERATIONS, false).ToFloat64()
+		return (slogX - l.slogA) / (l.slogB - l.slogA)
+	}
+	if l.a.Eq(l.b) {
+		return math.NaN()
+	}
+	return x.Subtract(l.a).Divide(l.b.Subtract(l.a)).ToFloat64()
+}
+
+func lerpLinear(a *Decimal, b *Decimal, t float64) *Decimal {
+	if a.layer == 0 && b.layer == 0 {
+		fa, fb := a.sign*a.mag, b.sign*b.mag
+		return decimalFromFloat64(fa + (fb-fa)*t)
+	}
+	return a.Add(b.Subtract(a).Multiply(decimalFromFloat64(t)))
+}
+
+// InverseLerp returns the fraction of the way x is from a to b in linear space, the inverse of Lerp.
+// The result is not clamped, and is NaN if a == b.
+func InverseLerp[DS DecimalSource](a DS, b DS, x DS) float64 {
+	return inverseLerpIn(SPACE_LINEAR, D(a), D(b), D(x))
+}
+
+// InverseLogLerp returns the fraction of the way x is from a to b when interpolating their base10 logarithms, the inverse of LogLerp.
+// The result is not clamped, and is NaN if a == b or if any of the values is not positive.
+func InverseLogLerp[DS DecimalSource](a DS, b DS, x DS) float64 {
+	return inverseLerpIn(SPACE_LOG, D(a), D(b), D(x))
+}
+
+// InverseSlogLerp returns the fraction of the way x is from a to b when interpolating their base10 super-logarithms, the inverse of SlogLerp.
+// The result is not clamped, and is NaN if a and b have the same super-logarithm or if any of the values is not positive.
+func InverseSlogLerp[DS DecimalSource](a DS, b DS, x DS) float64 {
+	return inverseLerpIn(SPACE_SLOG, D(a), D(b), D(x))
+}
+
+// InverseLerpIn returns the fraction of the way x is from a to b in the given space, the inverse of LerpIn
+func InverseLerpIn[DS DecimalSource](space Space, a DS, b DS, x DS) float64 {
+	return inverseLerpIn(space, D(a), D(b), D(x))
+}
+
+func inverseLerpIn(space Space, a *Decimal, b *Decimal, x *Decimal) float64 {
+	return newLerper(space, a, b).fraction(x)
+}
+
+// Progress returns how far current is from start to goal as a fraction between 0 and 1,
+// in the space AutoSpace picks for start and goal. It is 1 once current reaches the goal.
+func Progress[DS DecimalSource](current DS, start DS, goal DS) float64 {
+	dc, ds, dg := D(current), D(start), D(goal)
+	if dc.IsNaN() || ds.IsNaN() || dg.IsNaN() {
+		return math.NaN()
+	}
+	if ds.Eq(dg) {
+		if dc.Gte(dg) {
+			return 1
+		}
+		return 0
+	}
+	if dc.sign <= 0 && autoSpace(ds, dg) != SPACE_LINEAR {
+		return 0
+	}
+	p := inverseLerpIn(SPACE_AUTO, ds, dg, dc)
+	if math.IsNaN(p) {
+		return 0
+	}
+	return math.Min(math.Max(p, 0), 1)
+}
@@ -0,0 +1,17 @@
+package breaketernity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInverseSlogLerpEqualBounds(t *testing.T) {
+	for _, c := range [][3]string{{"1e100", "1e100", "1e200"}, {"ee20", "ee20", "ee20"}, {"5", "5", "1"}} {
+		if got := InverseSlogLerp(c[0], c[1], c[2]); !math.IsNaN(got) {
+			t.Errorf("InverseSlogLerp(%s, %s, %s) = %v, want NaN", c[0], c[1], c[2], got)
+		}
+	}
+	if got := InverseSlogLerp("10", "1e10", "1e10"); math.Abs(got-1) > 1e-9 {
+		t.Errorf("InverseSlogLerp(10, 1e10, 1e10) = %v, want 1", got)
+	}
+}
@@ -0,0 +1,255 @@
+package breaketernity
+
+import "math"
+
+// INTERVAL_ULPS is how many units in the last place of mag every computed bound is widened by,
+// to cover the rounding error of the float64 operations that produced it.
+const INTERVAL_ULPS float64 = 16
+
+// Interval is a closed range of Decimals [lo, hi] that is guaranteed to contain the exact result of the operations
+// that produced it. Every operation computes its bounds from the bounds of its operands,
+// then rounds them outward on mag, so precision lost deep in layer 1 and above shows up as a wider interval.
+// Two values that may be equal once rounding error is accounted for have overlapping intervals.
+type Interval struct {
+	lo Decimal
+	hi Decimal
+}
+
+// NewInterval creates a new Interval between lo and hi, in either order.
+func NewInterval[DS DecimalSource](lo DS, hi DS) *Interval {
+	return intervalFromBounds(D(lo), D(hi))
+}
+
+// IntervalOf creates a new Interval containing only the given value, which is taken to be exact.
+func IntervalOf[DS DecimalSource](value DS) *Interval {
+	d := D(value)
+	return &Interval{lo: *d, hi: *d}
+}
+
+func intervalFromBounds(lo *Decimal, hi *Decimal) *Interval {
+	if lo.IsNaN() || hi.IsNaN() {
+		return intervalNaN()
+	}
+	if lo.Gt(hi) {
+		lo, hi = hi, lo
+	}
+	return &Interval{lo: *lo, hi: *hi}
+}
+
+// intervalFromResults returns the smallest Interval containing all results, rounded outward.
+func intervalFromResults(results ...*Decimal) *Interval {
+	lo, hi := results[0], results[0]
+	for _, r := range results {
+		if r.IsNaN() {
+			return intervalNaN()
+		}
+		if r.Lt(lo) {
+			lo = r
+		}
+		if r.Gt(hi) {
+			hi = r
+		}
+	}
+	return &Interval{lo: *roundDown(lo), hi: *roundUp(hi)}
+}
+
+func intervalWholeLine() *Interval {
+	return &Interval{lo: *dFC_NN(-1, math.Inf(1), math.Inf(1)), hi: *dFC_NN(1, math.Inf(1), math.Inf(1))}
+}
+
+func intervalNaN() *Interval {
+	nan := dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	return &Interval{lo: *nan, hi: *nan}
+}
+
+// roundUp returns the next value above d that is INTERVAL_ULPS away on mag.
+func roundUp(d *Decimal) *Decimal {
+	return widenAbs(d, d.sign > 0)
+}
+
+// roundDown returns the next value below d that is INTERVAL_ULPS away on mag.
+func roundDown(d *Decimal) *Decimal {
+	return widenAbs(d, d.sign < 0)
+}
+
+// widenAbs moves d away from zero if outward is true, towards it otherwise.
+// Zero is left alone: a result of exactly zero only comes from exact cancellation or multiplication by zero.
+// The absolute value of a Decimal grows with mag on every layer, even when mag is negative.
+func widenAbs(d *Decimal, outward bool) *Decimal {
+	if d.sign == 0 || d.IsNaN() || d.IsInf() {
+		return decimalFromDecimal(d)
+	}
+	delta := math.Max(math.Abs(d.mag)*INTERVAL_ULPS*0x1p-52, math.SmallestNonzeroFloat64)
+	if outward {
+		return dFC(d.sign, d.layer, d.mag+delta)
+	}
+	mag := d.mag - delta
+	if d.layer == 0 && mag < 0 {
+		mag = 0
+	}
+	return dFC(d.sign, d.layer, mag)
+}
+
+// sumError bounds the rounding error of a + b. When the operands nearly cancel, the error is relative to the larger
+// operand rather than to the result. On layer 1 and above, a unit in the last place of mag is a relative error of
+// ln(10) * mag units in the last place of the value.
+func sumError(a *Decimal, b *Decimal) *Decimal {
+	larger := a.MaxAbs(b).Abs()
+	ulps := INTERVAL_ULPS * 0x1p-52
+	if larger.layer > 0 {
+		ulps *= math.Ln10 * math.Max(1, math.Abs(larger.mag))
+	}
+	return larger.Multiply(decimalFromFloat64(ulps))
+}
+
+// Lo returns the lower bound of the interval
+func (iv *Interval) Lo() *Decimal {
+	return decimalFromDecimal(&iv.lo)
+}
+
+// Hi returns the upper bound of the interval
+func (iv *Interval) Hi() *Decimal {
+	return decimalFromDecimal(&iv.hi)
+}
+
+// IsNaN returns true if either bound of the interval is NaN
+func (iv *Interval) IsNaN() bool {
+	return iv.lo.IsNaN() || iv.hi.IsNaN()
+}
+
+// Contains returns true if value lies within the interval, bounds included
+func (iv *Interval) Contains(value *Decimal) bool {
+	if iv.IsNaN() || value.IsNaN() {
+		return false
+	}
+	return iv.lo.Lte(value) && value.Lte(&iv.hi)
+}
+
+// Overlaps returns true if the two intervals have at least one value in common,
+// meaning the values they enclose could be equal
+func (iv *Interval) Overlaps(other *Interval) bool {
+	if iv.IsNaN() || other.IsNaN() {
+		return false
+	}
+	return iv.lo.Lte(&other.hi) && other.lo.Lte(&iv.hi)
+}
+
+// ToString returns the interval as a string in the form [lo, hi]
+func (iv *Interval) ToString() string {
+	return "[" + iv.lo.ToString() + ", " + iv.hi.ToString() + "]"
+}
+
+// Neg returns the negative of the interval
+func (iv *Interval) Neg() *Interval {
+	lo, hi := iv.hi, iv.lo
+	lo.sign, hi.sign = -lo.sign, -hi.sign
+	return &Interval{lo: lo, hi: hi}
+}
+
+// Add returns an interval containing every sum of a value in iv and a value in other.
+// The bounds are widened by the rounding error of the larger operands, which dominates when they nearly cancel.
+func (iv *Interval) Add(other *Interval) *Interval {
+	if iv.IsNaN() || other.IsNaN() {
+		return intervalNaN()
+	}
+	lo := iv.lo.Add(&other.lo).Subtract(sumError(&iv.lo, &other.lo))
+	hi := iv.hi.Add(&other.hi).Add(sumError(&iv.hi, &other.hi))
+	return &Interval{lo: *roundDown(lo), hi: *roundUp(hi)}
+}
+
+// Subtract returns an interval containing every difference of a value in iv and a value in other
+func (iv *Interval) Subtract(other *Interval) *Interval {
+	return iv.Add(other.Neg())
+}
+
+// Multiply returns an interval containing every product of a value in iv and a value in other
+func (iv *Interval) Multiply(other *Interval) *Interval {
+	if iv.IsNaN() || other.IsNaN() {
+		return intervalNaN()
+	}
+	return intervalFromResults(
+		iv.lo.Multiply(&other.lo),
+		iv.lo.Multiply(&other.hi),
+		iv.hi.Multiply(&other.lo),
+		iv.hi.Multiply(&other.hi),
+	)
+}
+
+// Recip returns an interval containing the reciprocal of every value in iv.
+// If iv contains zero, the result is the whole line from negative to positive infinity.
+func (iv *Interval) Recip() *Interval {
+	if iv.IsNaN() {
+		return intervalNaN()
+	}
+	if iv.Contains(dZero) {
+		return intervalWholeLine()
+	}
+	return intervalFromResults(iv.lo.Recip(), iv.hi.Recip())
+}
+
+// Divide returns an interval containing every quotient of a value in iv and a value in other.
+// If other contains zero, the result is the whole line from negative to positive infinity.
+func (iv *Interval) Divide(other *Interval) *Interval {
+	if iv.IsNaN() || other.IsNaN() {
+		return intervalNaN()
+	}
+	// Multiplying by the whole line would give 0 * Infinity = NaN when iv contains zero too
+	if other.Contains(dZero) {
+		return intervalWholeLine()
+	}
+	return iv.Multiply(other.Recip())
+}
+
+// Pow returns an interval containing every value in iv raised to the power of every value in other.
+// Every value in iv must be positive, otherwise the result is NaN.
+func (iv *Interval) Pow(other *Interval) *Interval {
+	if iv.IsNaN() || other.IsNaN() || iv.lo.sign <= 0 {
+		return intervalNaN()
+	}
+	// x^y is monotonic in x and in y for x > 0, so the extremes are at the corners
+	return intervalFromResults(
+		iv.lo.Pow(&other.lo),
+		iv.lo.Pow(&other.hi),
+		iv.hi.Pow(&other.lo),
+		iv.hi.Pow(&other.hi),
+	)
+}
+
+// Log returns an interval containing the logarithm in the given base of every value in iv.
+// Every value in iv must be positive, otherwise the result is NaN.
+func (iv *Interval) Log(base *Decimal) *Interval {
+	if iv.IsNaN() || iv.lo.sign <= 0 {
+		return intervalNaN()
+	}
+	return intervalFromResults(iv.lo.Log(base), iv.hi.Log(base))
+}
+
+// Log10 returns an interval containing the base10 logarithm of every value in iv.
+// Every value in iv must be positive, otherwise the result is NaN.
+func (iv *Interval) Log10() *Interval {
+	if iv.IsNaN() || iv.lo.sign <= 0 {
+		return intervalNaN()
+	}
+	return intervalFromResults(iv.lo.Log10(), iv.hi.Log10())
+}
+
+// Tetrate returns an interval containing every value in iv tetrated to the given height with the given payload.
+// Tetration is only monotonic in the base for bases >= 1, so the result is NaN if iv.Lo() < 1.
+func (iv *Interval) Tetrate(height float64, payload *Decimal, linear bool) *Interval {
+	if iv.IsNaN() || iv.lo.Lt(dOne) {
+		return intervalNaN()
+	}
+	return intervalFromResults(iv.lo.Tetrate(height, payload, linear), iv.hi.Tetrate(height, payload, linear))
+}
+
+// Slog returns an interval containing the super-logarithm in the given base of every value in iv.
+// The bounds are widened by how far Slog's search can be from the answer after the given number of iterations.
+// If the search hasn't closed in on it yet, that bound is infinite.
+func (iv *Interval) Slog(base *Decimal, iterations float64, linear bool) *Interval {
+	if iv.IsNaN() {
+		return intervalNaN()
+	}
+	lo, loError := iv.lo.slogSearch(base, iterations, linear)
+	hi, hiError := iv.hi.slogSearch(base, iterations, linear)
+	return intervalFromResults(decimalFromFloat64(lo-loError), decimalFromFloat64(hi+hiError))
+}
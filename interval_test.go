@@ -0,0 +1,93 @@
+package breaketernity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntervalAddCancellation(t *testing.T) {
+	cases := [][2]string{
+		{"1e20", "-9.9999999999e19"},
+		{"1e30", "-9.99999999999e29"},
+		{"3e40", "-2.9999999e40"},
+		{"-1e20", "9.9999999999e19"},
+		{"1e400", "-9.999999e399"},
+		{"123.456", "-123.455"},
+	}
+	for _, c := range cases {
+		a, b := D(c[0]), D(c[1])
+		// Decimal128 sums the values a and b actually hold with about twice the precision
+		exact := D128(a).Add(D128(b)).ToDecimal()
+		sum := IntervalOf(a).Add(IntervalOf(b))
+		if !sum.Contains(exact) {
+			t.Errorf("%s + %s = %s, which does not contain %s", c[0], c[1], sum.ToString(), exact.ToString())
+		}
+		difference := IntervalOf(a).Subtract(IntervalOf(b.Neg()))
+		if !difference.Contains(exact) {
+			t.Errorf("%s - %s = %s, which does not contain %s", c[0], b.Neg().ToString(), difference.ToString(), exact.ToString())
+		}
+	}
+}
+
+func TestIntervalDivide(t *testing.T) {
+	cases := []struct {
+		a, b   *Interval
+		lo, hi *Decimal
+	}{
+		{NewInterval(2, 4), NewInterval(1, 2), D(1), D(4)},
+		{NewInterval(-4, -2), NewInterval(1, 2), D(-4), D(-1)},
+		{NewInterval(-1, 1), NewInterval(-1, 1), D(math.Inf(-1)), D(math.Inf(1))},
+		{NewInterval(1, 2), NewInterval(0, 1), D(math.Inf(-1)), D(math.Inf(1))},
+		{IntervalOf(0), IntervalOf(0), D(math.Inf(-1)), D(math.Inf(1))},
+	}
+	for _, c := range cases {
+		got := c.a.Divide(c.b)
+		if got.IsNaN() || !got.Contains(c.lo) || !got.Contains(c.hi) {
+			t.Errorf("%s / %s = %s, want at least [%s, %s]", c.a.ToString(), c.b.ToString(), got.ToString(), c.lo.ToString(), c.hi.ToString())
+		}
+	}
+}
+
+func TestIntervalMultiplyAndPow(t *testing.T) {
+	product := NewInterval(-2, 3).Multiply(NewInterval(-5, 4))
+	if !product.Contains(D(-15)) || !product.Contains(D(12)) || product.Contains(D(-16)) || product.Contains(D(13)) {
+		t.Errorf("[-2, 3] * [-5, 4] = %s, want about [-15, 12]", product.ToString())
+	}
+	power := NewInterval(2, 3).Pow(NewInterval(-1, 2))
+	if !power.Contains(D(1.0/3)) || !power.Contains(D(9)) || power.Contains(D(0.3)) || power.Contains(D(9.1)) {
+		t.Errorf("[2, 3]^[-1, 2] = %s, want about [1/3, 9]", power.ToString())
+	}
+	if got := NewInterval(-1, 2).Pow(IntervalOf(2)); !got.IsNaN() {
+		t.Errorf("[-1, 2]^2 = %s, want NaN", got.ToString())
+	}
+	logarithm := NewInterval("10", "1e400").Log10()
+	if !logarithm.Contains(D(1)) || !logarithm.Contains(D(400)) || logarithm.Contains(D(401)) {
+		t.Errorf("log10([10, 1e400]) = %s, want about [1, 400]", logarithm.ToString())
+	}
+}
+
+func TestIntervalTetrate(t *testing.T) {
+	got := NewInterval(2, 3).Tetrate(2, D(1), false)
+	if !got.Contains(D(4)) || !got.Contains(D(27)) || got.Contains(D(28)) {
+		t.Errorf("[2, 3]^^2 = %s, want about [4, 27]", got.ToString())
+	}
+	// x^^2 = x^x has its minimum of 0.692... at 1/e, below both 0.25^^2 and 0.9^^2
+	if got := NewInterval(0.25, 0.9).Tetrate(2, D(1), false); !got.IsNaN() {
+		t.Errorf("[0.25, 0.9]^^2 = %s, want NaN", got.ToString())
+	}
+}
+
+func TestIntervalSlogCoversTheSearch(t *testing.T) {
+	for _, x := range []string{"5", "1e10", "1e1000", "ee1e100"} {
+		exact := D(x).Slog(D(10), 100, false)
+		for _, iterations := range []float64{5, 10, 20, 100} {
+			got := IntervalOf(x).Slog(D(10), iterations, false)
+			if !got.Contains(exact) {
+				t.Errorf("slog(%s) with %v iterations = %s, which does not contain %s", x, iterations, got.ToString(), exact.ToString())
+			}
+		}
+	}
+	if got := IntervalOf("1e10").Slog(D(10), 100, false); got.Hi().Subtract(got.Lo()).Gt(D(1e-9)) {
+		t.Errorf("slog(1e10) with 100 iterations = %s, want a narrow interval", got.ToString())
+	}
+}
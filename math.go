@@ -128,9 +128,9 @@ func Max[DS DecimalSource](d DS, other DS) *Decimal {
 // Max returns the maximum of two Decimal values.
 func (d *Decimal) Max(other *Decimal) *Decimal {
 	if d.Lt(other) {
-		return d
+		return D(other)
 	} else {
-		return other
+		return D(d)
 	}
 }
 
@@ -142,9 +142,9 @@ func Min[DS DecimalSource](d DS, other DS) *Decimal {
 // Min returns the minimum of two Decimal values.
 func (d *Decimal) Min(other *Decimal) *Decimal {
 	if d.Gt(other) {
-		return d
+		return D(other)
 	} else {
-		return other
+		return D(d)
 	}
 }
 
@@ -156,9 +156,9 @@ func MaxAbs[DS DecimalSource](d DS, other DS) *Decimal {
 // MaxAbs returns the Decimal with the maximum absolute value between d and other.
 func (d *Decimal) MaxAbs(other *Decimal) *Decimal {
 	if d.CmpAbs(other) < 0 {
-		return d
+		return D(other)
 	} else {
-		return other
+		return D(d)
 	}
 }
 
@@ -170,9 +170,9 @@ func MinAbs[DS DecimalSource](d DS, other DS) *Decimal {
 // MinAbs returns the Decimal with the minimum absolute value between d and other.
 func (d *Decimal) MinAbs(other *Decimal) *Decimal {
 	if d.CmpAbs(other) > 0 {
-		return d
+		return D(other)
 	} else {
-		return other
+		return D(d)
 	}
 }
 
@@ -492,7 +492,7 @@ func (d *Decimal) PowBase10() *Decimal {
 		return dFC_NN(0, 0, 0)
 	}
 	// Handle non-finite layer or magnitude
-	if math.IsInf(d.layer, 0) || math.IsInf(d.mag, 0) {
+	if !isFinite(d.layer) || !isFinite(d.mag) {
 		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
 	}
 
@@ -506,7 +506,7 @@ func (d *Decimal) PowBase10() *Decimal {
 			if a.sign == 0 {
 				return dFC_NN(1, 0, 1)
 			} else {
-				return dFC_NN(a.sign, a.layer+1, a.mag)
+				a = dFC_NN(a.sign, a.layer+1, math.Log10(a.mag))
 			}
 		}
 	}
@@ -550,7 +550,7 @@ func PowBaseN[DS DecimalSource](d DS, base DS) *Decimal {
 
 // PowBaseN returns the base raised to the power of the decimal
 func (d *Decimal) PowBaseN(base *Decimal) *Decimal {
-	return d.Pow(base)
+	return base.Pow(d)
 }
 
 // Root returns the "degree"th root of the decimal
@@ -596,9 +596,9 @@ func (d *Decimal) Factorial() *Decimal {
 	} else if d.layer == 0 {
 		return d.Add(D(1)).Gamma()
 	} else if d.layer == 1 {
-		return d.Multiply(d.Ln().Subtract(D(1))).PowBase10()
+		return d.Multiply(d.Ln().Subtract(D(1))).PowBaseE()
 	} else {
-		return d.PowBase10()
+		return d.PowBaseE()
 	}
 }
 
@@ -681,7 +681,7 @@ func Neg[DS DecimalSource](d DS) *Decimal {
 
 // Neg returns the negative of the decimal
 func (d *Decimal) Neg() *Decimal {
-	return dFC_NN(sign(-1*d.mag), d.layer, d.mag)
+	return dFC_NN(-d.sign, d.layer, d.mag)
 }
 
 // Round rounds the decimal to the nearest integer
@@ -731,16 +731,17 @@ func Ceil[DS DecimalSource](d DS) *Decimal {
 // Ceil rounds the decimal up to the nearest integer
 func (d *Decimal) Ceil() *Decimal {
 	if d.mag < 0 {
-		if d.sign == -1 {
+		// Something tiny like 10^10^-100 is still greater than 0
+		if d.sign == 1 {
 			return dFC_NN(1, 0, 1)
 		} else {
 			return dFC_NN(0, 0, 0)
 		}
 	}
-	if d.sign == 1 {
+	if d.sign == -1 {
 		return d.Neg().Floor().Neg()
 	}
-	if d.sign == 0 {
+	if d.layer == 0 {
 		return dFC(d.sign, 0, math.Ceil(d.mag))
 	}
 	return D(d)
@@ -789,14 +790,14 @@ func Add[DS DecimalSource](d DS, other DS) *Decimal {
 
 // Add returns the sum of the decimal and other
 func (d *Decimal) Add(other *Decimal) *Decimal {
-	if (d.Eq(dInf) && other.Eq(dInf)) || (d.Eq(dNegInf) && other.Eq(dNegInf)) {
+	if (d.Eq(dInf) && other.Eq(dNegInf)) || (d.Eq(dNegInf) && other.Eq(dInf)) {
 		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
 	}
 
-	if math.IsInf(d.layer, 0) {
+	if !isFinite(d.layer) {
 		return D(d)
 	}
-	if math.IsInf(other.layer, 0) {
+	if !isFinite(other.layer) {
 		return D(other)
 	}
 
@@ -807,7 +808,7 @@ func (d *Decimal) Add(other *Decimal) *Decimal {
 		return D(d)
 	}
 
-	if d.sign == -other.sign && d.layer == other.layer && d.mag == d.mag {
+	if d.sign == -other.sign && d.layer == other.layer && d.mag == other.mag {
 		return dFC_NN(0, 0, 0)
 	}
 
@@ -892,10 +893,10 @@ func (d *Decimal) Multiply(other *Decimal) *Decimal {
 		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
 	}
 
-	if math.IsInf(d.layer, 0) {
+	if !isFinite(d.layer) {
 		return D(d)
 	}
-	if math.IsInf(other.layer, 0) {
+	if !isFinite(other.layer) {
 		return D(other)
 	}
 
@@ -967,6 +968,7 @@ func Modulo[DS DecimalSource](d DS, other DS) *Decimal {
 // Modulo returns the remainder of d divided by other
 // Uses the truncated division modulo, which is the same as Go's native modulo operator (%)
 func (d *Decimal) Modulo(other *Decimal) *Decimal {
+	other = other.Abs()
 	if other.Eq(dZero) {
 		return dFC_NN(0, 0, 0)
 	}
@@ -987,10 +989,10 @@ func (d *Decimal) Modulo(other *Decimal) *Decimal {
 	}
 
 	if d.sign == -1 {
-		return d.Abs().Modulo(d).Neg()
+		return d.Abs().Modulo(other).Neg()
 	}
 
-	return d.Subtract(d.Divide(d).Floor().Multiply(other))
+	return d.Subtract(d.Divide(other).Floor().Multiply(other))
 }
 
 // IsNan returns true if the decimal is NaN
@@ -1029,7 +1031,7 @@ func dLambertW(d *Decimal, tol float64, principal bool) *Decimal {
 		w = Ln(d)
 	} else {
 		if d.Eq(dZero) {
-			return dFC_NN(-1, math.Inf(-1), math.Inf(-1))
+			return dFC_NN(-1, math.Inf(1), math.Inf(1))
 		}
 
 		w = Ln(d.Neg())
@@ -1064,17 +1066,27 @@ func LambertW[DS DecimalSource](d DS, principal bool) *Decimal {
 // W0 works for any number >= -1/e, but W-1 only works for nonpositive numbers >= -1/e
 // The principal paremeter determines which branch to use
 func (d *Decimal) LambertW(principal bool) *Decimal {
+	return d.lambertW(principal, 0)
+}
+
+// lambertW iterates until the relative tolerance tol is reached. A tol of 0 is the package default: 1e-10,
+// except for the principal branch at layer 0, which iterates to full float64 precision.
+func (d *Decimal) lambertW(principal bool, tol float64) *Decimal {
+	layer0Tol := tol
+	if tol <= 0 {
+		tol, layer0Tol = 1e-10, 1e-100
+	}
 	if d.Lt(D(-0.3678794411710499)) {
 		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
 	} else if principal {
 		if d.Abs().Lt(D("1e-300")) {
 			return decimalFromDecimal(d)
 		} else if d.mag < 0 {
-			return decimalFromFloat64(fLambertW(d.ToFloat64(), 1e-10, true))
+			return decimalFromFloat64(fLambertW(d.ToFloat64(), tol, true))
 		} else if d.layer == 0 {
-			return decimalFromFloat64(fLambertW(d.sign*d.mag, 1e-100, true))
+			return decimalFromFloat64(fLambertW(d.sign*d.mag, layer0Tol, true))
 		} else if d.Lt(D("eee15")) {
-			return dLambertW(d, 1e-10, true)
+			return dLambertW(d, tol, true)
 		} else {
 			return d.Ln()
 		}
@@ -1082,11 +1094,11 @@ func (d *Decimal) LambertW(principal bool) *Decimal {
 		if d.sign == 1 {
 			return dFC_NN(math.NaN(), math.NaN(), math.NaN())
 		} else if d.layer == 0 {
-			return decimalFromFloat64(fLambertW(d.sign*d.mag, 1e-10, false))
+			return decimalFromFloat64(fLambertW(d.sign*d.mag, tol, false))
 		} else if d.layer == 1 {
-			return dLambertW(d, 1e-10, false)
+			return dLambertW(d, tol, false)
 		} else {
-			return d.Neg().Recip().LambertW(true).Neg()
+			return d.Neg().Recip().lambertW(true, tol).Neg()
 		}
 	}
 }
@@ -1121,7 +1133,7 @@ func (d *Decimal) IteratedLog(base *Decimal, times float64, linear bool) *Decima
 
 	for i := 0; i < int(times); i++ {
 		result = result.Log(base)
-		if math.IsInf(result.layer, 0) || math.IsInf(result.mag, 0) {
+		if !isFinite(result.layer) || !isFinite(result.mag) {
 			return result.Normalize()
 		}
 		if i > 10000 {
@@ -1227,7 +1239,7 @@ func (d *Decimal) LayerAdd10(diff *Decimal, linear bool) *Decimal {
 	result = result.Normalize()
 
 	if fDiff != 0 {
-		return result.LayerAdd(diff, D(10), linear)
+		return result.LayerAdd(D(fDiff), D(10), linear)
 	}
 
 	return result
@@ -1355,12 +1367,20 @@ func Slog[DS DecimalSource](d DS, base DS, iterations float64, linear bool) *Dec
 // So this library uses an analytic approximation for bases <= 10, but reverts to a linear approximation for bases > 10
 // If you want to use the linear approximation for all bases, set linear parameter to true
 func (d *Decimal) Slog(base *Decimal, iterations float64, linear bool) *Decimal {
+	result, _ := d.slogSearch(base, iterations, linear)
+	return decimalFromFloat64(result)
+}
+
+// slogSearch runs Slog's search and returns its result together with how far the result can be from the true
+// super-logarithm. Once the search has overshot the answer, every step halves and turns back towards it,
+// so the answer is never further away than the last step. Before that, the distance is unknown and is infinite.
+func (d *Decimal) slogSearch(base *Decimal, iterations float64, linear bool) (float64, float64) {
 	stepSize := 0.001
 	hasChangedDirectionsOnce := false
 	previouslyRose := false
 	result := d.slogInternal(base, linear).ToFloat64()
 	for i := 1; i < int(iterations); i++ {
-		newDecimal := decimalFromDecimal(d).Tetrate(result, dOne, linear)
+		newDecimal := decimalFromDecimal(base).Tetrate(result, dOne, linear)
 		currentlyRose := newDecimal.Gt(d)
 		if i > 1 {
 			if previouslyRose != currentlyRose {
@@ -1384,7 +1404,10 @@ func (d *Decimal) Slog(base *Decimal, iterations float64, linear bool) *Decimal
 		}
 	}
 
-	return decimalFromFloat64(result)
+	if !hasChangedDirectionsOnce {
+		return result, math.Inf(1)
+	}
+	return result, math.Abs(stepSize)
 }
 
 // Tetrate is the result of exponentiating 'd' to 'payload' 'height' times in a row
@@ -1481,7 +1504,7 @@ func (d *Decimal) Tetrate(height float64, payload *Decimal, linear bool) *Decima
 
 		for i := 0; i < int(limitHeight); i++ {
 			oldPayload := payload
-			payload := d.Pow(payload)
+			payload = d.Pow(payload)
 			if oldPayload.Eq(payload) {
 				return payload
 			}
@@ -1517,7 +1540,7 @@ func (d *Decimal) Tetrate(height float64, payload *Decimal, linear bool) *Decima
 
 	for i := 0; i < int(height); i++ {
 		payload = d.Pow(payload)
-		if math.IsInf(payload.layer, 0) || math.IsInf(payload.mag, 0) {
+		if !isFinite(payload.layer) || !isFinite(payload.mag) {
 			return payload.Normalize()
 		}
 		if payload.layer-d.layer > 3 {
@@ -1557,7 +1580,7 @@ func (d *Decimal) Pentate(height float64, payload *Decimal, linear bool) *Decima
 
 	for i := 0; i < int(height); i++ {
 		payload = d.Tetrate(payload.ToFloat64(), dOne, linear)
-		if math.IsInf(payload.layer, 0) || math.IsInf(payload.mag, 0) {
+		if !isFinite(payload.layer) || !isFinite(payload.mag) {
 			return payload.Normalize()
 		}
 		if i > 10 {
@@ -0,0 +1,253 @@
+package breaketernity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAddOppositeSigns(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want float64
+	}{
+		{"5", "-3", 2},
+		{"-5", "3", -2},
+		{"5", "-5", 0},
+		{"1e400", "-1e400", 0},
+	}
+	for _, c := range cases {
+		if got := D(c.a).Add(D(c.b)); got.ToFloat64() != c.want {
+			t.Errorf("%s + %s = %s, want %g", c.a, c.b, got.ToString(), c.want)
+		}
+	}
+	if got := D("1e400").Add(D("-1e399")); !got.Gt(D("1e399")) {
+		t.Errorf("1e400 + -1e399 = %s, want 9e399", got.ToString())
+	}
+}
+
+func TestSlogInvertsTetrate(t *testing.T) {
+	for _, base := range []float64{2, 10} {
+		for _, height := range []float64{0.5, 1.5, 2.5, 3.25} {
+			x := D(base).Tetrate(height, dOne, false)
+			if got := x.Slog(D(base), DEFAULT_SLOG_ITERATIONS, false).ToFloat64(); math.Abs(got-height) > 1e-9*height {
+				t.Errorf("slog(%g^^%g, %g) = %g", base, height, base, got)
+			}
+		}
+	}
+}
+
+func TestNegFlipsSign(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"5", "-5"},
+		{"-5", "5"},
+		{"0", "0"},
+		{"1e400", "-1e400"},
+		{"-1e-400", "1e-400"},
+	}
+	for _, c := range cases {
+		if got := D(c.in).Neg(); !got.Eq(D(c.want)) {
+			t.Errorf("-(%s) = %s, want %s", c.in, got.ToString(), c.want)
+		}
+	}
+	if got := D(7).Subtract(D(3)); !got.Eq(D(4)) {
+		t.Errorf("7 - 3 = %s, want 4", got.ToString())
+	}
+}
+
+func TestMaxMinPickTheRightOperand(t *testing.T) {
+	a, b := D(-7), D(3)
+	if got := a.Max(b); !got.Eq(b) {
+		t.Errorf("max(-7, 3) = %s", got.ToString())
+	}
+	if got := a.Min(b); !got.Eq(a) {
+		t.Errorf("min(-7, 3) = %s", got.ToString())
+	}
+	if got := a.MaxAbs(b); !got.Eq(a) {
+		t.Errorf("maxabs(-7, 3) = %s", got.ToString())
+	}
+	if got := a.MinAbs(b); !got.Eq(b) {
+		t.Errorf("minabs(-7, 3) = %s", got.ToString())
+	}
+	// The result is a copy, so mutating it leaves the operands alone
+	got := a.Max(b)
+	got.sign = -1
+	if !b.Eq(D(3)) {
+		t.Errorf("max returned its operand: b is now %s", b.ToString())
+	}
+}
+
+func TestCeilAndFloor(t *testing.T) {
+	cases := []struct {
+		in          string
+		ceil, floor string
+	}{
+		{"2.5", "3", "2"},
+		{"-2.5", "-2", "-3"},
+		{"3", "3", "3"},
+		{"0", "0", "0"},
+		{"1e-400", "1", "0"},
+		{"-1e-400", "0", "-1"},
+		{"1e400", "1e400", "1e400"},
+	}
+	for _, c := range cases {
+		if got := D(c.in).Ceil(); !got.Eq(D(c.ceil)) {
+			t.Errorf("ceil(%s) = %s, want %s", c.in, got.ToString(), c.ceil)
+		}
+		if got := D(c.in).Floor(); !got.Eq(D(c.floor)) {
+			t.Errorf("floor(%s) = %s, want %s", c.in, got.ToString(), c.floor)
+		}
+	}
+}
+
+func TestModuloTruncates(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want float64
+	}{
+		{"7", "3", 1},
+		{"-7", "3", -1},
+		{"7", "-3", 1},
+		{"-7", "-3", -1},
+		{"7.5", "2", 1.5},
+		{"5", "0", 0},
+	}
+	for _, c := range cases {
+		if got := D(c.a).Modulo(D(c.b)); got.ToFloat64() != c.want {
+			t.Errorf("%s mod %s = %s, want %g", c.a, c.b, got.ToString(), c.want)
+		}
+	}
+}
+
+func TestPowBase10OutsideFloat64(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"400", "1e400"},
+		{"-400", "1e-400"},
+		{"-5", "0.00001"},
+	}
+	for _, c := range cases {
+		if got := D(c.in).PowBase10(); math.Abs(got.Log10().ToFloat64()-D(c.want).Log10().ToFloat64()) > 1e-9 {
+			t.Errorf("10^%s = %s, want %s", c.in, got.ToString(), c.want)
+		}
+	}
+}
+
+func TestPowBaseNRaisesTheBase(t *testing.T) {
+	if got := D(3).PowBaseN(D(2)).ToFloat64(); math.Abs(got-8) > 1e-12 {
+		t.Errorf("2^3 = %g, want 8", got)
+	}
+	if got := PowBaseN(10, 2).ToFloat64(); math.Abs(got-1024) > 1e-9 {
+		t.Errorf("2^10 = %g, want 1024", got)
+	}
+}
+
+func TestFactorialAboveLayer0(t *testing.T) {
+	// Stirling: log10(n!) ≈ n(log10(n) - log10(e)) for huge n
+	n := 1e20
+	want := n * (math.Log10(n) - math.Log10E)
+	if got := D(n).Factorial().Log10().ToFloat64(); math.Abs(got-want) > 1e-9*want {
+		t.Errorf("log10((1e20)!) = %g, want %g", got, want)
+	}
+	huge := D("1e1e20")
+	if got := huge.Factorial(); !got.Eq(huge.PowBaseE()) {
+		t.Errorf("(1e1e20)! = %s, want e^1e1e20", got.ToString())
+	}
+}
+
+func TestAddInfinities(t *testing.T) {
+	inf, negInf := D(math.Inf(1)), D(math.Inf(-1))
+	if got := inf.Add(inf).ToFloat64(); !math.IsInf(got, 1) {
+		t.Errorf("inf + inf = %g, want +Inf", got)
+	}
+	if got := negInf.Add(negInf).ToFloat64(); !math.IsInf(got, -1) {
+		t.Errorf("-inf + -inf = %g, want -Inf", got)
+	}
+	if got := inf.Add(negInf); !got.IsNaN() {
+		t.Errorf("inf + -inf = %s, want NaN", got.ToString())
+	}
+}
+
+func TestTetrateConvergingBases(t *testing.T) {
+	cases := []struct {
+		base, height float64
+	}{
+		{0.5, 3},
+		{0.5, 4},
+		{math.Sqrt2, 2},
+		{math.Sqrt2, 5},
+	}
+	for _, c := range cases {
+		want := 1.0
+		for i := 0; i < int(c.height); i++ {
+			want = math.Pow(c.base, want)
+		}
+		if got := D(c.base).Tetrate(c.height, dOne, false).ToFloat64(); math.Abs(got-want) > 1e-12 {
+			t.Errorf("%g^^%g = %g, want %g", c.base, c.height, got, want)
+		}
+	}
+}
+
+func TestLayerAdd10FractionalDiff(t *testing.T) {
+	// Adding 1.5 layers is adding 1 layer and then the remaining half
+	want := D(5).LayerAdd10(D(1), false).LayerAdd(D(0.5), D(10), false)
+	if got := D(5).LayerAdd10(D(1.5), false); !got.Eq(want) {
+		t.Errorf("layeradd10(5, 1.5) = %s, want %s", got.ToString(), want.ToString())
+	}
+	want = D(5).LayerAdd(D(-0.5), D(10), false)
+	if got := D("1e5").LayerAdd10(D(-1.5), false); !got.Eq(want) {
+		t.Errorf("layeradd10(1e5, -1.5) = %s, want %s", got.ToString(), want.ToString())
+	}
+}
+
+func TestLambertWBranchMinusOneAtZero(t *testing.T) {
+	// W₋₁ tends to -∞ as its argument approaches 0 from below
+	if got := dLambertW(dZero, 1e-10, false).ToFloat64(); !math.IsInf(got, -1) {
+		t.Errorf("W₋₁(0) = %g, want -Inf", got)
+	}
+}
+
+func TestTetrateNegativeOne(t *testing.T) {
+	// (-1)^(-1)^… stays at -1, including for fractional heights
+	for _, height := range []float64{0.5, 2.5, 3} {
+		if got := D(-1).Tetrate(height, dOne, false); !got.Eq(D(-1)) {
+			t.Errorf("(-1)^^%g = %s, want -1", height, got.ToString())
+		}
+	}
+}
+
+func TestArithmeticPropagatesNaN(t *testing.T) {
+	nan := dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	ops := map[string]func(a, b *Decimal) *Decimal{
+		"add":      (*Decimal).Add,
+		"multiply": (*Decimal).Multiply,
+	}
+	for name, op := range ops {
+		if got := op(nan, D(2)); !got.IsNaN() {
+			t.Errorf("%s(NaN, 2) = %s, want NaN", name, got.ToString())
+		}
+		if got := op(D(2), nan); !got.IsNaN() {
+			t.Errorf("%s(2, NaN) = %s, want NaN", name, got.ToString())
+		}
+	}
+	if got := nan.PowBase10(); !got.IsNaN() {
+		t.Errorf("10^NaN = %s, want NaN", got.ToString())
+	}
+}
+
+func TestLambertWTolerance(t *testing.T) {
+	// The package-level LambertW iterates the layer 0 principal branch to full precision, whatever the Context default
+	for _, x := range []float64{0.3, 2, 7.5, 100, 1e5, -0.36787944117} {
+		if got, want := D(x).LambertW(true).ToFloat64(), fLambertW(x, 0, true); got != want {
+			t.Errorf("W(%g) = %v, want %v", x, got, want)
+		}
+	}
+	// A Context tolerance only applies through the Context
+	ctx := DefaultContext()
+	ctx.LambertWTolerance = 0.1
+	if loose, full := ctx.LambertW(D(50), true), D(50).LambertW(true); loose.Eq(full) {
+		t.Errorf("Context.LambertW with tolerance 0.1 = %s, same as the package default", loose.ToString())
+	}
+}
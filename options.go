@@ -0,0 +1,85 @@
+package breaketernity
+
+import (
+	"strings"
+)
+
+// ParseOptions controls how ParseWith reads a string into a Decimal.
+// The zero value is the default used by D().
+type ParseOptions struct {
+	// KeepCommas leaves commas in the string, where they end the number like in parseFloat,
+	// instead of removing them so that "1,000,000" reads as 1e6.
+	KeepCommas bool
+	// CommasAreDecimalPoints replaces every comma with a period, so "1,5e10" reads as 1.5e10.
+	CommasAreDecimalPoints bool
+	// LinearHyper4 uses the linear approximation for the tetration and pentation formats (X^^N, X^^^N, NpX, XFN).
+	LinearHyper4 bool
+	// WhitespaceSeparators removes whitespace inside the string, so "1 000 000" reads as 1e6.
+	WhitespaceSeparators bool
+	// UnderscoreSeparators removes underscores, so "1_000_000" reads as 1e6.
+	UnderscoreSeparators bool
+}
+
+// FormatOptions controls how FormatWith turns a Decimal into a string.
+// The zero value is the default used by ToString.
+type FormatOptions struct {
+	// MaxEsInARow is the highest layer written as a run of e's ("eeeX") before switching to "(e^N)X".
+	// 0 means MAX_ES_IN_A_ROW, and 1 always writes layers 2 and above as "(e^N)X".
+	MaxEsInARow float64
+	// FixedPlaces rounds to Places decimal places instead of keeping the shortest representation.
+	FixedPlaces bool
+	// Places is the number of decimal places kept when FixedPlaces is set.
+	Places int
+}
+
+// DefaultParseOptions returns the options used by D() when given a string.
+func DefaultParseOptions() ParseOptions {
+	return ParseOptions{
+		KeepCommas:             !IGNORE_COMMAS,
+		CommasAreDecimalPoints: COMMAS_ARE_DECIMAL_POINTS,
+	}
+}
+
+// DefaultFormatOptions returns the options used by ToString.
+func DefaultFormatOptions() FormatOptions {
+	return FormatOptions{
+		MaxEsInARow: MAX_ES_IN_A_ROW,
+	}
+}
+
+// ParseWith creates a new Decimal from a string, using the given options instead of the package defaults.
+func ParseWith(s string, opts ParseOptions) *Decimal {
+	return decimalFromStringWith(s, opts)
+}
+
+// FormatWith returns the string representation of the decimal, using the given options instead of the package defaults.
+func FormatWith[DS DecimalSource](d DS, opts FormatOptions) string {
+	return D(d).FormatWith(opts)
+}
+
+// FormatWith returns the string representation of the decimal, using the given options instead of the package defaults.
+func (d *Decimal) FormatWith(opts FormatOptions) string {
+	maxEsInARow := opts.MaxEsInARow
+	if maxEsInARow == 0 {
+		maxEsInARow = MAX_ES_IN_A_ROW
+	}
+	if opts.FixedPlaces {
+		return d.formatWithNDecimalPlaces(opts.Places, maxEsInARow)
+	}
+	return d.format(maxEsInARow)
+}
+
+func applyParseOptions(s string, opts ParseOptions) string {
+	if opts.CommasAreDecimalPoints {
+		s = strings.Replace(s, ",", ".", -1)
+	} else if !opts.KeepCommas {
+		s = strings.Replace(s, ",", "", -1)
+	}
+	if opts.UnderscoreSeparators {
+		s = strings.Replace(s, "_", "", -1)
+	}
+	if opts.WhitespaceSeparators {
+		s = strings.Join(strings.Fields(s), "")
+	}
+	return s
+}
@@ -0,0 +1,69 @@
+package breaketernity
+
+import "testing"
+
+func TestZeroOptionsAreTheDefaults(t *testing.T) {
+	if DefaultParseOptions() != (ParseOptions{}) {
+		t.Errorf("DefaultParseOptions() = %+v, want the zero value", DefaultParseOptions())
+	}
+	if DefaultFormatOptions().FixedPlaces || DefaultFormatOptions().Places != 0 {
+		t.Errorf("DefaultFormatOptions() = %+v, want no fixed places", DefaultFormatOptions())
+	}
+	for _, s := range []string{"1,000,000", "1.5e400", "ee20", "eeeeeee3", "-12.5", "10^^3.5"} {
+		if got, want := ParseWith(s, ParseOptions{}), D(s); !got.Eq(want) {
+			t.Errorf("ParseWith(%q, ParseOptions{}) = %s, want %s", s, got.ToString(), want.ToString())
+		}
+		d := D(s)
+		if got, want := d.FormatWith(FormatOptions{}), d.ToString(); got != want {
+			t.Errorf("FormatWith(%s, FormatOptions{}) = %q, want %q", s, got, want)
+		}
+		if got, want := d.FormatWith(DefaultFormatOptions()), d.ToString(); got != want {
+			t.Errorf("FormatWith(%s, DefaultFormatOptions()) = %q, want %q", s, got, want)
+		}
+	}
+}
+
+func TestParseWith(t *testing.T) {
+	cases := []struct {
+		s    string
+		opts ParseOptions
+		want *Decimal
+	}{
+		{"1,000,000", ParseOptions{}, D(1e6)},
+		{"1,000,000", ParseOptions{KeepCommas: true}, D(1)},
+		{"1,5e10", ParseOptions{CommasAreDecimalPoints: true}, D(1.5e10)},
+		{"1 000 000", ParseOptions{}, D(1)},
+		{"1 000 000", ParseOptions{WhitespaceSeparators: true}, D(1e6)},
+		{"1_000_000", ParseOptions{}, D(1)},
+		{"1_000_000", ParseOptions{UnderscoreSeparators: true}, D(1e6)},
+		{"10^^2.5", ParseOptions{}, D(10).Tetrate(2.5, dOne, false)},
+		{"10^^2.5", ParseOptions{LinearHyper4: true}, D(10).Tetrate(2.5, dOne, true)},
+	}
+	for _, c := range cases {
+		if got := ParseWith(c.s, c.opts); !got.Eq(c.want) {
+			t.Errorf("ParseWith(%q, %+v) = %s, want %s", c.s, c.opts, got.ToString(), c.want.ToString())
+		}
+	}
+}
+
+func TestFormatWith(t *testing.T) {
+	cases := []struct {
+		s    string
+		opts FormatOptions
+		want string
+	}{
+		{"1.23456e1000", FormatOptions{FixedPlaces: true, Places: 2}, "1.23e1000"},
+		{"3.14159", FormatOptions{FixedPlaces: true}, "3"},
+		{"3.14159", FormatOptions{Places: 2}, "3.14159"},
+		{"eee20", FormatOptions{}, "eee20"},
+		{"eee20", FormatOptions{MaxEsInARow: 1}, "(e^3)20"},
+		{"eee20", FormatOptions{MaxEsInARow: 2}, "(e^3)20"},
+		{"eeeeeee20", FormatOptions{MaxEsInARow: 10}, "eeeeeee20"},
+		{"eeeeeee20", FormatOptions{}, "(e^7)20"},
+	}
+	for _, c := range cases {
+		if got := FormatWith(c.s, c.opts); got != c.want {
+			t.Errorf("FormatWith(%q, %+v) = %q, want %q", c.s, c.opts, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package breaketernity
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// DEFAULT_CHUNK_SIZE is the number of values a worker processes at a time when ParallelOptions.ChunkSize is not set.
+const DEFAULT_CHUNK_SIZE = 1024
+
+// ParallelOptions controls how ParallelMap, ParallelReduce and ParallelSum split their work.
+// Workers is the number of goroutines (runtime.GOMAXPROCS(0) if <= 0),
+// and ChunkSize the number of values handed to a worker at a time (DEFAULT_CHUNK_SIZE if <= 0).
+// Results never depend on Workers or on scheduling. ParallelReduce's result depends on ChunkSize
+// when f is not exactly associative, ParallelMap's and ParallelSum's never do.
+type ParallelOptions struct {
+	Workers   int
+	ChunkSize int
+}
+
+// DefaultParallelOptions returns options using one worker per CPU and DEFAULT_CHUNK_SIZE.
+func DefaultParallelOptions() ParallelOptions {
+	return ParallelOptions{Workers: runtime.GOMAXPROCS(0), ChunkSize: DEFAULT_CHUNK_SIZE}
+}
+
+// ParallelMap returns f applied to every value, in the same order as values.
+// If ctx is cancelled before every chunk is processed, the context's error is returned instead.
+func ParallelMap(ctx context.Context, values []*Decimal, f func(*Decimal) *Decimal, opts ParallelOptions) ([]*Decimal, error) {
+	result := make([]*Decimal, len(values))
+	err := forEachChunk(ctx, len(values), opts, func(chunk int, start int, end int) {
+		for i := start; i < end; i++ {
+			result[i] = f(values[i])
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ParallelReduce folds the values with f, starting from identity.
+// Each chunk is folded in order, then the chunk results are folded in order, so f must be associative
+// and identity must be its identity element for the result to match a sequential fold.
+// If ctx is cancelled before every chunk is processed, the context's error is returned instead.
+func ParallelReduce(ctx context.Context, values []*Decimal, f func(acc *Decimal, value *Decimal) *Decimal, identity *Decimal, opts ParallelOptions) (*Decimal, error) {
+	opts = opts.withDefaults()
+	partials := make([]*Decimal, chunkCount(len(values), opts.ChunkSize))
+	err := forEachChunk(ctx, len(values), opts, func(chunk int, start int, end int) {
+		acc := decimalFromDecimal(identity)
+		for i := start; i < end; i++ {
+			acc = f(acc, values[i])
+		}
+		partials[chunk] = acc
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := decimalFromDecimal(identity)
+	for _, p := range partials {
+		result = f(result, p)
+	}
+	return result, nil
+}
+
+// ParallelSum returns the sum of the values, which is the same as Sum(values...) whatever the options.
+// The chunks are sorted by magnitude in parallel, then merged and summed in one pass.
+// If ctx is cancelled before every chunk is processed, the context's error is returned instead.
+func ParallelSum(ctx context.Context, values []*Decimal, opts ParallelOptions) (*Decimal, error) {
+	opts = opts.withDefaults()
+	runs := make([][]*Decimal, chunkCount(len(values), opts.ChunkSize))
+	err := forEachChunk(ctx, len(values), opts, func(chunk int, start int, end int) {
+		run := slices.Clone(values[start:end])
+		slices.SortFunc(run, cmpAbsDescending)
+		runs[chunk] = run
+	})
+	if err != nil {
+		return nil, err
+	}
+	for len(runs) > 1 {
+		merged := make([][]*Decimal, 0, (len(runs)+1)/2)
+		for i := 0; i < len(runs); i += 2 {
+			if i+1 == len(runs) {
+				merged = append(merged, runs[i])
+			} else {
+				merged = append(merged, mergeSorted(runs[i], runs[i+1]))
+			}
+		}
+		runs = merged
+	}
+	if len(runs) == 0 {
+		return sumDecimals(nil), nil
+	}
+	return sumDecimals(runs[0]), nil
+}
+
+// mergeSorted merges two runs sorted with cmpAbsDescending into a new sorted run.
+func mergeSorted(a []*Decimal, b []*Decimal) []*Decimal {
+	merged := make([]*Decimal, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if cmpAbsDescending(b[0], a[0]) < 0 {
+			merged, b = append(merged, b[0]), b[1:]
+		} else {
+			merged, a = append(merged, a[0]), a[1:]
+		}
+	}
+	merged = append(merged, a...)
+	return append(merged, b...)
+}
+
+func (opts ParallelOptions) withDefaults() ParallelOptions {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DEFAULT_CHUNK_SIZE
+	}
+	return opts
+}
+
+func chunkCount(n int, chunkSize int) int {
+	return (n + chunkSize - 1) / chunkSize
+}
+
+// forEachChunk calls process for every chunk of [0, n) from opts.Workers goroutines,
+// and stops handing out chunks once ctx is cancelled.
+func forEachChunk(ctx context.Context, n int, opts ParallelOptions, process func(chunk int, start int, end int)) error {
+	opts = opts.withDefaults()
+	chunks := chunkCount(n, opts.ChunkSize)
+	workers := min(opts.Workers, chunks)
+
+	var next, done atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				chunk := int(next.Add(1) - 1)
+				if chunk >= chunks {
+					return
+				}
+				start := chunk * opts.ChunkSize
+				process(chunk, start, min(start+opts.ChunkSize, n))
+				done.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if int(done.Load()) < chunks {
+		return ctx.Err()
+	}
+	return nil
+}
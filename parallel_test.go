@@ -0,0 +1,83 @@
+package breaketernity
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+// cancellingValues returns n values from several layers, with every large value followed later by its negation,
+// so summing them in chunks would lose the small terms that remain.
+func cancellingValues(n int) []*Decimal {
+	r := rand.New(rand.NewPCG(1, 2))
+	values := make([]*Decimal, 0, n)
+	for len(values) < n {
+		switch r.IntN(3) {
+		case 0:
+			values = append(values, D(r.Float64()*100-50))
+		case 1:
+			large := dFC(1, 1, 300+r.Float64()*200)
+			values = append(values, large, large.Neg())
+		default:
+			large := dFC(1, 2, 1+r.Float64()*5)
+			values = append(values, large, large.Neg())
+		}
+	}
+	r.Shuffle(len(values), func(i, j int) {
+		values[i], values[j] = values[j], values[i]
+	})
+	return values[:n]
+}
+
+func TestParallelSumMatchesSum(t *testing.T) {
+	for _, n := range []int{0, 1, 7, 1000, 5000} {
+		values := cancellingValues(n)
+		want := Sum(values...)
+		for _, opts := range []ParallelOptions{{}, {Workers: 1, ChunkSize: 1}, {Workers: 3, ChunkSize: 7}, {Workers: 8, ChunkSize: 100}} {
+			got, err := ParallelSum(context.Background(), values, opts)
+			if err != nil {
+				t.Fatalf("ParallelSum(%d values, %+v): %v", n, opts, err)
+			}
+			if !got.Eq(want) {
+				t.Errorf("ParallelSum(%d values, %+v) = %s, want %s", n, opts, got.ToString(), want.ToString())
+			}
+		}
+	}
+}
+
+func TestParallelMapAndReduce(t *testing.T) {
+	values := make([]*Decimal, 100)
+	for i := range values {
+		values[i] = D(i)
+	}
+	opts := ParallelOptions{Workers: 4, ChunkSize: 9}
+	squares, err := ParallelMap(context.Background(), values, func(d *Decimal) *Decimal { return d.Multiply(d) }, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, d := range squares {
+		if !d.Eq(D(i * i)) {
+			t.Errorf("ParallelMap squares[%d] = %s, want %d", i, d.ToString(), i*i)
+		}
+	}
+	largest, err := ParallelReduce(context.Background(), values, (*Decimal).Max, D(-1), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !largest.Eq(D(99)) {
+		t.Errorf("ParallelReduce with Max = %s, want 99", largest.ToString())
+	}
+}
+
+func TestParallelCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	values := cancellingValues(100)
+	if _, err := ParallelSum(ctx, values, ParallelOptions{ChunkSize: 10}); !errors.Is(err, context.Canceled) {
+		t.Errorf("ParallelSum with a cancelled context returned %v, want context.Canceled", err)
+	}
+	if _, err := ParallelMap(ctx, values, (*Decimal).Abs, ParallelOptions{ChunkSize: 10}); !errors.Is(err, context.Canceled) {
+		t.Errorf("ParallelMap with a cancelled context returned %v, want context.Canceled", err)
+	}
+}
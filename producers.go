@@ -0,0 +1,340 @@
+package breaketernity
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrMaxSteps = errors.New("breaketernity: simulation did not finish within MaxSteps")
+
+var ErrInvalidDuration = errors.New("breaketernity: simulation duration must be finite and not negative")
+
+// ProducerTier is one tier of a producer chain. Every unit of the tier produces Rate units of the tier below it
+// and Growth units of itself per second. A nil Amount, Rate or Growth is 0. The Rate of tier 0 is unused.
+type ProducerTier struct {
+	Amount *Decimal
+	Rate   *Decimal
+	Growth *Decimal
+}
+
+// ProducerRateFunc returns the Rate of a tier at time t, in seconds since the start of the simulation,
+// from the current amounts of every tier, for rates that change as the amounts do, such as a multiplier
+// based on the currency owned.
+type ProducerRateFunc func(tier int, t float64, amounts []*Decimal) *Decimal
+
+// SimulateOptions controls how Simulate advances a producer chain.
+// RateFunc, if set, replaces the Rate of every tier and makes the simulation step adaptively.
+// Tolerance is the relative error allowed per adaptive step, and MaxSteps bounds the number of adaptive steps,
+// rejected ones included.
+type SimulateOptions struct {
+	RateFunc  ProducerRateFunc
+	MaxSteps  int
+	Tolerance float64
+}
+
+// DefaultSimulateOptions returns options with constant rates, for up to 1e6 adaptive steps, to a tolerance of 1e-9.
+func DefaultSimulateOptions() SimulateOptions {
+	return SimulateOptions{MaxSteps: 1e6, Tolerance: 1e-9}
+}
+
+// SimulateMethod is the way Simulate solved a producer chain
+type SimulateMethod int
+
+const (
+	// SIMULATE_CLOSED_FORM is used when the rates are constant and every tier has the same Growth g:
+	// each amount is e^(g*t) times a polynomial in t
+	SIMULATE_CLOSED_FORM SimulateMethod = iota
+	// SIMULATE_MATRIX_EXP is used when the rates are constant and the tiers grow at different rates:
+	// the exact solution over a short time is squared until it covers the whole duration
+	SIMULATE_MATRIX_EXP
+	// SIMULATE_ADAPTIVE is used with a RateFunc: the chain is integrated with a Runge-Kutta method whose steps
+	// grow as long as the error stays within the tolerance. The growth of each tier is factored out exactly.
+	SIMULATE_ADAPTIVE
+)
+
+// SimulateResult is the state of a producer chain at the end of Simulate.
+// Elapsed is the number of seconds simulated, which is less than requested only along with an error.
+// Steps is the number of squarings with SIMULATE_MATRIX_EXP and of adaptive steps with SIMULATE_ADAPTIVE.
+type SimulateResult struct {
+	Amounts []*Decimal
+	Elapsed float64
+	Method  SimulateMethod
+	Steps   int
+}
+
+// Simulate advances a chain of producer tiers by the given number of seconds, where tier n produces tier n-1
+// and tier 0 is the currency. The top tier only changes through its Growth. It never iterates per tick:
+// constant rates are solved exactly, and a RateFunc is integrated with steps as long as the tolerance allows.
+// Returns ErrInvalidDuration, along with the initial state, if seconds is negative, infinite or NaN.
+// Returns ErrMaxSteps, along with the state reached, if the adaptive steps run out,
+// and ErrNoConvergence if the steps become too short to make progress, e.g. once an amount is NaN,
+// or if the rates are so high that the matrix exponential would need more than maxSquarings squarings.
+func Simulate(tiers []ProducerTier, seconds float64, opts SimulateOptions) (SimulateResult, error) {
+	if opts.MaxSteps <= 0 {
+		opts.MaxSteps = 1e6
+	}
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = 1e-9
+	}
+	amounts := make([]*Decimal, len(tiers))
+	rates := make([]*Decimal, len(tiers))
+	growths := make([]*Decimal, len(tiers))
+	sameGrowth := true
+	for i, tier := range tiers {
+		amounts[i], rates[i], growths[i] = orZero(tier.Amount), orZero(tier.Rate), orZero(tier.Growth)
+		sameGrowth = sameGrowth && growths[i].Eq(growths[0])
+	}
+	if seconds < 0 || math.IsNaN(seconds) || math.IsInf(seconds, 1) {
+		return SimulateResult{Amounts: amounts}, ErrInvalidDuration
+	}
+	if len(tiers) == 0 || seconds == 0 {
+		return SimulateResult{Amounts: amounts}, nil
+	}
+
+	switch {
+	case opts.RateFunc != nil:
+		return simulateAdaptive(amounts, growths, seconds, opts)
+	case sameGrowth:
+		return SimulateResult{Amounts: simulateClosedForm(amounts, rates, growths[0], seconds), Elapsed: seconds}, nil
+	}
+	result, steps, ok := simulateMatrixExp(amounts, rates, growths, seconds)
+	if !ok {
+		return SimulateResult{Amounts: amounts, Method: SIMULATE_MATRIX_EXP}, ErrNoConvergence
+	}
+	return SimulateResult{Amounts: result, Elapsed: seconds, Method: SIMULATE_MATRIX_EXP, Steps: steps}, nil
+}
+
+func orZero(d *Decimal) *Decimal {
+	if d == nil {
+		return decimalFromDecimal(dZero)
+	}
+	return decimalFromDecimal(d)
+}
+
+// simulateClosedForm solves a chain whose tiers all grow at the same rate g:
+// amount[k](t) = e^(g*t) * sum over j >= k of amount[j] * rate[k+1] * ... * rate[j] * t^(j-k) / (j-k)!
+func simulateClosedForm(amounts []*Decimal, rates []*Decimal, growth *Decimal, seconds float64) []*Decimal {
+	t := decimalFromFloat64(seconds)
+	result := make([]*Decimal, len(amounts))
+	for k := range amounts {
+		sum := amounts[k]
+		coefficient := dOne
+		for j := k + 1; j < len(amounts); j++ {
+			coefficient = coefficient.Multiply(rates[j]).Multiply(t).Divide(decimalFromFloat64(float64(j - k)))
+			if coefficient.sign == 0 {
+				break
+			}
+			if amounts[j].sign != 0 {
+				sum = sum.Add(amounts[j].Multiply(coefficient))
+			}
+		}
+		if growth.sign != 0 {
+			sum = sum.Multiply(growth.Multiply(t).PowBaseE())
+		}
+		result[k] = sum
+	}
+	return result
+}
+
+// triangular is an upper triangular matrix of Decimals
+type triangular [][]Decimal
+
+func newTriangular(n int) triangular {
+	m := make(triangular, n)
+	for i := range m {
+		m[i] = make([]Decimal, n)
+	}
+	return m
+}
+
+// multiply returns m * other. Entries that are 0 are skipped, so they never turn 0 * Infinity into NaN.
+func (m triangular) multiply(other triangular) triangular {
+	result := newTriangular(len(m))
+	for i := range m {
+		for j := i; j < len(m); j++ {
+			sum := dZero
+			for k := i; k <= j; k++ {
+				if m[i][k].sign != 0 && other[k][j].sign != 0 {
+					sum = sum.Add(m[i][k].Multiply(&other[k][j]))
+				}
+			}
+			result[i][j] = *sum
+		}
+	}
+	return result
+}
+
+// maxSquarings bounds the squarings of simulateMatrixExp. A chain needs more only if its fastest rate
+// times the duration is beyond 2^4095.
+const maxSquarings = 4096
+
+// simulateMatrixExp solves a chain with constant rates as amounts(t) = exp(M*t) * amounts, where M has the growths
+// on its diagonal and the rates above it. exp(M*h) is summed as a Taylor series over a time h short enough for
+// it to converge quickly, then squared until h reaches t. It returns the amounts and the number of squarings,
+// or false if that would be more than maxSquarings.
+func simulateMatrixExp(amounts []*Decimal, rates []*Decimal, growths []*Decimal, seconds float64) ([]*Decimal, int, bool) {
+	n := len(amounts)
+	// Halve h until the norm of M*h is at most 1/2
+	norm := dZero
+	for k := range n {
+		row := growths[k].Abs()
+		if k+1 < n {
+			row = row.Add(rates[k+1].Abs())
+		}
+		norm = norm.Max(row)
+	}
+	squarings := 0
+	if scale := norm.Multiply(decimalFromFloat64(2 * seconds)); scale.Gt(dOne) {
+		log2 := math.Ceil(scale.Log2().ToFloat64())
+		if !(log2 <= maxSquarings) {
+			return nil, 0, false
+		}
+		squarings = int(log2)
+	}
+	h := decimalFromFloat64(seconds).Divide(decimalFromFloat64(2).Pow(decimalFromFloat64(float64(squarings))))
+
+	mh := newTriangular(n)
+	for k := range n {
+		mh[k][k] = *growths[k].Multiply(h)
+		if k+1 < n {
+			mh[k][k+1] = *rates[k+1].Multiply(h)
+		}
+	}
+	// The terms shrink by at least half each time, so 2^-60 / 60! is far below the precision of a Decimal
+	exp, term := newTriangular(n), newTriangular(n)
+	for k := range n {
+		exp[k][k], term[k][k] = *dOne, *dOne
+	}
+	for i := 1; i <= 60; i++ {
+		term = term.multiply(mh)
+		factor := decimalFromFloat64(1 / float64(i))
+		for r := range n {
+			for c := r; c < n; c++ {
+				if term[r][c].sign != 0 {
+					term[r][c] = *term[r][c].Multiply(factor)
+					exp[r][c] = *exp[r][c].Add(&term[r][c])
+				}
+			}
+		}
+	}
+	for range squarings {
+		exp = exp.multiply(exp)
+	}
+
+	result := make([]*Decimal, n)
+	for i := range n {
+		sum := dZero
+		for j := i; j < n; j++ {
+			if exp[i][j].sign != 0 && amounts[j].sign != 0 {
+				sum = sum.Add(exp[i][j].Multiply(amounts[j]))
+			}
+		}
+		result[i] = sum
+	}
+	return result, squarings, true
+}
+
+// simulateAdaptive integrates a chain whose rates come from RateFunc with the Bogacki-Shampine method,
+// a third order Runge-Kutta method with an embedded second order one that estimates the error of each step.
+// It integrates z[k] = amount[k] * e^(-growth[k]*t) rather than the amounts, so that the exponential growth of
+// each tier is exact and the steps only have to follow the production, which varies far more slowly.
+func simulateAdaptive(amounts []*Decimal, growths []*Decimal, seconds float64, opts SimulateOptions) (SimulateResult, error) {
+	n := len(amounts)
+	// growthAt returns e^(g*t), or nil for g = 0
+	growthAt := func(g *Decimal, t float64) *Decimal {
+		if g.sign == 0 {
+			return nil
+		}
+		return g.Multiply(decimalFromFloat64(t)).PowBaseE()
+	}
+	// unscale returns the amounts at time t from z
+	unscale := func(t float64, z []*Decimal) []*Decimal {
+		y := make([]*Decimal, n)
+		for k := range n {
+			y[k] = z[k]
+			if factor := growthAt(growths[k], t); factor != nil && z[k].sign != 0 {
+				y[k] = z[k].Multiply(factor)
+			}
+		}
+		return y
+	}
+	// dz[k]/dt = rate[k+1] * amount[k+1] * e^(-growth[k]*t) = rate[k+1] * z[k+1] * e^((growth[k+1]-growth[k])*t)
+	derivatives := func(t float64, z []*Decimal) []*Decimal {
+		y := unscale(t, z)
+		dz := make([]*Decimal, n)
+		for k := range n {
+			dz[k] = decimalFromDecimal(dZero)
+			if k+1 < n && z[k+1].sign != 0 {
+				dz[k] = opts.RateFunc(k+1, t, y).Multiply(z[k+1])
+				if factor := growthAt(growths[k+1].Subtract(growths[k]), t); factor != nil {
+					dz[k] = dz[k].Multiply(factor)
+				}
+			}
+		}
+		return dz
+	}
+	// combine returns z + h * (c[0] * k[0] + c[1] * k[1] + ...)
+	combine := func(z []*Decimal, h float64, c []float64, k ...[]*Decimal) []*Decimal {
+		result := make([]*Decimal, n)
+		for i := range n {
+			sum := dZero
+			for j := range k {
+				if c[j] != 0 && k[j][i].sign != 0 {
+					sum = sum.Add(k[j][i].Multiply(decimalFromFloat64(c[j])))
+				}
+			}
+			result[i] = z[i].Add(sum.Multiply(decimalFromFloat64(h)))
+		}
+		return result
+	}
+
+	t, h := 0., seconds/64
+	z := amounts
+	k1 := derivatives(t, z)
+	// Rejected steps count towards MaxSteps too, so that the loop always ends
+	steps, attempts := 0, 0
+	for t < seconds {
+		if attempts >= opts.MaxSteps {
+			return SimulateResult{Amounts: unscale(t, z), Elapsed: t, Method: SIMULATE_ADAPTIVE, Steps: steps}, ErrMaxSteps
+		}
+		attempts++
+		last := h >= seconds-t
+		if last {
+			h = seconds - t
+		}
+		k2 := derivatives(t+h/2, combine(z, h/2, []float64{1}, k1))
+		k3 := derivatives(t+3*h/4, combine(z, 3*h/4, []float64{1}, k2))
+		next := combine(z, h, []float64{2. / 9, 1. / 3, 4. / 9}, k1, k2, k3)
+		k4 := derivatives(t+h, next)
+		// The second order solution, whose distance to the third order one estimates the error
+		lower := combine(z, h, []float64{7. / 24, 1. / 4, 1. / 3, 1. / 8}, k1, k2, k3, k4)
+
+		errorRatio := 0.
+		for i := range n {
+			scale := z[i].Abs().Max(next[i].Abs())
+			if scale.sign == 0 {
+				continue
+			}
+			errorRatio = math.Max(errorRatio, next[i].Subtract(lower[i]).Abs().Divide(scale).ToFloat64()/opts.Tolerance)
+		}
+		if math.IsNaN(errorRatio) {
+			errorRatio = math.Inf(1)
+		}
+
+		if errorRatio <= 1 {
+			t += h
+			if last {
+				t = seconds
+			}
+			// The last stage is the first stage of the next step
+			z, k1 = next, k4
+			steps++
+		}
+		// Scale h by the usual safety factor and the third root of the error ratio, within [1/5, 5]
+		h *= math.Min(5, math.Max(0.2, 0.9*math.Pow(errorRatio, -1./3)))
+		if h <= seconds*1e-15 {
+			return SimulateResult{Amounts: unscale(t, z), Elapsed: t, Method: SIMULATE_ADAPTIVE, Steps: steps}, ErrNoConvergence
+		}
+	}
+	return SimulateResult{Amounts: unscale(seconds, z), Elapsed: seconds, Method: SIMULATE_ADAPTIVE, Steps: steps}, nil
+}
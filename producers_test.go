@@ -0,0 +1,129 @@
+package breaketernity
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSimulateInvalidDuration(t *testing.T) {
+	sameGrowth := []ProducerTier{{Amount: D(1)}, {Amount: D(2), Rate: D(3)}}
+	differentGrowths := []ProducerTier{{Amount: D(1)}, {Amount: D(2), Rate: D(3), Growth: D(0.1)}}
+	rateFunc := DefaultSimulateOptions()
+	rateFunc.RateFunc = func(tier int, t float64, amounts []*Decimal) *Decimal { return D(3) }
+	for _, seconds := range []float64{-1, math.NaN(), math.Inf(1), math.Inf(-1)} {
+		for _, c := range []struct {
+			tiers []ProducerTier
+			opts  SimulateOptions
+		}{{sameGrowth, DefaultSimulateOptions()}, {differentGrowths, DefaultSimulateOptions()}, {differentGrowths, rateFunc}} {
+			result, err := Simulate(c.tiers, seconds, c.opts)
+			if !errors.Is(err, ErrInvalidDuration) {
+				t.Errorf("Simulate(%v seconds) returned %v, want ErrInvalidDuration", seconds, err)
+			}
+			if !result.Amounts[0].Eq(D(1)) || result.Steps != 0 {
+				t.Errorf("Simulate(%v seconds) = %s after %d steps, want the initial amounts", seconds, result.Amounts[0].ToString(), result.Steps)
+			}
+		}
+	}
+}
+
+func TestSimulateMatrixExpSquarings(t *testing.T) {
+	tiers := []ProducerTier{{Amount: D(1)}, {Amount: D(2), Rate: D(3), Growth: D(0.1)}}
+	result, err := Simulate(tiers, 1e300, DefaultSimulateOptions())
+	if err != nil || result.Method != SIMULATE_MATRIX_EXP || result.Steps <= 0 || result.Steps > maxSquarings {
+		t.Errorf("Simulate(1e300 seconds) = %d squarings, %v", result.Steps, err)
+	}
+	tiers[1].Rate = D("1e4000")
+	if _, err := Simulate(tiers, 1e300, DefaultSimulateOptions()); !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("Simulate with a rate of 1e4000 for 1e300 seconds returned %v, want ErrNoConvergence", err)
+	}
+}
+
+// relativeError is the relative difference between a Decimal and a float64
+func relativeError(got *Decimal, want float64) float64 {
+	return math.Abs(got.ToFloat64()-want) / math.Abs(want)
+}
+
+func TestSimulateClosedForm(t *testing.T) {
+	// Same growth g: amount0(t) = e^(g*t) * (a0 + r*a1*t + r*r2*a2*t²/2), amount1(t) = e^(g*t) * (a1 + r2*a2*t)
+	a0, a1, a2, r1, r2, g, seconds := 5., 2., 3., 1.5, 0.25, 0.01, 40.
+	tiers := []ProducerTier{{Amount: D(a0), Growth: D(g)}, {Amount: D(a1), Rate: D(r1), Growth: D(g)}, {Amount: D(a2), Rate: D(r2), Growth: D(g)}}
+	result, err := Simulate(tiers, seconds, DefaultSimulateOptions())
+	if err != nil || result.Method != SIMULATE_CLOSED_FORM {
+		t.Fatalf("Simulate = method %d, %v", result.Method, err)
+	}
+	e := math.Exp(g * seconds)
+	want := []float64{e * (a0 + r1*a1*seconds + r1*r2*a2*seconds*seconds/2), e * (a1 + r2*a2*seconds), e * a2}
+	for k, w := range want {
+		if err := relativeError(result.Amounts[k], w); err > 1e-12 {
+			t.Errorf("amount %d = %s, want %g", k, result.Amounts[k].ToString(), w)
+		}
+	}
+}
+
+// twoTiers returns the exact amounts of tier 0 with growth g0 produced at rate r by tier 1 with growth g1
+func twoTiers(a0, a1, r, g0, g1, t float64) (float64, float64) {
+	return math.Exp(g0*t)*a0 + r*a1*(math.Exp(g1*t)-math.Exp(g0*t))/(g1-g0), a1 * math.Exp(g1*t)
+}
+
+func TestSimulateMatrixExp(t *testing.T) {
+	a0, a1, r, g0, g1, seconds := 1., 2., 3., 0.05, 0.2, 30.
+	tiers := []ProducerTier{{Amount: D(a0), Growth: D(g0)}, {Amount: D(a1), Rate: D(r), Growth: D(g1)}}
+	result, err := Simulate(tiers, seconds, DefaultSimulateOptions())
+	if err != nil || result.Method != SIMULATE_MATRIX_EXP {
+		t.Fatalf("Simulate = method %d, %v", result.Method, err)
+	}
+	want0, want1 := twoTiers(a0, a1, r, g0, g1, seconds)
+	if err := relativeError(result.Amounts[0], want0); err > 1e-12 {
+		t.Errorf("amount 0 = %s, want %g", result.Amounts[0].ToString(), want0)
+	}
+	if err := relativeError(result.Amounts[1], want1); err > 1e-12 {
+		t.Errorf("amount 1 = %s, want %g", result.Amounts[1].ToString(), want1)
+	}
+}
+
+func TestSimulateAdaptive(t *testing.T) {
+	constant := func(r float64) SimulateOptions {
+		opts := DefaultSimulateOptions()
+		opts.RateFunc = func(tier int, t float64, amounts []*Decimal) *Decimal { return D(r) }
+		return opts
+	}
+
+	// Constant rates must agree with the exact solution
+	a0, a1, r, g0, g1, seconds := 1., 2., 3., 0.05, 0.2, 30.
+	tiers := []ProducerTier{{Amount: D(a0), Growth: D(g0)}, {Amount: D(a1), Growth: D(g1)}}
+	result, err := Simulate(tiers, seconds, constant(r))
+	if err != nil || result.Method != SIMULATE_ADAPTIVE {
+		t.Fatalf("Simulate = method %d, %v", result.Method, err)
+	}
+	want0, want1 := twoTiers(a0, a1, r, g0, g1, seconds)
+	if err := relativeError(result.Amounts[0], want0); err > 1e-8 {
+		t.Errorf("amount 0 = %s, want %g", result.Amounts[0].ToString(), want0)
+	}
+	if err := relativeError(result.Amounts[1], want1); err > 1e-12 {
+		t.Errorf("amount 1 = %s, want %g", result.Amounts[1].ToString(), want1)
+	}
+
+	// A rate of t: amount0 = a0 + a1*t²/2
+	opts := DefaultSimulateOptions()
+	opts.RateFunc = func(tier int, t float64, amounts []*Decimal) *Decimal { return D(t) }
+	result, err = Simulate([]ProducerTier{{Amount: D(a0)}, {Amount: D(a1)}}, seconds, opts)
+	if want := a0 + a1*seconds*seconds/2; err != nil || relativeError(result.Amounts[0], want) > 1e-8 {
+		t.Errorf("amount 0 with a rate of t = %s, %v, want %g", result.Amounts[0].ToString(), err, want)
+	}
+
+	// Growth is exact whatever the duration: 3 days at 10% per second is e^25920, in a handful of steps
+	days := 3 * 86400.
+	result, err = Simulate([]ProducerTier{{Amount: D(1), Growth: D(0.1)}, {Amount: D(1), Growth: D(0.1)}}, days, constant(1))
+	if err != nil {
+		t.Fatalf("Simulate for 3 days at growth 0.1 returned %v after %d steps", err, result.Steps)
+	}
+	// amount0 = e^(g*t) * (1 + t)
+	wantLog := (0.1*days + math.Log(1+days)) / math.Ln10
+	if got := result.Amounts[0].Log10().ToFloat64(); math.Abs(got-wantLog) > 1e-9*wantLog {
+		t.Errorf("log10(amount 0) after 3 days = %.12g, want %.12g", got, wantLog)
+	}
+	if result.Steps > 100 {
+		t.Errorf("3 days at growth 0.1 took %d steps", result.Steps)
+	}
+}
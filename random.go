@@ -0,0 +1,118 @@
+package breaketernity
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// The random functions draw every number from the given Source, so the same seed always gives the same results,
+// e.g. RandomDecimalForTesting(rand.NewPCG(1, 2), 3).
+
+// RandomDecimalForTesting returns a random Decimal with a layer between 0 and maxLayers.
+// Like break_eternity.js's randomDecimalForTesting, this doesn't follow any kind of sane distribution:
+// it is meant to produce a variety of edge cases for tests, such as zeros, exact powers of 10 and integer mags.
+func RandomDecimalForTesting(src rand.Source, maxLayers int) *Decimal {
+	r := rand.New(src)
+	// 5% of the time, return 0
+	if r.Float64()*20 < 1 {
+		return dFC_NN(0, 0, 0)
+	}
+	randomLayer := math.Floor(r.Float64() * float64(maxLayers+1))
+	var randomExp float64
+	if randomLayer == 0 {
+		randomExp = r.Float64()*616 - 308
+	} else {
+		randomExp = r.Float64() * 16
+	}
+	// 10% of the time, make it a simple power of 10
+	if r.Float64() > 0.9 {
+		randomExp = math.Trunc(randomExp)
+	}
+	randomMag := math.Pow(10, randomExp)
+	// 10% of the time, trunc mag
+	if r.Float64() > 0.9 {
+		randomMag = math.Trunc(randomMag)
+	}
+	randomSign := -1.
+	if r.Float64() > 0.5 {
+		randomSign = 1
+	}
+	return dFC(randomSign, randomLayer, randomMag)
+}
+
+// Uniform returns a random Decimal uniformly distributed between a and b in linear space.
+// Between huge bounds, almost every result has the magnitude of the larger bound, use LogUniform or SlogUniform instead.
+func Uniform[DS DecimalSource](src rand.Source, a DS, b DS) *Decimal {
+	return uniformBetween(rand.New(src), D(a), D(b))
+}
+
+// LogUniform returns a random Decimal between a and b whose logarithm is uniformly distributed,
+// so that every order of magnitude is equally likely. Both bounds must be positive, otherwise the result is NaN.
+func LogUniform[DS DecimalSource](src rand.Source, a DS, b DS) *Decimal {
+	da, db := D(a), D(b)
+	if da.sign <= 0 || db.sign <= 0 {
+		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	}
+	return uniformBetween(rand.New(src), da.Log10(), db.Log10()).PowBase10()
+}
+
+// SlogUniform returns a random Decimal between a and b whose super-logarithm in base 10 is uniformly distributed,
+// so that every layer is equally likely. Both bounds must be positive, otherwise the result is NaN.
+func SlogUniform[DS DecimalSource](src rand.Source, a DS, b DS) *Decimal {
+	da, db := D(a), D(b)
+	if da.sign <= 0 || db.sign <= 0 {
+		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	}
+	base := D(10)
+	la := da.Slog(base, DEFAULT_SLOG_ITERATIONS, false).ToFloat64()
+	lb := db.Slog(base, DEFAULT_SLOG_ITERATIONS, false).ToFloat64()
+	height := la + rand.New(src).Float64()*(lb-la)
+	return base.Tetrate(height, dOne, false)
+}
+
+// WeightedChoice returns a random index into weights, where each index is chosen with a probability proportional to its weight.
+// Weights so small compared to the largest one that they vanish in a float64 are never chosen.
+// Returns -1 if there are no weights, if they are all 0, or if any of them is negative or NaN.
+func WeightedChoice(src rand.Source, weights []*Decimal) int {
+	largest := dFC_NN(0, 0, 0)
+	for _, w := range weights {
+		if w.IsNaN() || w.sign < 0 {
+			return -1
+		}
+		if w.Gt(largest) {
+			largest = w
+		}
+	}
+	if largest.sign == 0 {
+		return -1
+	}
+	ratios := make([]float64, len(weights))
+	total := 0.
+	for i, w := range weights {
+		ratios[i] = w.Divide(largest).ToFloat64()
+		total += ratios[i]
+	}
+	target := rand.New(src).Float64() * total
+	last := 0
+	for i, ratio := range ratios {
+		if ratio == 0 {
+			continue
+		}
+		if target < ratio {
+			return i
+		}
+		target -= ratio
+		last = i
+	}
+	// Only reached through rounding in the running subtraction
+	return last
+}
+
+func uniformBetween(r *rand.Rand, a *Decimal, b *Decimal) *Decimal {
+	u := r.Float64()
+	if a.layer == 0 && b.layer == 0 {
+		fa, fb := a.sign*a.mag, b.sign*b.mag
+		return decimalFromFloat64(fa + u*(fb-fa))
+	}
+	return a.Add(b.Subtract(a).Multiply(decimalFromFloat64(u)))
+}
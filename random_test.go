@@ -0,0 +1,128 @@
+package breaketernity
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+const randomDraws = 2000
+
+func TestRandomIsReproducible(t *testing.T) {
+	draws := map[string]func(src rand.Source) *Decimal{
+		"RandomDecimalForTesting": func(src rand.Source) *Decimal { return RandomDecimalForTesting(src, 3) },
+		"Uniform":                 func(src rand.Source) *Decimal { return Uniform(src, "-5", "1e400") },
+		"LogUniform":              func(src rand.Source) *Decimal { return LogUniform(src, "1e-10", "1e400") },
+		"SlogUniform":             func(src rand.Source) *Decimal { return SlogUniform(src, "2", "ee100") },
+		"WeightedChoice": func(src rand.Source) *Decimal {
+			return D(WeightedChoice(src, []*Decimal{D(1), D(2), D("1e-5")}))
+		},
+	}
+	for name, draw := range draws {
+		first, second, other := rand.NewPCG(1, 2), rand.NewPCG(1, 2), rand.NewPCG(3, 4)
+		differs := false
+		for range 20 {
+			a, b, c := draw(first), draw(second), draw(other)
+			if !a.Eq(b) {
+				t.Errorf("%s gave %s and %s from the same seed", name, a.ToString(), b.ToString())
+			}
+			differs = differs || !a.Eq(c)
+		}
+		if !differs {
+			t.Errorf("%s gave the same 20 results from two seeds", name)
+		}
+	}
+}
+
+// between returns true if x is between lo and hi, allowing for the rounding of the result's last digit
+func between(x *Decimal, lo *Decimal, hi *Decimal) bool {
+	return (x.Gte(lo) || x.EqTolerance(lo, 1e-12)) && (x.Lte(hi) || x.EqTolerance(hi, 1e-12))
+}
+
+func TestRandomBounds(t *testing.T) {
+	cases := []struct {
+		name   string
+		draw   func(src rand.Source) *Decimal
+		lo, hi *Decimal
+	}{
+		{"Uniform(1, 2)", func(src rand.Source) *Decimal { return Uniform(src, 1, 2) }, D(1), D(2)},
+		{"Uniform(3, -3)", func(src rand.Source) *Decimal { return Uniform(src, 3, -3) }, D(-3), D(3)},
+		{"Uniform(-5, 1e400)", func(src rand.Source) *Decimal { return Uniform(src, "-5", "1e400") }, D(-5), D("1e400")},
+		{"Uniform(1e400, 2e400)", func(src rand.Source) *Decimal { return Uniform(src, "1e400", "2e400") }, D("1e400"), D("2e400")},
+		{"LogUniform(1e-10, 1e400)", func(src rand.Source) *Decimal { return LogUniform(src, "1e-10", "1e400") }, D(1e-10), D("1e400")},
+		{"LogUniform(2, 3)", func(src rand.Source) *Decimal { return LogUniform(src, 2, 3) }, D(2), D(3)},
+		{"SlogUniform(2, ee100)", func(src rand.Source) *Decimal { return SlogUniform(src, "2", "ee100") }, D(2), D("ee100")},
+		{"SlogUniform(1e10, eee10)", func(src rand.Source) *Decimal { return SlogUniform(src, "1e10", "eee10") }, D(1e10), D("eee10")},
+	}
+	for _, c := range cases {
+		src := rand.NewPCG(5, 6)
+		for range randomDraws {
+			if x := c.draw(src); !between(x, c.lo, c.hi) {
+				t.Errorf("%s = %s", c.name, x.ToString())
+				break
+			}
+		}
+	}
+	for _, bounds := range [][2]float64{{-1, 10}, {0, 10}, {10, -1}} {
+		if got := LogUniform(rand.NewPCG(1, 2), bounds[0], bounds[1]); !got.IsNaN() {
+			t.Errorf("LogUniform(%v, %v) = %s, want NaN", bounds[0], bounds[1], got.ToString())
+		}
+		if got := SlogUniform(rand.NewPCG(1, 2), bounds[0], bounds[1]); !got.IsNaN() {
+			t.Errorf("SlogUniform(%v, %v) = %s, want NaN", bounds[0], bounds[1], got.ToString())
+		}
+	}
+}
+
+func TestLogUniformSpreadsOverOrdersOfMagnitude(t *testing.T) {
+	// Half of the orders of magnitude between 1 and 1e400 are below 1e200, where Uniform would almost never go
+	src := rand.NewPCG(7, 8)
+	below := 0
+	for range randomDraws {
+		if LogUniform(src, "1", "1e400").Lt(D("1e200")) {
+			below++
+		}
+	}
+	if below < randomDraws*45/100 || below > randomDraws*55/100 {
+		t.Errorf("%d of %d LogUniform(1, 1e400) draws are below 1e200, want about half", below, randomDraws)
+	}
+}
+
+func TestWeightedChoice(t *testing.T) {
+	cases := []struct {
+		weights []*Decimal
+		// The expected share of every index, or nil if the result is -1
+		shares []float64
+	}{
+		{[]*Decimal{D(1), D(3)}, []float64{0.25, 0.75}},
+		{[]*Decimal{D(0), D(2), D(0), D(2)}, []float64{0, 0.5, 0, 0.5}},
+		{[]*Decimal{D("1e1000"), D("3e1000")}, []float64{0.25, 0.75}},
+		{[]*Decimal{D("1e-400"), D(1)}, []float64{0, 1}},
+		{[]*Decimal{D(5)}, []float64{1}},
+		{nil, nil},
+		{[]*Decimal{D(0), D(0)}, nil},
+		{[]*Decimal{D(1), D(-1)}, nil},
+		{[]*Decimal{D(1), dFC_NN(0, 0, 0).Divide(dFC_NN(0, 0, 0))}, nil},
+	}
+	for _, c := range cases {
+		src := rand.NewPCG(9, 10)
+		counts := make([]int, len(c.weights))
+		for range randomDraws {
+			i := WeightedChoice(src, c.weights)
+			if c.shares == nil {
+				if i != -1 {
+					t.Errorf("%s = %d, want -1", differentialCase("WeightedChoice", c.weights), i)
+				}
+				break
+			}
+			if i < 0 || i >= len(c.weights) {
+				t.Fatalf("%s = %d", differentialCase("WeightedChoice", c.weights), i)
+			}
+			counts[i]++
+		}
+		for i, share := range c.shares {
+			got := float64(counts[i]) / randomDraws
+			if (share == 0) != (counts[i] == 0) || got < share-0.05 || got > share+0.05 {
+				t.Errorf("%s chose %d %d times out of %d, want a share of %v", differentialCase("WeightedChoice", c.weights), i, counts[i], randomDraws, share)
+			}
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package breaketernity
+
+import "math"
+
+// Sample is the value of a quantity at a point in time, in seconds.
+type Sample struct {
+	Time  float64
+	Value *Decimal
+}
+
+// The rate functions take samples in increasing order of time and return the slope of the least-squares line
+// through them, which smooths out the noise of every sample in the window. They return NaN with fewer than two
+// samples, or when all the samples are at the same time.
+
+// LinearRate returns how much the value changes per second: the slope of value over time
+func LinearRate(samples []Sample) *Decimal {
+	weights, ok := rateWeights(samples)
+	if !ok {
+		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	}
+	terms := make([]*Decimal, len(samples))
+	for i, s := range samples {
+		terms[i] = s.Value.Multiply(decimalFromFloat64(weights[i]))
+	}
+	return sumDecimals(terms)
+}
+
+// LogRate returns how many orders of magnitude the value grows by per second: the slope of log10(value) over time.
+// The values must be positive, otherwise the result is NaN.
+func LogRate(samples []Sample) float64 {
+	weights, ok := rateWeights(samples)
+	if !ok {
+		return math.NaN()
+	}
+	// The logarithms are taken relative to the last one, so that large ones keep their differences in a float64
+	last := samples[len(samples)-1].Value.Log10()
+	rate := 0.
+	for i, s := range samples {
+		if s.Value.sign <= 0 {
+			return math.NaN()
+		}
+		rate += weights[i] * s.Value.Log10().Subtract(last).ToFloat64()
+	}
+	return rate
+}
+
+// SlogRate returns how many layers the value grows by per second: the slope of slog10(value) over time.
+// The values must be positive, otherwise the result is NaN.
+func SlogRate(samples []Sample) float64 {
+	weights, ok := rateWeights(samples)
+	if !ok {
+		return math.NaN()
+	}
+	rate := 0.
+	for i, s := range samples {
+		if s.Value.sign <= 0 {
+			return math.NaN()
+		}
+		rate += weights[i] * slog10(s.Value)
+	}
+	return rate
+}
+
+// TimeToReach estimates how many seconds until the value reaches goal, assuming its rate stays constant in the given space:
+// a constant linear rate, a constant number of orders of magnitude per second, or a constant number of layers per second.
+// SPACE_AUTO picks the space with AutoSpace from the last value and the goal.
+// Returns 0 if the goal is already reached, +Inf if the value isn't growing, and NaN with fewer than two samples.
+func TimeToReach(samples []Sample, goal *Decimal, space Space) float64 {
+	if _, ok := rateWeights(samples); !ok {
+		return math.NaN()
+	}
+	last := samples[len(samples)-1]
+	if last.Value.Gte(goal) {
+		return 0
+	}
+	if space == SPACE_AUTO {
+		space = autoSpace(last.Value, goal)
+	}
+
+	var remaining, rate float64
+	switch space {
+	case SPACE_LOG:
+		rate = LogRate(samples)
+		remaining = goal.Log10().Subtract(last.Value.Log10()).ToFloat64()
+	case SPACE_SLOG:
+		rate = SlogRate(samples)
+		remaining = slog10(goal) - slog10(last.Value)
+	default:
+		linearRate := LinearRate(samples)
+		if linearRate.sign <= 0 {
+			return math.Inf(1)
+		}
+		return goal.Subtract(last.Value).Divide(linearRate).ToFloat64()
+	}
+	if math.IsNaN(rate) || math.IsNaN(remaining) {
+		return math.NaN()
+	}
+	if rate <= 0 {
+		return math.Inf(1)
+	}
+	return remaining / rate
+}
+
+// rateWeights returns the weights w for which the sum of w[i] * y[i] is the slope of the least-squares line
+// through the points (samples[i].Time, y[i]): (t[i] - mean(t)) / Σ(t - mean(t))².
+func rateWeights(samples []Sample) ([]float64, bool) {
+	if len(samples) < 2 {
+		return nil, false
+	}
+	mean := 0.
+	for _, s := range samples {
+		mean += s.Time
+	}
+	mean /= float64(len(samples))
+	weights := make([]float64, len(samples))
+	variance := 0.
+	for i, s := range samples {
+		weights[i] = s.Time - mean
+		variance += weights[i] * weights[i]
+	}
+	if !(variance > 0) {
+		return nil, false
+	}
+	for i := range weights {
+		weights[i] /= variance
+	}
+	return weights, true
+}
+
+func slog10(d *Decimal) float64 {
+	return d.Slog(D(10), DEFAULT_SLOG_ITERATIONS, false).ToFloat64()
+}
+
+// RateTracker keeps the samples of a quantity over a sliding window of time, to display its current rates.
+// A longer window gives smoother but slower to react rates.
+type RateTracker struct {
+	window  float64
+	samples []Sample
+}
+
+// NewRateTracker creates a new RateTracker keeping the samples of the last window seconds.
+func NewRateTracker(window float64) *RateTracker {
+	return &RateTracker{window: window}
+}
+
+// Add records the value at the given time, which must not be earlier than the previous one,
+// and forgets the samples that left the window. The two most recent samples are always kept.
+func (r *RateTracker) Add(time float64, value *Decimal) {
+	r.samples = append(r.samples, Sample{Time: time, Value: decimalFromDecimal(value)})
+	drop := 0
+	for drop < len(r.samples)-2 && r.samples[drop].Time < time-r.window {
+		drop++
+	}
+	r.samples = r.samples[drop:]
+}
+
+// Samples returns the samples currently in the window, oldest first
+func (r *RateTracker) Samples() []Sample {
+	return append([]Sample(nil), r.samples...)
+}
+
+// LinearRate returns how much the value changes per second over the window
+func (r *RateTracker) LinearRate() *Decimal {
+	return LinearRate(r.samples)
+}
+
+// LogRate returns how many orders of magnitude the value grows by per second over the window
+func (r *RateTracker) LogRate() float64 {
+	return LogRate(r.samples)
+}
+
+// SlogRate returns how many layers the value grows by per second over the window
+func (r *RateTracker) SlogRate() float64 {
+	return SlogRate(r.samples)
+}
+
+// TimeToReach estimates how many seconds until the value reaches goal, see TimeToReach
+func (r *RateTracker) TimeToReach(goal *Decimal, space Space) float64 {
+	return TimeToReach(r.samples, goal, space)
+}
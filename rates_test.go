@@ -0,0 +1,91 @@
+package breaketernity
+
+import (
+	"math"
+	"testing"
+)
+
+// noisySamples returns samples at times 0, 1, 2 and 3 whose value maps to 0, 2, 1 and 3 through f.
+// The least-squares slope of those is 0.8, while the first and last samples alone give 1.
+func noisySamples(f func(y float64) *Decimal) []Sample {
+	samples := make([]Sample, 4)
+	for i, y := range []float64{0, 2, 1, 3} {
+		samples[i] = Sample{Time: float64(i), Value: f(y)}
+	}
+	return samples
+}
+
+func TestRatesFitEverySample(t *testing.T) {
+	linear := noisySamples(func(y float64) *Decimal { return D(y) })
+	if got := LinearRate(linear); !got.EqTolerance(D(0.8), 1e-12) {
+		t.Errorf("LinearRate = %s, want 0.8", got.ToString())
+	}
+	// Values far out of float64 range keep the slope of their logarithms
+	orders := noisySamples(func(y float64) *Decimal { return D(10).Pow(D(1e6 + y)) })
+	if got := LogRate(orders); math.Abs(got-0.8) > 1e-9 {
+		t.Errorf("LogRate = %v, want 0.8", got)
+	}
+	huge := noisySamples(func(y float64) *Decimal { return D(10).Pow(D(1e300).Add(D(y).Multiply(D(1e290)))) })
+	// The last value is so much larger than the others that the slope is its weight, (3 - 1.5) / 5, times it
+	if got, want := LinearRate(huge), huge[3].Value.Multiply(D(0.3)); !got.EqTolerance(want, 1e-12) {
+		t.Errorf("LinearRate = %s, want %s", got.ToString(), want.ToString())
+	}
+	layers := noisySamples(func(y float64) *Decimal { return D(10).Tetrate(2+y, dOne, false) })
+	if got := SlogRate(layers); math.Abs(got-0.8) > 1e-6 {
+		t.Errorf("SlogRate = %v, want 0.8", got)
+	}
+}
+
+func TestRatesOfExactGrowth(t *testing.T) {
+	var samples []Sample
+	for i := 0; i < 10; i++ {
+		samples = append(samples, Sample{Time: 1e6 + float64(i)*0.5, Value: D(3 * float64(i) * 0.5)})
+	}
+	if got := LinearRate(samples); !got.EqTolerance(D(3), 1e-9) {
+		t.Errorf("LinearRate = %s, want 3", got.ToString())
+	}
+	if got := TimeToReach(samples, D(30), SPACE_LINEAR); math.Abs(got-(30-13.5)/3) > 1e-6 {
+		t.Errorf("TimeToReach(30) = %v, want %v", got, (30-13.5)/3)
+	}
+	if got := TimeToReach(samples, D(10), SPACE_LINEAR); got != 0 {
+		t.Errorf("TimeToReach(10) = %v, want 0", got)
+	}
+}
+
+func TestRatesNaN(t *testing.T) {
+	cases := map[string][]Sample{
+		"no samples":       nil,
+		"one sample":       {{Time: 0, Value: D(1)}},
+		"at the same time": {{Time: 1, Value: D(1)}, {Time: 1, Value: D(2)}},
+	}
+	for name, samples := range cases {
+		if got := LinearRate(samples); !got.IsNaN() {
+			t.Errorf("LinearRate with %s = %s, want NaN", name, got.ToString())
+		}
+		if got := LogRate(samples); !math.IsNaN(got) {
+			t.Errorf("LogRate with %s = %v, want NaN", name, got)
+		}
+		if got := TimeToReach(samples, D(10), SPACE_LINEAR); !math.IsNaN(got) {
+			t.Errorf("TimeToReach with %s = %v, want NaN", name, got)
+		}
+	}
+	negative := []Sample{{Time: 0, Value: D(-1)}, {Time: 1, Value: D(5)}}
+	if got := LogRate(negative); !math.IsNaN(got) {
+		t.Errorf("LogRate with a negative value = %v, want NaN", got)
+	}
+}
+
+func TestRateTrackerWindow(t *testing.T) {
+	r := NewRateTracker(5)
+	for i := 0; i <= 20; i++ {
+		r.Add(float64(i), D(float64(i*i)))
+	}
+	samples := r.Samples()
+	if len(samples) != 6 || samples[0].Time != 15 {
+		t.Fatalf("RateTracker kept %d samples from time %v, want 6 from time 15", len(samples), samples[0].Time)
+	}
+	// The slope of i² over 15..20 is 2 * 17.5
+	if got := r.LinearRate(); !got.EqTolerance(D(35), 1e-12) {
+		t.Errorf("RateTracker.LinearRate = %s, want 35", got.ToString())
+	}
+}
@@ -0,0 +1,110 @@
+package breaketernity
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrNotBracketed = errors.New("breaketernity: f(lower) and f(upper) are on the same side of the target")
+
+var ErrNoConvergence = errors.New("breaketernity: solver did not converge")
+
+// SolveOptions controls how Solve searches for a solution.
+// Space is the space in which the interval between lower and upper is searched, SPACE_AUTO picks it with AutoSpace.
+// Tolerance is the width, as a fraction of that interval, below which the search stops.
+type SolveOptions struct {
+	Space         Space
+	MaxIterations int
+	Tolerance     float64
+}
+
+// DefaultSolveOptions returns options searching in the automatically chosen space, for up to 200 iterations, to a tolerance of 1e-12.
+func DefaultSolveOptions() SolveOptions {
+	return SolveOptions{Space: SPACE_AUTO, MaxIterations: 200, Tolerance: 1e-12}
+}
+
+// SolveResult is the solution found by Solve.
+// Error is the width of the final bracket around the solution, as a fraction of the interval between lower and upper
+// in the space that was searched.
+type SolveResult struct {
+	X          *Decimal
+	Iterations int
+	Error      float64
+}
+
+// Solve finds x between lower and upper such that f(x) = target. f must be continuous,
+// and f(lower) and f(upper) must be on opposite sides of the target, otherwise ErrNotBracketed is returned.
+// The interval is searched in linear, log or slog space, and the distance to the target is measured in the space
+// that suits f(lower) and f(upper), so the search converges even when x or f(x) spans several layers.
+// It uses the secant method, falling back to bisection whenever the secant step leaves the bracket or stalls.
+// Returns ErrNoConvergence, along with the best solution found, if the tolerance isn't reached within MaxIterations.
+func Solve[DS DecimalSource](f func(*Decimal) *Decimal, target DS, lower DS, upper DS, opts SolveOptions) (SolveResult, error) {
+	dTarget, dLower, dUpper := D(target), D(lower), D(upper)
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = 200
+	}
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = 1e-12
+	}
+
+	fLower, fUpper := f(dLower), f(dUpper)
+	cmpLower, cmpUpper := fLower.Cmp(dTarget), fUpper.Cmp(dTarget)
+	if cmpLower == 0 {
+		return SolveResult{X: dLower, Error: 0}, nil
+	}
+	if cmpUpper == 0 {
+		return SolveResult{X: dUpper, Error: 0}, nil
+	}
+	if cmpLower == cmpUpper || fLower.IsNaN() || fUpper.IsNaN() {
+		return SolveResult{X: dFC_NN(math.NaN(), math.NaN(), math.NaN())}, ErrNotBracketed
+	}
+
+	inputs := newLerper(opts.Space, dLower, dUpper)
+	outputs := newLerper(SPACE_AUTO, fLower, fUpper)
+	targetFraction := outputs.fraction(dTarget)
+	// The residual is the signed distance between f(x) and the target, as a fraction of [f(lower), f(upper)]
+	residual := func(y *Decimal) float64 {
+		return outputs.fraction(y) - targetFraction
+	}
+
+	// t is the position of x in [lower, upper] in the searched space. The bracket [a, b] always has f(a) and f(b)
+	// on opposite sides of the target, with cmpA being the side of f(a).
+	a, b := 0., 1.
+	ra, rb := residual(fLower), residual(fUpper)
+	cmpA := cmpLower
+	lastSide := 0
+	for i := 1; i <= opts.MaxIterations; i++ {
+		t := a - ra*(b-a)/(rb-ra)
+		if math.IsNaN(t) || t <= a || t >= b {
+			t = (a + b) / 2
+		}
+		x := inputs.at(t)
+		y := f(x)
+		cmp := y.Cmp(dTarget)
+		if cmp == 0 {
+			return SolveResult{X: x, Iterations: i, Error: 0}, nil
+		}
+		r := residual(y)
+		if math.IsNaN(r) {
+			r = 0
+		}
+		// Illinois variant: when the same end is kept twice in a row, halve its residual so the secant step moves it
+		if cmp == cmpA {
+			a, ra = t, r
+			if lastSide == -1 {
+				rb /= 2
+			}
+			lastSide = -1
+		} else {
+			b, rb = t, r
+			if lastSide == 1 {
+				ra /= 2
+			}
+			lastSide = 1
+		}
+		if b-a < opts.Tolerance {
+			return SolveResult{X: inputs.at((a + b) / 2), Iterations: i, Error: b - a}, nil
+		}
+	}
+	return SolveResult{X: inputs.at((a + b) / 2), Iterations: opts.MaxIterations, Error: b - a}, ErrNoConvergence
+}
@@ -0,0 +1,83 @@
+package breaketernity
+
+import (
+	"errors"
+	"testing"
+)
+
+// costOf is the example cost formula without a closed form: x^x * 1e5 + 3^x
+func costOf(x *Decimal) *Decimal {
+	return x.Pow(x).Multiply(D(1e5)).Add(D(3).Pow(x))
+}
+
+func TestSolve(t *testing.T) {
+	cases := []struct {
+		name         string
+		f            func(*Decimal) *Decimal
+		target       *Decimal
+		lower, upper *Decimal
+		space        Space
+	}{
+		{"x^x * 1e5 + 3^x = 1e10", costOf, D(1e10), D(1), D(100), SPACE_AUTO},
+		{"x^x * 1e5 + 3^x = 1e1000", costOf, D("1e1000"), D(1), D("1e300"), SPACE_AUTO},
+		{"x^x * 1e5 + 3^x = ee10", costOf, D("ee10"), D(1), D("1e300"), SPACE_LOG},
+		{"x^2 = 2", func(x *Decimal) *Decimal { return x.Multiply(x) }, D(2), D(0), D(2), SPACE_LINEAR},
+		{"1 / x = 0.25", (*Decimal).Recip, D(0.25), D(1), D(10), SPACE_AUTO},
+		{"10^x = ee100", (*Decimal).PowBase10, D("ee100"), D(1), D("1e1000"), SPACE_SLOG},
+	}
+	for _, c := range cases {
+		opts := DefaultSolveOptions()
+		opts.Space = c.space
+		result, err := Solve(c.f, c.target, c.lower, c.upper, opts)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if result.Error > opts.Tolerance || result.Iterations > opts.MaxIterations {
+			t.Errorf("%s: stopped after %d iterations with an error of %g", c.name, result.Iterations, result.Error)
+		}
+		if !result.X.Gte(c.lower) || !result.X.Lte(c.upper) {
+			t.Errorf("%s: x = %s is outside of [%s, %s]", c.name, result.X.ToString(), c.lower.ToString(), c.upper.ToString())
+		}
+		// Compared in log space, where the results of the formulas above span several layers
+		if got := c.f(result.X); !got.Log10().EqTolerance(c.target.Log10(), 1e-9) {
+			t.Errorf("%s: f(%s) = %s", c.name, result.X.ToString(), got.ToString())
+		}
+	}
+}
+
+func TestSolveKnownRoots(t *testing.T) {
+	result, err := Solve(func(x *Decimal) *Decimal { return x.Multiply(x) }, 2, 0, 2, DefaultSolveOptions())
+	if err != nil || !result.X.EqTolerance(D(1.4142135623730951), 1e-11) {
+		t.Errorf("Solve(x^2 = 2) = %s, %v, want sqrt(2)", result.X.ToString(), err)
+	}
+	result, err = Solve((*Decimal).PowBase10, "ee100", "1", "1e1000", DefaultSolveOptions())
+	if err != nil || !result.X.EqTolerance(D("1e100"), 1e-9) {
+		t.Errorf("Solve(10^x = ee100) = %s, %v, want 1e100", result.X.ToString(), err)
+	}
+}
+
+func TestSolveErrors(t *testing.T) {
+	square := func(x *Decimal) *Decimal { return x.Multiply(x) }
+
+	result, err := Solve(square, -1, 0, 2, DefaultSolveOptions())
+	if !errors.Is(err, ErrNotBracketed) || !result.X.IsNaN() {
+		t.Errorf("Solve(x^2 = -1) = %s, %v, want NaN, ErrNotBracketed", result.X.ToString(), err)
+	}
+
+	result, err = Solve(square, 4, 2, 5, DefaultSolveOptions())
+	if err != nil || result.X.Neq(D(2)) || result.Iterations != 0 || result.Error != 0 {
+		t.Errorf("Solve(x^2 = 4) on [2, 5] = %s after %d iterations, %v, want the lower bound", result.X.ToString(), result.Iterations, err)
+	}
+
+	opts := DefaultSolveOptions()
+	opts.MaxIterations = 3
+	result, err = Solve(costOf, "1e1000", "1", "1e300", opts)
+	if !errors.Is(err, ErrNoConvergence) || result.Iterations != 3 || !(result.Error > opts.Tolerance) {
+		t.Errorf("Solve with 3 iterations = %s after %d iterations with an error of %g, %v, want ErrNoConvergence",
+			result.X.ToString(), result.Iterations, result.Error, err)
+	}
+	if !result.X.Gte(D(1)) || !result.X.Lte(D("1e300")) {
+		t.Errorf("Solve with 3 iterations = %s, want the best solution in [1, 1e300]", result.X.ToString())
+	}
+}
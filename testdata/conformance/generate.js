@@ -0,0 +1,135 @@
+// Regenerates the expected values of the conformance fixtures from break_eternity.js.
+//
+//   npm install break_eternity.js
+//   node testdata/conformance/generate.js [--check]
+//
+// The operations and arguments of every case are kept, only "expected" is rewritten.
+// With --check, nothing is written: the cases whose expected value would change are listed instead.
+// Cases marked with a "divergence" keep the break_eternity.js result, the Go test reports them separately.
+
+const fs = require("fs");
+const path = require("path");
+const Decimal = require("break_eternity.js");
+
+function toNumber(v) {
+  if (v === "Infinity") return Infinity;
+  if (v === "-Infinity") return -Infinity;
+  if (v === "NaN") return NaN;
+  return v;
+}
+
+function toDecimal(v) {
+  if (typeof v === "object") {
+    return Decimal.fromComponents_noNormalize(toNumber(v.sign), toNumber(v.layer), toNumber(v.mag));
+  }
+  return new Decimal(v);
+}
+
+function jsonNumber(x) {
+  if (Number.isNaN(x)) return "NaN";
+  if (x === Infinity) return "Infinity";
+  if (x === -Infinity) return "-Infinity";
+  return x;
+}
+
+// Each operation maps the Go method name to its break_eternity.js call, with the receiver first
+const d = toDecimal;
+const n = toNumber;
+const ops = {
+  D: (a) => d(a[0]),
+  Cmp: (a) => d(a[0]).cmp(d(a[1])),
+  CmpAbs: (a) => d(a[0]).cmpabs(d(a[1])),
+  Eq: (a) => d(a[0]).eq(d(a[1])),
+  Neq: (a) => d(a[0]).neq(d(a[1])),
+  Lt: (a) => d(a[0]).lt(d(a[1])),
+  Lte: (a) => d(a[0]).lte(d(a[1])),
+  Gt: (a) => d(a[0]).gt(d(a[1])),
+  Gte: (a) => d(a[0]).gte(d(a[1])),
+  Max: (a) => d(a[0]).max(d(a[1])),
+  Min: (a) => d(a[0]).min(d(a[1])),
+  MaxAbs: (a) => d(a[0]).maxabs(d(a[1])),
+  MinAbs: (a) => d(a[0]).minabs(d(a[1])),
+  Clamp: (a) => d(a[0]).clamp(d(a[1]), d(a[2])),
+  ClampMin: (a) => d(a[0]).clampMin(d(a[1])),
+  ClampMax: (a) => d(a[0]).clampMax(d(a[1])),
+  EqTolerance: (a) => d(a[0]).eq_tolerance(d(a[1]), n(a[2])),
+  CmpTolerance: (a) => d(a[0]).cmp_tolerance(d(a[1]), n(a[2])),
+  NeqTolerance: (a) => d(a[0]).neq_tolerance(d(a[1]), n(a[2])),
+  LtTolerance: (a) => d(a[0]).lt_tolerance(d(a[1]), n(a[2])),
+  LteTolerance: (a) => d(a[0]).lte_tolerance(d(a[1]), n(a[2])),
+  GtTolerance: (a) => d(a[0]).gt_tolerance(d(a[1]), n(a[2])),
+  GteTolerance: (a) => d(a[0]).gte_tolerance(d(a[1]), n(a[2])),
+  IsNaN: (a) => Decimal.isNaN(d(a[0])),
+  IsInf: (a) => Decimal.isFinite(d(a[0])) === false && !Decimal.isNaN(d(a[0])),
+  ToFloat64: (a) => d(a[0]).toNumber(),
+  Abs: (a) => d(a[0]).abs(),
+  Neg: (a) => d(a[0]).neg(),
+  Round: (a) => d(a[0]).round(),
+  Floor: (a) => d(a[0]).floor(),
+  Ceil: (a) => d(a[0]).ceil(),
+  Trunc: (a) => d(a[0]).trunc(),
+  Add: (a) => d(a[0]).add(d(a[1])),
+  Subtract: (a) => d(a[0]).sub(d(a[1])),
+  Multiply: (a) => d(a[0]).mul(d(a[1])),
+  Divide: (a) => d(a[0]).div(d(a[1])),
+  Recip: (a) => d(a[0]).recip(),
+  Modulo: (a) => d(a[0]).mod(d(a[1])),
+  PLog10: (a) => d(a[0]).pLog10(),
+  AbsLog10: (a) => d(a[0]).absLog10(),
+  Log10: (a) => d(a[0]).log10(),
+  Log: (a) => d(a[0]).log(d(a[1])),
+  Ln: (a) => d(a[0]).ln(),
+  Log2: (a) => d(a[0]).log2(),
+  Pow: (a) => d(a[0]).pow(d(a[1])),
+  PowBase10: (a) => Decimal.pow10(d(a[0])),
+  PowBaseE: (a) => d(a[0]).exp(),
+  PowBaseN: (a) => d(a[0]).pow_base(d(a[1])),
+  Root: (a) => d(a[0]).root(d(a[1])),
+  Sqrt: (a) => d(a[0]).sqrt(),
+  Gamma: (a) => d(a[0]).gamma(),
+  Factorial: (a) => d(a[0]).factorial(),
+  LambertW: (a) => d(a[0]).lambertw(a[1]),
+  IteratedLog: (a) => d(a[0]).iteratedlog(d(a[1]), n(a[2]), a[3]),
+  IteratedExp: (a) => d(a[0]).iteratedexp(n(a[1]), d(a[2]), a[3]),
+  LayerAdd10: (a) => d(a[0]).layeradd10(d(a[1]), a[2]),
+  LayerAdd: (a) => d(a[0]).layeradd(n(d(a[1]).toNumber()), d(a[2]), a[3]),
+  Slog: (a) => d(a[0]).slog(d(a[1]), n(a[2]), a[3]),
+  Tetrate: (a) => d(a[0]).tetrate(n(a[1]), d(a[2]), a[3]),
+  Pentate: (a) => d(a[0]).pentate(n(a[1]), d(a[2]), a[3]),
+};
+
+function expected(result) {
+  if (result instanceof Decimal) {
+    return { sign: jsonNumber(result.sign), layer: jsonNumber(result.layer), mag: jsonNumber(result.mag) };
+  }
+  if (typeof result === "boolean") return { bool: result };
+  if (typeof result === "number" && Number.isInteger(result) && Math.abs(result) <= 1) return { int: result };
+  return { number: jsonNumber(result) };
+}
+
+const check = process.argv.includes("--check");
+let changed = 0;
+for (const file of fs.readdirSync(__dirname).filter((f) => f.endsWith(".json")).sort()) {
+  const fixturePath = path.join(__dirname, file);
+  const fixture = JSON.parse(fs.readFileSync(fixturePath, "utf8"));
+  for (const c of fixture.cases) {
+    const op = ops[c.op];
+    if (!op) throw new Error(`${file}: unknown operation ${c.op}`);
+    const result = op(c.args);
+    // Only ToFloat64 returns a plain number, which may happen to be an integer
+    const value = c.op === "ToFloat64" ? { number: jsonNumber(result) } : expected(result);
+    if (check && JSON.stringify(value) !== JSON.stringify(c.expected)) {
+      changed++;
+      console.log(`${file}: ${c.op}(${JSON.stringify(c.args)}) is ${JSON.stringify(value)}, fixture has ${JSON.stringify(c.expected)}`);
+    }
+    c.expected = value;
+  }
+  if (check) continue;
+  const lines = fixture.cases.map((c) => "    " + JSON.stringify(c).replace(/,"/g, ', "').replace(/":/g, '": '));
+  fs.writeFileSync(fixturePath, '{\n  "cases": [\n' + lines.join(",\n") + "\n  ]\n}\n");
+  console.log(`${file}: ${fixture.cases.length} cases`);
+}
+if (check) {
+  console.log(`${changed} cases differ from break_eternity.js`);
+  process.exitCode = changed > 0 ? 1 : 0;
+}
@@ -43,6 +43,11 @@ func numberToFixedString(value float64, digits int) string {
 	return fmt.Sprintf(format, value)
 }
 
+// isFinite is JavaScript's Number.isFinite: false for infinities and NaN
+func isFinite(x float64) bool {
+	return !math.IsInf(x, 0) && !math.IsNaN(x)
+}
+
 func sign(x float64) float64 {
 	if x == 0 {
 		return 0
@@ -0,0 +1,209 @@
+package breaketernity
+
+import "math"
+
+// The vector functions apply the same operation to every element of slices of Decimal, writing into dst.
+// dst may be one of the inputs, and all slices must have the same length.
+// They don't allocate per element, and when every lane is in layer 0 they run as a plain float64 loop.
+
+func checkLengths(lengths ...int) {
+	for _, l := range lengths[1:] {
+		if l != lengths[0] {
+			panic("Mismatched vector lengths")
+		}
+	}
+}
+
+func allLayer0(a []Decimal) bool {
+	for i := range a {
+		if a[i].layer != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// setFloat64 stores f into d, normalizing in place only when f is outside of the layer 0 range.
+func setFloat64(d *Decimal, f float64) {
+	abs := math.Abs(f)
+	d.sign = sign(f)
+	d.layer = 0
+	d.mag = abs
+	if abs >= EXP_LIMIT || (abs < FIRST_NEG_LAYER && abs != 0) || math.IsNaN(f) {
+		d.Normalize()
+	}
+}
+
+// AddVec sets dst[i] = a[i] + b[i]
+func AddVec(dst, a, b []Decimal) {
+	checkLengths(len(dst), len(a), len(b))
+	if allLayer0(a) && allLayer0(b) {
+		for i := range dst {
+			setFloat64(&dst[i], a[i].sign*a[i].mag+b[i].sign*b[i].mag)
+		}
+		return
+	}
+	for i := range dst {
+		if a[i].layer == 0 && b[i].layer == 0 {
+			setFloat64(&dst[i], a[i].sign*a[i].mag+b[i].sign*b[i].mag)
+		} else {
+			dst[i] = *a[i].Add(&b[i])
+		}
+	}
+}
+
+// SubVec sets dst[i] = a[i] - b[i]
+func SubVec(dst, a, b []Decimal) {
+	checkLengths(len(dst), len(a), len(b))
+	if allLayer0(a) && allLayer0(b) {
+		for i := range dst {
+			setFloat64(&dst[i], a[i].sign*a[i].mag-b[i].sign*b[i].mag)
+		}
+		return
+	}
+	for i := range dst {
+		if a[i].layer == 0 && b[i].layer == 0 {
+			setFloat64(&dst[i], a[i].sign*a[i].mag-b[i].sign*b[i].mag)
+		} else {
+			negB := b[i]
+			negB.sign = -negB.sign
+			dst[i] = *a[i].Add(&negB)
+		}
+	}
+}
+
+// MulVec sets dst[i] = a[i] * b[i]
+func MulVec(dst, a, b []Decimal) {
+	checkLengths(len(dst), len(a), len(b))
+	if allLayer0(a) && allLayer0(b) {
+		for i := range dst {
+			setFloat64(&dst[i], a[i].sign*a[i].mag*b[i].sign*b[i].mag)
+		}
+		return
+	}
+	for i := range dst {
+		if a[i].layer == 0 && b[i].layer == 0 {
+			setFloat64(&dst[i], a[i].sign*a[i].mag*b[i].sign*b[i].mag)
+		} else {
+			dst[i] = *a[i].Multiply(&b[i])
+		}
+	}
+}
+
+// DivVec sets dst[i] = a[i] / b[i]
+func DivVec(dst, a, b []Decimal) {
+	checkLengths(len(dst), len(a), len(b))
+	for i := range dst {
+		if a[i].layer == 0 && b[i].layer == 0 && b[i].sign != 0 {
+			setFloat64(&dst[i], (a[i].sign*a[i].mag)/(b[i].sign*b[i].mag))
+		} else {
+			dst[i] = *a[i].Divide(&b[i])
+		}
+	}
+}
+
+// AddScalarVec sets dst[i] = a[i] + s
+func AddScalarVec(dst, a []Decimal, s *Decimal) {
+	checkLengths(len(dst), len(a))
+	if s.layer == 0 && allLayer0(a) {
+		f := s.sign * s.mag
+		for i := range dst {
+			setFloat64(&dst[i], a[i].sign*a[i].mag+f)
+		}
+		return
+	}
+	for i := range dst {
+		if a[i].layer == 0 && s.layer == 0 {
+			setFloat64(&dst[i], a[i].sign*a[i].mag+s.sign*s.mag)
+		} else {
+			dst[i] = *a[i].Add(s)
+		}
+	}
+}
+
+// MulScalarVec sets dst[i] = a[i] * s
+func MulScalarVec(dst, a []Decimal, s *Decimal) {
+	checkLengths(len(dst), len(a))
+	if s.layer == 0 && allLayer0(a) {
+		f := s.sign * s.mag
+		for i := range dst {
+			setFloat64(&dst[i], a[i].sign*a[i].mag*f)
+		}
+		return
+	}
+	for i := range dst {
+		if a[i].layer == 0 && s.layer == 0 {
+			setFloat64(&dst[i], a[i].sign*a[i].mag*s.sign*s.mag)
+		} else {
+			dst[i] = *a[i].Multiply(s)
+		}
+	}
+}
+
+// ClampVec sets dst[i] to a[i] clamped between min and max
+func ClampVec(dst, a []Decimal, min *Decimal, max *Decimal) {
+	checkLengths(len(dst), len(a))
+	for i := range dst {
+		if a[i].Lt(min) {
+			dst[i] = *min
+		} else if a[i].Gt(max) {
+			dst[i] = *max
+		} else {
+			dst[i] = a[i]
+		}
+	}
+}
+
+// CmpVec sets dst[i] to 1 if a[i] > b[i], -1 if a[i] < b[i], and 0 if a[i] == b[i]
+func CmpVec(dst []int, a, b []Decimal) {
+	checkLengths(len(dst), len(a), len(b))
+	for i := range dst {
+		dst[i] = a[i].Cmp(&b[i])
+	}
+}
+
+// SumVec returns the sum of all elements of a, added from first to last
+func SumVec(a []Decimal) *Decimal {
+	i := 0
+	sum := 0.
+	for ; i < len(a) && a[i].layer == 0; i++ {
+		next := sum + a[i].sign*a[i].mag
+		if math.IsInf(next, 0) {
+			break
+		}
+		sum = next
+	}
+	result := decimalFromFloat64(sum)
+	for ; i < len(a); i++ {
+		result = result.Add(&a[i])
+	}
+	return result
+}
+
+// MaxVec returns the largest element of a, or NaN if a is empty
+func MaxVec(a []Decimal) *Decimal {
+	if len(a) == 0 {
+		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	}
+	best := 0
+	for i := 1; i < len(a); i++ {
+		if a[i].Cmp(&a[best]) > 0 {
+			best = i
+		}
+	}
+	return decimalFromDecimal(&a[best])
+}
+
+// MinVec returns the smallest element of a, or NaN if a is empty
+func MinVec(a []Decimal) *Decimal {
+	if len(a) == 0 {
+		return dFC_NN(math.NaN(), math.NaN(), math.NaN())
+	}
+	best := 0
+	for i := 1; i < len(a); i++ {
+		if a[i].Cmp(&a[best]) < 0 {
+			best = i
+		}
+	}
+	return decimalFromDecimal(&a[best])
+}
@@ -0,0 +1,320 @@
+package breaketernity
+
+import (
+	"math"
+	"testing"
+)
+
+// vectorValues covers the layer 0 fast paths, results that leave layer 0 above EXP_LIMIT and below FIRST_NEG_LAYER,
+// the special values, and layers 1 and 2
+var vectorValues = []any{0, 1, -2.5, 6e15, -6e15, 1e8, 1e-8, -1e-9, math.Inf(1), math.NaN(), "1e1000", "-1e400", "ee50", "1e-500"}
+
+func vectorOf(values ...any) []Decimal {
+	v := make([]Decimal, len(values))
+	for i, value := range values {
+		switch value := value.(type) {
+		case int:
+			v[i] = *D(value)
+		case float64:
+			v[i] = *D(value)
+		case string:
+			v[i] = *D(value)
+		}
+	}
+	return v
+}
+
+// vectorPairs returns every pair of vectorValues, first as two vectors in layer 0 only, then as two vectors with
+// every value, so that both the fast path and the mixed-layer fallback are covered
+func vectorPairs() [][2][]Decimal {
+	var layer0 []any
+	for _, v := range vectorValues {
+		if _, ok := v.(string); !ok {
+			layer0 = append(layer0, v)
+		}
+	}
+	var pairs [][2][]Decimal
+	for _, values := range [][]any{layer0, vectorValues} {
+		var a, b []any
+		for _, x := range values {
+			for _, y := range values {
+				a, b = append(a, x), append(b, y)
+			}
+		}
+		pairs = append(pairs, [2][]Decimal{vectorOf(a...), vectorOf(b...)})
+	}
+	return pairs
+}
+
+// sameDecimal allows for DivVec dividing the float64s directly where Divide multiplies by the reciprocal,
+// but both must be normalized to the same layer
+func sameDecimal(a *Decimal, b *Decimal) bool {
+	if a.IsNaN() || b.IsNaN() {
+		return a.IsNaN() && b.IsNaN()
+	}
+	return a.layer == b.layer && (a.Eq(b) || a.EqTolerance(b, 1e-15))
+}
+
+func TestBinaryVecMatchesScalar(t *testing.T) {
+	ops := []struct {
+		name   string
+		vec    func(dst, a, b []Decimal)
+		scalar func(a, b *Decimal) *Decimal
+	}{
+		{"AddVec", AddVec, (*Decimal).Add},
+		{"SubVec", SubVec, (*Decimal).Subtract},
+		{"MulVec", MulVec, (*Decimal).Multiply},
+		{"DivVec", DivVec, (*Decimal).Divide},
+		{"AddScalarVec", func(dst, a, b []Decimal) {
+			for i := range dst {
+				AddScalarVec(dst[i:i+1], a[i:i+1], &b[i])
+			}
+		}, (*Decimal).Add},
+		{"MulScalarVec", func(dst, a, b []Decimal) {
+			for i := range dst {
+				MulScalarVec(dst[i:i+1], a[i:i+1], &b[i])
+			}
+		}, (*Decimal).Multiply},
+	}
+	for _, op := range ops {
+		for _, pair := range vectorPairs() {
+			a, b := pair[0], pair[1]
+			dst := make([]Decimal, len(a))
+			op.vec(dst, a, b)
+			for i := range dst {
+				if want := op.scalar(&a[i], &b[i]); !sameDecimal(&dst[i], want) {
+					t.Errorf("%s: %s and %s gave %s, want %s", op.name, a[i].ToString(), b[i].ToString(), dst[i].ToString(), want.ToString())
+				}
+			}
+			// dst may be one of the inputs
+			inPlace := append([]Decimal(nil), a...)
+			op.vec(inPlace, inPlace, b)
+			for i := range inPlace {
+				if !sameDecimal(&inPlace[i], &dst[i]) {
+					t.Errorf("%s in place: %s and %s gave %s, want %s", op.name, a[i].ToString(), b[i].ToString(), inPlace[i].ToString(), dst[i].ToString())
+				}
+			}
+		}
+	}
+}
+
+func TestScalarVecWithOneScalar(t *testing.T) {
+	a := vectorOf(1, -2.5, 1e308, "1e1000")
+	dst := make([]Decimal, len(a))
+	for _, s := range []*Decimal{D(3), D("1e500")} {
+		AddScalarVec(dst, a, s)
+		for i := range dst {
+			if want := a[i].Add(s); !sameDecimal(&dst[i], want) {
+				t.Errorf("AddScalarVec: %s + %s = %s, want %s", a[i].ToString(), s.ToString(), dst[i].ToString(), want.ToString())
+			}
+		}
+		MulScalarVec(dst, a, s)
+		for i := range dst {
+			if want := a[i].Multiply(s); !sameDecimal(&dst[i], want) {
+				t.Errorf("MulScalarVec: %s * %s = %s, want %s", a[i].ToString(), s.ToString(), dst[i].ToString(), want.ToString())
+			}
+		}
+	}
+}
+
+func TestClampAndCmpVec(t *testing.T) {
+	a := vectorOf(-5, 0, 5, 50, "1e1000", "-1e400", math.Inf(1))
+	b := vectorOf(-5, 1, 4, "1e100", "1e1000", "-1e500", math.Inf(1))
+	dst := make([]Decimal, len(a))
+	ClampVec(dst, a, D(0), D(10))
+	for i, want := range []float64{0, 0, 5, 10, 10, 0, 10} {
+		if dst[i].Neq(D(want)) {
+			t.Errorf("ClampVec: %s clamped to [0, 10] = %s, want %v", a[i].ToString(), dst[i].ToString(), want)
+		}
+	}
+	cmp := make([]int, len(a))
+	CmpVec(cmp, a, b)
+	for i, want := range []int{0, -1, 1, -1, 0, 1, 0} {
+		if cmp[i] != want {
+			t.Errorf("CmpVec: %s vs %s = %d, want %d", a[i].ToString(), b[i].ToString(), cmp[i], want)
+		}
+	}
+}
+
+func TestSumMaxMinVec(t *testing.T) {
+	cases := []struct {
+		values        []Decimal
+		sum, max, min *Decimal
+	}{
+		{vectorOf(1, 2, 3.5), D(6.5), D(3.5), D(1)},
+		{vectorOf(-1, "1e1000", 5), D("1e1000"), D("1e1000"), D(-1)},
+		// The running sum leaves layer 0 and comes back
+		{vectorOf(6e15, 6e15, -6e15), D(6e15), D(6e15), D(-6e15)},
+		{vectorOf("ee50", "-ee50", 7), D(7), D("ee50"), D("-ee50")},
+		{vectorOf(), D(0), nil, nil},
+	}
+	for _, c := range cases {
+		if got := SumVec(c.values); !got.EqTolerance(c.sum, 1e-15) {
+			t.Errorf("SumVec(%v) = %s, want %s", c.values, got.ToString(), c.sum.ToString())
+		}
+		if c.max == nil {
+			if !MaxVec(c.values).IsNaN() || !MinVec(c.values).IsNaN() {
+				t.Errorf("MaxVec and MinVec of an empty vector = %s and %s, want NaN", MaxVec(c.values).ToString(), MinVec(c.values).ToString())
+			}
+			continue
+		}
+		if got := MaxVec(c.values); got.Neq(c.max) {
+			t.Errorf("MaxVec(%v) = %s, want %s", c.values, got.ToString(), c.max.ToString())
+		}
+		if got := MinVec(c.values); got.Neq(c.min) {
+			t.Errorf("MinVec(%v) = %s, want %s", c.values, got.ToString(), c.min.ToString())
+		}
+	}
+}
+
+func TestVecLengthMismatchPanics(t *testing.T) {
+	short, long := make([]Decimal, 2), make([]Decimal, 3)
+	calls := map[string]func(){
+		"AddVec":       func() { AddVec(long, long, short) },
+		"SubVec":       func() { SubVec(short, long, long) },
+		"MulVec":       func() { MulVec(long, short, long) },
+		"DivVec":       func() { DivVec(long, long, short) },
+		"AddScalarVec": func() { AddScalarVec(short, long, D(1)) },
+		"MulScalarVec": func() { MulScalarVec(long, short, D(1)) },
+		"ClampVec":     func() { ClampVec(short, long, D(0), D(1)) },
+		"CmpVec":       func() { CmpVec(make([]int, 2), long, long) },
+	}
+	for name, call := range calls {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s with mismatched lengths didn't panic", name)
+				}
+			}()
+			call()
+		}()
+	}
+}
+
+const benchVectorLength = 10000
+
+func benchVectors(layer1 bool) ([]Decimal, []Decimal, []Decimal) {
+	a := make([]Decimal, benchVectorLength)
+	b := make([]Decimal, benchVectorLength)
+	dst := make([]Decimal, benchVectorLength)
+	for i := range a {
+		a[i] = *D(float64(i) + 1.5)
+		b[i] = *D(float64(i%100) * 0.25)
+		if layer1 && i%10 == 0 {
+			a[i] = *D("1e1000")
+		}
+	}
+	return dst, a, b
+}
+
+func BenchmarkAddVec(b *testing.B) {
+	dst, x, y := benchVectors(false)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		AddVec(dst, x, y)
+	}
+}
+
+func BenchmarkAddScalar(b *testing.B) {
+	dst, x, y := benchVectors(false)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range dst {
+			dst[i] = *x[i].Add(&y[i])
+		}
+	}
+}
+
+func BenchmarkAddVecMixedLayers(b *testing.B) {
+	dst, x, y := benchVectors(true)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		AddVec(dst, x, y)
+	}
+}
+
+func BenchmarkAddScalarMixedLayers(b *testing.B) {
+	dst, x, y := benchVectors(true)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range dst {
+			dst[i] = *x[i].Add(&y[i])
+		}
+	}
+}
+
+func BenchmarkMulScalarVec(b *testing.B) {
+	dst, x, _ := benchVectors(false)
+	rate := D(1.01)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		MulScalarVec(dst, x, rate)
+	}
+}
+
+func BenchmarkMulScalarScalar(b *testing.B) {
+	dst, x, _ := benchVectors(false)
+	rate := D(1.01)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range dst {
+			dst[i] = *x[i].Multiply(rate)
+		}
+	}
+}
+
+func BenchmarkClampVec(b *testing.B) {
+	dst, x, _ := benchVectors(false)
+	min, max := D(10), D(5000)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ClampVec(dst, x, min, max)
+	}
+}
+
+func BenchmarkClampScalar(b *testing.B) {
+	dst, x, _ := benchVectors(false)
+	min, max := D(10), D(5000)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range dst {
+			dst[i] = *x[i].Clamp(min, max)
+		}
+	}
+}
+
+func BenchmarkSumVec(b *testing.B) {
+	_, x, _ := benchVectors(false)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		SumVec(x)
+	}
+}
+
+func BenchmarkSumScalar(b *testing.B) {
+	_, x, _ := benchVectors(false)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		sum := D(0)
+		for i := range x {
+			sum = sum.Add(&x[i])
+		}
+	}
+}
+
+func BenchmarkMaxVec(b *testing.B) {
+	_, x, _ := benchVectors(true)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		MaxVec(x)
+	}
+}
+
+func BenchmarkCmpVec(b *testing.B) {
+	_, x, y := benchVectors(true)
+	dst := make([]int, benchVectorLength)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		CmpVec(dst, x, y)
+	}
+}